@@ -0,0 +1,241 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/subtle"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gigo-ws/config"
+	"gigo-ws/utils"
+)
+
+var (
+	ErrAuthenticationFailed = fmt.Errorf("authentication failed")
+)
+
+// defaultHmacMaxSkew is the maximum age of a signed timestamp used
+// when the configuration does not specify one
+const defaultHmacMaxSkew = time.Minute * 5
+
+// maxHmacNonceLength limits the size of the nonces held by the replay cache
+const maxHmacNonceLength = 128
+
+// ClientIdentity
+//
+//	Identity of an authenticated caller of the api
+type ClientIdentity struct {
+	ID string
+}
+
+// Authenticator
+//
+//	Authenticates the caller of an rpc from the metadata sent with the call
+type Authenticator interface {
+	// Authenticate
+	//
+	//	Returns the identity of the caller or ErrAuthenticationFailed
+	//	if the caller could not be authenticated. The body is the encoded
+	//	request message and is only read for signed calls.
+	Authenticate(ctx context.Context, rpc string, metadata map[string]string, body []byte) (*ClientIdentity, error)
+}
+
+// Authorizer
+//
+//	Determines whether an authenticated caller is permitted to call an rpc
+type Authorizer interface {
+	// Authorize
+	//
+	//	Returns true if the identity is permitted to call the rpc
+	Authorize(identity *ClientIdentity, rpc string) bool
+}
+
+type configAuthClient struct {
+	config.AuthClientConfig
+	allowAll    bool
+	allowedRPCs map[string]bool
+}
+
+// ConfigAuth
+//
+//	Authenticator and Authorizer backed by the clients
+//	declared in the server's auth configuration
+type ConfigAuth struct {
+	clients map[string]*configAuthClient
+	maxSkew time.Duration
+	// nonces of the accepted hmac signatures mapped to the time that
+	// their timestamp leaves the skew window and they can be forgotten
+	nonces     map[string]time.Time
+	noncesLock sync.Mutex
+}
+
+// NewConfigAuth
+//
+//	Creates a new ConfigAuth from the server's auth configuration
+func NewConfigAuth(cfg config.AuthConfig) (*ConfigAuth, error) {
+	a := &ConfigAuth{
+		clients: make(map[string]*configAuthClient),
+		maxSkew: defaultHmacMaxSkew,
+		nonces:  make(map[string]time.Time),
+	}
+	if cfg.HmacMaxSkew > 0 {
+		a.maxSkew = time.Duration(cfg.HmacMaxSkew) * time.Second
+	}
+
+	for _, c := range cfg.Clients {
+		if c.ID == "" {
+			return nil, fmt.Errorf("auth client missing id")
+		}
+		if _, ok := a.clients[c.ID]; ok {
+			return nil, fmt.Errorf("duplicate auth client %q", c.ID)
+		}
+		if c.Token == "" && c.HmacSecret == "" && c.CertCommonName == "" {
+			return nil, fmt.Errorf("auth client %q has no token, hmac secret or certificate common name", c.ID)
+		}
+
+		client := &configAuthClient{
+			AuthClientConfig: c,
+			allowedRPCs:      make(map[string]bool),
+		}
+		for _, rpc := range c.AllowedRPCs {
+			if rpc == "*" {
+				client.allowAll = true
+				continue
+			}
+			client.allowedRPCs[rpc] = true
+		}
+		a.clients[c.ID] = client
+	}
+
+	return a, nil
+}
+
+// Authenticate
+//
+//	Authenticates the caller using either a bearer token in the authorization
+//	metadata, an hmac signature of the rpc, request body, a recent timestamp
+//	and a unique nonce or the common name of a verified mTLS client certificate
+func (a *ConfigAuth) Authenticate(ctx context.Context, rpc string, metadata map[string]string, body []byte) (*ClientIdentity, error) {
+	// handle bearer token authentication
+	if authorization, ok := metadata[utils.AuthMetadataAuthorization]; ok {
+		token, ok := strings.CutPrefix(authorization, "Bearer ")
+		if !ok || token == "" {
+			return nil, ErrAuthenticationFailed
+		}
+
+		// compare against every client so that the time taken
+		// does not reveal which client matched
+		var identity *ClientIdentity
+		for _, c := range a.clients {
+			if c.Token == "" {
+				continue
+			}
+			if subtle.ConstantTimeCompare([]byte(c.Token), []byte(token)) == 1 {
+				identity = &ClientIdentity{ID: c.ID}
+			}
+		}
+		if identity == nil {
+			return nil, ErrAuthenticationFailed
+		}
+		return identity, nil
+	}
+
+	// handle mtls authentication when no hmac signature was sent
+	clientId := metadata[utils.AuthMetadataClientID]
+	timestamp := metadata[utils.AuthMetadataTimestamp]
+	nonce := metadata[utils.AuthMetadataNonce]
+	signature := metadata[utils.AuthMetadataSignature]
+	if clientId == "" && timestamp == "" && nonce == "" && signature == "" {
+		tlsIdentity, ok := ctx.Value("tls_identity").(*TLSIdentity)
+		if !ok || tlsIdentity == nil || tlsIdentity.CommonName == "" {
+			return nil, ErrAuthenticationFailed
+		}
+		for _, c := range a.clients {
+			if c.CertCommonName != "" && c.CertCommonName == tlsIdentity.CommonName {
+				return &ClientIdentity{ID: c.ID}, nil
+			}
+		}
+		return nil, ErrAuthenticationFailed
+	}
+
+	// handle hmac authentication
+	if clientId == "" || timestamp == "" || nonce == "" || len(nonce) > maxHmacNonceLength || signature == "" {
+		return nil, ErrAuthenticationFailed
+	}
+
+	client, ok := a.clients[clientId]
+	if !ok || client.HmacSecret == "" {
+		return nil, ErrAuthenticationFailed
+	}
+
+	// reject timestamps outside the permitted skew to limit replays
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrAuthenticationFailed
+	}
+	skew := time.Since(time.Unix(unix, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > a.maxSkew {
+		return nil, ErrAuthenticationFailed
+	}
+
+	expected := utils.SignRPC(client.HmacSecret, rpc, timestamp, nonce, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, ErrAuthenticationFailed
+	}
+
+	// reject signatures that were already used within the skew window
+	if !a.useNonce(client.ID, nonce, time.Unix(unix, 0).Add(a.maxSkew)) {
+		return nil, ErrAuthenticationFailed
+	}
+
+	return &ClientIdentity{ID: client.ID}, nil
+}
+
+// useNonce
+//
+//	Records the nonce of a client until the passed expiration. Returns
+//	false if the nonce was already recorded. Expired nonces are pruned
+//	since their timestamps are rejected by the skew check anyway.
+func (a *ConfigAuth) useNonce(clientId string, nonce string, expiration time.Time) bool {
+	a.noncesLock.Lock()
+	defer a.noncesLock.Unlock()
+
+	now := time.Now()
+	for k, exp := range a.nonces {
+		if exp.Before(now) {
+			delete(a.nonces, k)
+		}
+	}
+
+	key := clientId + "\n" + nonce
+	if _, ok := a.nonces[key]; ok {
+		return false
+	}
+	a.nonces[key] = expiration
+	return true
+}
+
+// Authorize
+//
+//	Returns true if the client's configured rules permit the rpc. Rules
+//	are matched against the method name of the rpc e.g. DestroyWorkspace
+func (a *ConfigAuth) Authorize(identity *ClientIdentity, rpc string) bool {
+	if identity == nil {
+		return false
+	}
+
+	client, ok := a.clients[identity.ID]
+	if !ok {
+		return false
+	}
+
+	return client.allowAll || client.allowedRPCs[path.Base(rpc)]
+}
@@ -0,0 +1,436 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gigo-ws/config"
+	"gigo-ws/protos/ws"
+	"gigo-ws/utils"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/gage-technologies/gigo-lib/logging"
+	"google.golang.org/protobuf/proto"
+	"storj.io/drpc"
+	"storj.io/drpc/drpcmetadata"
+)
+
+type testStream struct {
+	ctx  context.Context
+	sent []drpc.Message
+}
+
+func (s *testStream) Context() context.Context { return s.ctx }
+func (s *testStream) MsgSend(msg drpc.Message, _ drpc.Encoding) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+func (s *testStream) MsgRecv(drpc.Message, drpc.Encoding) error { return fmt.Errorf("not implemented") }
+func (s *testStream) CloseSend() error                          { return nil }
+func (s *testStream) Close() error                              { return nil }
+
+// rawTestStream test stream that receives an encoded request
+type rawTestStream struct {
+	testStream
+	body []byte
+}
+
+func (s *rawTestStream) RawRecv() ([]byte, error) { return s.body, nil }
+
+type testEncoding struct{}
+
+func (testEncoding) Marshal(msg drpc.Message) ([]byte, error) {
+	return proto.Marshal(msg.(proto.Message))
+}
+func (testEncoding) Unmarshal(buf []byte, msg drpc.Message) error {
+	return proto.Unmarshal(buf, msg.(proto.Message))
+}
+
+type testHandler struct {
+	called bool
+	// recv optional message received from the stream when the handler is called
+	recv drpc.Message
+}
+
+func (h *testHandler) HandleRPC(stream drpc.Stream, _ string) error {
+	h.called = true
+	if h.recv != nil {
+		return stream.MsgRecv(h.recv, testEncoding{})
+	}
+	return nil
+}
+
+func newTestConfigAuth(t *testing.T) *ConfigAuth {
+	auth, err := NewConfigAuth(config.AuthConfig{
+		Enabled: true,
+		Clients: []config.AuthClientConfig{
+			{
+				ID:          "core",
+				Token:       "core-token",
+				HmacSecret:  "core-secret",
+				AllowedRPCs: []string{"*"},
+			},
+			{
+				ID:          "dashboard",
+				Token:       "dashboard-token",
+				AllowedRPCs: []string{"GetWorkspace", "ListWorkspaces"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth
+}
+
+func TestConfigAuth_Authenticate(t *testing.T) {
+	auth := newTestConfigAuth(t)
+
+	rpc := "/ws.GigoWS/DestroyWorkspace"
+	now := fmt.Sprintf("%d", time.Now().Unix())
+	stale := fmt.Sprintf("%d", time.Now().Add(-time.Hour).Unix())
+	body := []byte("destroy 1")
+
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		id       string
+	}{
+		{
+			name:     "bearer",
+			metadata: map[string]string{utils.AuthMetadataAuthorization: "Bearer dashboard-token"},
+			id:       "dashboard",
+		},
+		{
+			name:     "bad bearer",
+			metadata: map[string]string{utils.AuthMetadataAuthorization: "Bearer nope"},
+		},
+		{
+			name:     "missing bearer prefix",
+			metadata: map[string]string{utils.AuthMetadataAuthorization: "core-token"},
+		},
+		{
+			name: "hmac",
+			metadata: map[string]string{
+				utils.AuthMetadataClientID:  "core",
+				utils.AuthMetadataTimestamp: now,
+				utils.AuthMetadataNonce:     "nonce-1",
+				utils.AuthMetadataSignature: utils.SignRPC("core-secret", rpc, now, "nonce-1", body),
+			},
+			id: "core",
+		},
+		{
+			name: "hmac replayed nonce",
+			metadata: map[string]string{
+				utils.AuthMetadataClientID:  "core",
+				utils.AuthMetadataTimestamp: now,
+				utils.AuthMetadataNonce:     "nonce-1",
+				utils.AuthMetadataSignature: utils.SignRPC("core-secret", rpc, now, "nonce-1", body),
+			},
+		},
+		{
+			name: "hmac missing nonce",
+			metadata: map[string]string{
+				utils.AuthMetadataClientID:  "core",
+				utils.AuthMetadataTimestamp: now,
+				utils.AuthMetadataSignature: utils.SignRPC("core-secret", rpc, now, "", body),
+			},
+		},
+		{
+			name: "hmac wrong body",
+			metadata: map[string]string{
+				utils.AuthMetadataClientID:  "core",
+				utils.AuthMetadataTimestamp: now,
+				utils.AuthMetadataNonce:     "nonce-2",
+				utils.AuthMetadataSignature: utils.SignRPC("core-secret", rpc, now, "nonce-2", []byte("destroy 2")),
+			},
+		},
+		{
+			name: "hmac wrong rpc",
+			metadata: map[string]string{
+				utils.AuthMetadataClientID:  "core",
+				utils.AuthMetadataTimestamp: now,
+				utils.AuthMetadataNonce:     "nonce-3",
+				utils.AuthMetadataSignature: utils.SignRPC("core-secret", "/ws.GigoWS/Echo", now, "nonce-3", body),
+			},
+		},
+		{
+			name: "hmac stale timestamp",
+			metadata: map[string]string{
+				utils.AuthMetadataClientID:  "core",
+				utils.AuthMetadataTimestamp: stale,
+				utils.AuthMetadataNonce:     "nonce-4",
+				utils.AuthMetadataSignature: utils.SignRPC("core-secret", rpc, stale, "nonce-4", body),
+			},
+		},
+		{
+			name: "hmac client without secret",
+			metadata: map[string]string{
+				utils.AuthMetadataClientID:  "dashboard",
+				utils.AuthMetadataTimestamp: now,
+				utils.AuthMetadataNonce:     "nonce-5",
+				utils.AuthMetadataSignature: utils.SignRPC("", rpc, now, "nonce-5", body),
+			},
+		},
+		{
+			name:     "no credentials",
+			metadata: map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			identity, err := auth.Authenticate(context.TODO(), rpc, test.metadata, body)
+			if test.id == "" {
+				if !errors.Is(err, ErrAuthenticationFailed) {
+					t.Fatalf("expected authentication failure, got %v %+v", err, identity)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if identity.ID != test.id {
+				t.Fatalf("expected identity %s, got %s", test.id, identity.ID)
+			}
+		})
+	}
+}
+
+func TestConfigAuth_Authorize(t *testing.T) {
+	auth := newTestConfigAuth(t)
+
+	if !auth.Authorize(&ClientIdentity{ID: "core"}, "/ws.GigoWS/DestroyWorkspace") {
+		t.Fatal("expected core to be authorized")
+	}
+
+	if !auth.Authorize(&ClientIdentity{ID: "dashboard"}, "/ws.GigoWS/GetWorkspace") {
+		t.Fatal("expected dashboard to be authorized for GetWorkspace")
+	}
+
+	if auth.Authorize(&ClientIdentity{ID: "dashboard"}, "/ws.GigoWS/DestroyWorkspace") {
+		t.Fatal("expected dashboard to be unauthorized for DestroyWorkspace")
+	}
+
+	if auth.Authorize(&ClientIdentity{ID: "unknown"}, "/ws.GigoWS/GetWorkspace") {
+		t.Fatal("expected unknown client to be unauthorized")
+	}
+}
+
+func TestDrpcMiddleware_Auth(t *testing.T) {
+	logger, err := logging.CreateBasicLogger(logging.NewDefaultBasicLoggerOptions("/tmp/gigo-ws-mw-auth-test.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snowflakeNode, err := snowflake.NewNode(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth := newTestConfigAuth(t)
+	handler := &testHandler{}
+	mw, err := NewDrpcMiddleware(DrpcMiddlewareOptions{
+		WaitGroup:     &sync.WaitGroup{},
+		Handler:       handler,
+		Logger:        logger,
+		SnowflakeNode: snowflakeNode,
+		Description:   ws.DRPCGigoWSDescription{},
+		Authenticator: auth,
+		Authorizer:    auth,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		rpc    string
+		token  string
+		status ws.ResponseCode
+		out    proto.Message
+	}{
+		{
+			name:   "unauthenticated unary",
+			rpc:    "/ws.GigoWS/DestroyWorkspace",
+			token:  "nope",
+			status: ws.ResponseCode_FAILED_AUTHENTICATION,
+			out:    &ws.DestroyWorkspaceResponse{},
+		},
+		{
+			name:   "unauthorized unary",
+			rpc:    "/ws.GigoWS/DestroyWorkspace",
+			token:  "dashboard-token",
+			status: ws.ResponseCode_FAILED_AUTHORIZATION,
+			out:    &ws.DestroyWorkspaceResponse{},
+		},
+		{
+			name:   "unauthenticated stream",
+			rpc:    "/ws.GigoWS/CreateWorkspaceStream",
+			token:  "nope",
+			status: ws.ResponseCode_FAILED_AUTHENTICATION,
+			out:    &ws.CreateWorkspaceStreamResponse{},
+		},
+		{
+			name:   "authorized",
+			rpc:    "/ws.GigoWS/GetWorkspace",
+			token:  "dashboard-token",
+			status: ws.ResponseCode_SUCCESS,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler.called = false
+			stream := &testStream{
+				ctx: drpcmetadata.Add(context.TODO(), utils.AuthMetadataAuthorization, "Bearer "+test.token),
+			}
+
+			err := mw.HandleRPC(stream, test.rpc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.status == ws.ResponseCode_SUCCESS {
+				if !handler.called {
+					t.Fatal("expected handler to be called")
+				}
+				return
+			}
+
+			if handler.called {
+				t.Fatal("expected handler not to be called")
+			}
+
+			if len(stream.sent) != 1 {
+				t.Fatalf("expected 1 response, got %d", len(stream.sent))
+			}
+
+			res, ok := stream.sent[0].(proto.Message)
+			if !ok || res.ProtoReflect().Descriptor().FullName() != test.out.ProtoReflect().Descriptor().FullName() {
+				t.Fatalf("unexpected response type %T", stream.sent[0])
+			}
+
+			status := res.ProtoReflect().Get(res.ProtoReflect().Descriptor().Fields().ByName("status")).Enum()
+			if ws.ResponseCode(status) != test.status {
+				t.Fatalf("expected status %s, got %s", test.status, ws.ResponseCode(status))
+			}
+		})
+	}
+}
+
+func TestConfigAuth_AuthenticateTLS(t *testing.T) {
+	auth, err := NewConfigAuth(config.AuthConfig{
+		Enabled: true,
+		Clients: []config.AuthClientConfig{
+			{
+				ID:             "core",
+				CertCommonName: "gigo-core",
+				AllowedRPCs:    []string{"*"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.TODO(), "tls_identity", &TLSIdentity{CommonName: "gigo-core"})
+	identity, err := auth.Authenticate(ctx, "/ws.GigoWS/Echo", map[string]string{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if identity.ID != "core" {
+		t.Fatalf("expected identity core, got %s", identity.ID)
+	}
+
+	ctx = context.WithValue(context.TODO(), "tls_identity", &TLSIdentity{CommonName: "other"})
+	_, err = auth.Authenticate(ctx, "/ws.GigoWS/Echo", map[string]string{}, nil)
+	if !errors.Is(err, ErrAuthenticationFailed) {
+		t.Fatalf("expected authentication failure, got %v", err)
+	}
+}
+
+func TestDrpcMiddleware_SignedRequest(t *testing.T) {
+	logger, err := logging.CreateBasicLogger(logging.NewDefaultBasicLoggerOptions("/tmp/gigo-ws-mw-auth-test.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snowflakeNode, err := snowflake.NewNode(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth := newTestConfigAuth(t)
+	handler := &testHandler{}
+	mw, err := NewDrpcMiddleware(DrpcMiddlewareOptions{
+		WaitGroup:     &sync.WaitGroup{},
+		Handler:       handler,
+		Logger:        logger,
+		SnowflakeNode: snowflakeNode,
+		Description:   ws.DRPCGigoWSDescription{},
+		Authenticator: auth,
+		Authorizer:    auth,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rpc := "/ws.GigoWS/DestroyWorkspace"
+	signed, err := proto.Marshal(&ws.DestroyWorkspaceRequest{WorkspaceId: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := proto.Marshal(&ws.DestroyWorkspaceRequest{WorkspaceId: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newStream := func(nonce string, body []byte) *rawTestStream {
+		now := fmt.Sprintf("%d", time.Now().Unix())
+		return &rawTestStream{
+			testStream: testStream{
+				ctx: drpcmetadata.AddPairs(context.TODO(), map[string]string{
+					utils.AuthMetadataClientID:  "core",
+					utils.AuthMetadataTimestamp: now,
+					utils.AuthMetadataNonce:     nonce,
+					utils.AuthMetadataSignature: utils.SignRPC("core-secret", rpc, now, nonce, signed),
+				}),
+			},
+			body: body,
+		}
+	}
+
+	// the handler receives the message that was read to verify the signature
+	req := &ws.DestroyWorkspaceRequest{}
+	handler.recv = req
+	err = mw.HandleRPC(newStream("nonce-1", signed), rpc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !handler.called || req.GetWorkspaceId() != 1 {
+		t.Fatalf("expected handler to receive the signed request, got %+v", req)
+	}
+
+	// the signature cannot be used with another request or replayed
+	for _, stream := range []*rawTestStream{newStream("nonce-2", other), newStream("nonce-1", signed)} {
+		handler.called = false
+		err = mw.HandleRPC(stream, rpc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if handler.called {
+			t.Fatal("expected handler not to be called")
+		}
+		res, ok := stream.sent[0].(*ws.DestroyWorkspaceResponse)
+		if !ok || res.GetStatus() != ws.ResponseCode_FAILED_AUTHENTICATION {
+			t.Fatalf("expected authentication failure, got %+v", stream.sent[0])
+		}
+	}
+}
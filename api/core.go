@@ -5,24 +5,39 @@ import (
 	"embed"
 	"fmt"
 	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"gigo-ws/config"
 	"gigo-ws/models"
 	"gigo-ws/provisioner"
+	"gigo-ws/provisioner/backend"
+	"gigo-ws/provisioner/tfevent"
 	"gigo-ws/volpool"
 
 	"github.com/gage-technologies/gigo-lib/logging"
 	"github.com/gage-technologies/gigo-lib/storage"
+	"github.com/hashicorp/go-version"
+	tfjson "github.com/hashicorp/terraform-json"
 )
 
 //go:embed resources
 var embedFS embed.FS
 
 var (
-	ErrWorkspaceNotFound = fmt.Errorf("workspace not found")
+	ErrWorkspaceNotFound         = fmt.Errorf("workspace not found")
+	ErrInvalidWorkspaceResources = fmt.Errorf("invalid workspace resources")
+	ErrStateNotLocked            = fmt.Errorf("workspace state is not locked")
 )
 
+// claimNameRegex matches the literal claim name of a volume
+// mounted by a workspace template
+var claimNameRegex = regexp.MustCompile(`claim_name\s*=\s*"([^"$]+)"`)
+
 const hostAliasesTemplate = `
     host_aliases {
       ip = "%s"
@@ -50,6 +65,9 @@ type createWorkspaceOptions struct {
 	RegistryCaches  []config.RegistryCacheConfig
 	WsHostOverrides map[string]string
 	Logger          logging.Logger
+	// Events optional channel to receive the terraform
+	// events of the apply operation as they occur
+	Events chan<- tfevent.Event
 }
 
 type startWorkspaceOptions struct {
@@ -57,6 +75,9 @@ type startWorkspaceOptions struct {
 	StorageEngine storage.Storage
 	Logger        logging.Logger
 	WorkspaceID   int64
+	// Events optional channel to receive the terraform
+	// events of the apply operation as they occur
+	Events chan<- tfevent.Event
 }
 
 type stopWorkspaceOptions struct {
@@ -66,6 +87,61 @@ type stopWorkspaceOptions struct {
 	WorkspaceID   int64
 }
 
+type updateWorkspaceResourcesOptions struct {
+	Provisioner   *provisioner.Provisioner
+	StorageEngine storage.Storage
+	Volpool       *volpool.VolumePool
+	Logger        logging.Logger
+	WorkspaceID   int64
+	Resources     models.WorkspaceResources
+}
+
+type planWorkspaceOptions struct {
+	Provisioner   *provisioner.Provisioner
+	StorageEngine storage.Storage
+	Logger        logging.Logger
+	WorkspaceID   int64
+	Operation     models.JobOperation
+	// TemplateOpts parameters of the workspace - only used when planning a create
+	TemplateOpts    templateOptions
+	RegistryCaches  []config.RegistryCacheConfig
+	WsHostOverrides map[string]string
+}
+
+type getWorkspaceOptions struct {
+	Provisioner   *provisioner.Provisioner
+	StorageEngine storage.Storage
+	WorkspaceID   int64
+}
+
+// workspaceStatus
+//
+//	Read-only view of a workspace assembled from the
+//	statefile and the stored module of the workspace
+type workspaceStatus struct {
+	State     models.WorkspaceState
+	AgentID   int64
+	PVCName   string
+	PodName   string
+	Resources *models.WorkspaceResources
+	// TerraformVersion version of terraform that the module is pinned to
+	TerraformVersion string
+}
+
+type listWorkspacesOptions struct {
+	Provisioner   *provisioner.Provisioner
+	StorageEngine storage.Storage
+	Cursor        int64
+	Limit         int
+	States        []models.WorkspaceState
+}
+
+type workspaceSummary struct {
+	WorkspaceID  int64
+	State        models.WorkspaceState
+	LastModified time.Time
+}
+
 type destroyWorkspaceOptions struct {
 	Provisioner   *provisioner.Provisioner
 	Volpool       *volpool.VolumePool
@@ -74,6 +150,28 @@ type destroyWorkspaceOptions struct {
 	WorkspaceID   int64
 }
 
+type upgradeWorkspaceOptions struct {
+	Provisioner   *provisioner.Provisioner
+	StorageEngine storage.Storage
+	Logger        logging.Logger
+	WorkspaceID   int64
+	Version       *version.Version
+}
+
+type forceUnlockOptions struct {
+	Provisioner *provisioner.Provisioner
+	Logger      logging.Logger
+	WorkspaceID int64
+	LockID      string
+}
+
+type restoreStatefileOptions struct {
+	Provisioner *provisioner.Provisioner
+	Logger      logging.Logger
+	WorkspaceID int64
+	VersionID   string
+}
+
 func createWorkspace(ctx context.Context, opts createWorkspaceOptions) (*models.Agent, *provisioner.ApplyLogs, error) {
 	// retrieve the current state from statefile
 	state, err := provisioner.ParseStatefileForWorkspaceState(opts.Provisioner.Backend, opts.TemplateOpts.WorkspaceID)
@@ -96,44 +194,21 @@ func createWorkspace(ctx context.Context, opts createWorkspaceOptions) (*models.
 		return nil, nil, fmt.Errorf("failed to retrieve volume: %v", err)
 	}
 
-	// select the template disk size if the volume is nil
-	var templateBuf []byte
-	if vol == nil {
-		// read template from storage
-		templateBuf, err = embedFS.ReadFile("resources/template_vol.tf")
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to read tf template: %v", err)
-		}
-	} else {
-		// read template from storage
-		templateBuf, err = embedFS.ReadFile("resources/template_novol.tf")
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to read tf template: %v", err)
-		}
-
-		// inject the volume name into the template
-		templateBuf = []byte(strings.ReplaceAll(string(templateBuf), "<VOL_PVC_NAME>", fmt.Sprintf("%s", vol.PVCName)))
+	// select the template using the pvc of the pooled volume if one was claimed
+	pvcName := ""
+	if vol != nil {
+		pvcName = vol.PVCName
 	}
 
-	// conditionally inject host aliases into template
-	if len(opts.WsHostOverrides) > 0 {
-		hostAliases := ""
-		for host, ip := range opts.WsHostOverrides {
-			hostAliases += fmt.Sprintf(hostAliasesTemplate, ip, host)
-		}
-		templateBuf = []byte(strings.ReplaceAll(string(templateBuf), "<HOST_ALIASES>", hostAliases))
-	}
-
-	// update the container with registry caching if it is configured
-	opts.TemplateOpts.Container = handleRegistryCaches(opts.TemplateOpts.Container, opts.RegistryCaches)
-
 	// format module with terraform template
-	module := &models.TerraformModule{
-		MainTF:      templateBuf,
-		ModuleID:    opts.TemplateOpts.WorkspaceID,
-		Environment: prepEnvironmentForCreation(opts.TemplateOpts),
+	module, err := prepModuleForCreation(opts.TemplateOpts, pvcName, opts.RegistryCaches, opts.WsHostOverrides)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	// pin the module to the current terraform version for all later operations
+	module.TerraformVersion = opts.Provisioner.DefaultVersion()
+
 	// create boolean to track failure
 	failed := true
 
@@ -143,8 +218,9 @@ func createWorkspace(ctx context.Context, opts createWorkspaceOptions) (*models.
 		if failed {
 			// use a new context here since we don't want this interrupted by
 			// drpc api call context - the api context could be cancelled
-			// mid-operation but we want this to complete async
-			_, err := opts.Provisioner.Destroy(context.TODO(), module)
+			// mid-operation but we want this to complete async - the cleanup
+			// must not be rejected by a full queue or the resources are orphaned
+			_, err := opts.Provisioner.Destroy(provisioner.WithGuaranteedSlot(context.TODO()), module)
 			if err != nil {
 				opts.Logger.Error(fmt.Errorf("failed to destroy workspace on create cleanup: %v", err))
 			}
@@ -166,16 +242,21 @@ func createWorkspace(ctx context.Context, opts createWorkspaceOptions) (*models.
 		return
 	}()
 
-	// perform apply operation
-	logs, err := opts.Provisioner.Apply(ctx, module)
+	// perform apply operation streaming the events if the caller requested them
+	var logs *provisioner.ApplyLogs
+	if opts.Events != nil {
+		logs, err = opts.Provisioner.ApplyStream(ctx, module, opts.Events)
+	} else {
+		logs, err = opts.Provisioner.Apply(ctx, module)
+	}
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to apply configuration: %v", err)
+		return nil, nil, fmt.Errorf("failed to apply configuration: %w", err)
 	}
 
-	// retrieve agent from statefile
-	agent, err := provisioner.ParseStatefileForAgent(opts.Provisioner.Backend, opts.TemplateOpts.WorkspaceID)
+	// retrieve agent from the module outputs
+	agent, err := opts.Provisioner.Agent(ctx, module)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse agent from statefile: %v", err)
+		return nil, nil, fmt.Errorf("failed to retrieve agent: %w", err)
 	}
 
 	// preserve module for later operations
@@ -208,10 +289,12 @@ func startWorkspace(ctx context.Context, opts startWorkspaceOptions) (*models.Ag
 
 	// create dummy logs incase we don't do anything
 	logs := &provisioner.ApplyLogs{
-		StdOut: make([]map[string]interface{}, 0),
-		StdErr: make([]map[string]interface{}, 0),
+		Events: make([]tfevent.Event, 0),
 	}
 
+	// agent is retrieved from the module outputs if we apply the module
+	var agent *models.Agent
+
 	// only perform the operation if we are stopped
 	if state == models.WorkspaceStateStopped {
 		// load module using the workspace id
@@ -237,20 +320,32 @@ func startWorkspace(ctx context.Context, opts startWorkspaceOptions) (*models.Ag
 			return
 		}()
 
-		// perform apply operation
-		logs, err = opts.Provisioner.Apply(ctx, module)
+		// perform apply operation streaming the events if the caller requested them
+		if opts.Events != nil {
+			logs, err = opts.Provisioner.ApplyStream(ctx, module, opts.Events)
+		} else {
+			logs, err = opts.Provisioner.Apply(ctx, module)
+		}
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to apply configuration: %v", err)
+			return nil, nil, fmt.Errorf("failed to apply configuration: %w", err)
 		}
 
 		// TODO: think long and hard about what a cleanup operation looks like for this
 		// do we stop the workspace, make a second attempt???
+
+		// retrieve agent from the module outputs
+		agent, err = opts.Provisioner.Agent(ctx, module)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to retrieve agent: %w", err)
+		}
 	}
 
-	// retrieve agent from statefile
-	agent, err := provisioner.ParseStatefileForAgent(opts.Provisioner.Backend, opts.WorkspaceID)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse agent from statefile: %v", err)
+	// retrieve agent from statefile if the workspace was already in the desired state
+	if agent == nil {
+		agent, err = provisioner.ParseStatefileForAgent(opts.Provisioner.Backend, opts.WorkspaceID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse agent from statefile: %v", err)
+		}
 	}
 
 	// return apply logs
@@ -274,10 +369,12 @@ func stopWorkspace(ctx context.Context, opts stopWorkspaceOptions) (*models.Agen
 
 	// create dummy logs incase we don't do anything
 	logs := &provisioner.ApplyLogs{
-		StdOut: make([]map[string]interface{}, 0),
-		StdErr: make([]map[string]interface{}, 0),
+		Events: make([]tfevent.Event, 0),
 	}
 
+	// agent is retrieved from the module outputs if we apply the module
+	var agent *models.Agent
+
 	// only perform the operation if we are active
 	if state == models.WorkspaceStateActive {
 		// load module using the workspace id
@@ -306,23 +403,123 @@ func stopWorkspace(ctx context.Context, opts stopWorkspaceOptions) (*models.Agen
 		// perform apply operation
 		logs, err = opts.Provisioner.Apply(ctx, module)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to apply configuration: %v", err)
+			return nil, nil, fmt.Errorf("failed to apply configuration: %w", err)
 		}
 
 		// TODO: think long and hard about what a cleanup operation looks like for this
 		// do we restart the workspace, make a second attempt???
+
+		// retrieve agent from the module outputs
+		agent, err = opts.Provisioner.Agent(ctx, module)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to retrieve agent: %w", err)
+		}
 	}
 
-	// retrieve agent from statefile
-	agent, err := provisioner.ParseStatefileForAgent(opts.Provisioner.Backend, opts.WorkspaceID)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse agent from statefile: %v", err)
+	// retrieve agent from statefile if the workspace was already in the desired state
+	if agent == nil {
+		agent, err = provisioner.ParseStatefileForAgent(opts.Provisioner.Backend, opts.WorkspaceID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse agent from statefile: %v", err)
+		}
 	}
 
 	// return apply logs
 	return agent, logs, nil
 }
 
+func updateWorkspaceResources(ctx context.Context, opts updateWorkspaceResourcesOptions) (*provisioner.ApplyLogs, error) {
+	// retrieve the current state from statefile
+	state, err := provisioner.ParseStatefileForWorkspaceState(opts.Provisioner.Backend, opts.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workspace state from statefile: %v", err)
+	}
+
+	// handle a destroyed workspace by returning an error
+	if state == models.WorkspaceStateDestroyed {
+		return nil, ErrWorkspaceNotFound
+	}
+
+	// load module using the workspace id
+	module, err := models.LoadModule(opts.StorageEngine, opts.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load module: %v", err)
+	}
+	if module == nil {
+		return nil, ErrWorkspaceNotFound
+	}
+
+	// retrieve the current sizing of the workspace
+	current, err := module.GetWorkspaceResources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workspace resources: %v", err)
+	}
+
+	// a persistent volume can be expanded but never shrunk
+	if opts.Resources.Disk < current.Disk {
+		return nil, fmt.Errorf("%w: disk cannot shrink from %dGi to %dGi", ErrInvalidWorkspaceResources, current.Disk, opts.Resources.Disk)
+	}
+
+	// volumes from the volume pool are referenced by a literal claim name
+	// and are expanded through the module of the volume by the pool
+	pooled := claimNameRegex.Match(module.MainTF)
+	if opts.Resources.Disk != current.Disk && pooled && opts.Volpool == nil {
+		return nil, fmt.Errorf("%w: disk of a pooled volume cannot be resized while the volume pool is disabled", ErrInvalidWorkspaceResources)
+	}
+
+	// create dummy logs incase we don't do anything
+	logs := &provisioner.ApplyLogs{
+		Events: make([]tfevent.Event, 0),
+	}
+
+	// skip the apply if the sizing is unchanged
+	if *current == opts.Resources {
+		return logs, nil
+	}
+
+	// expand the pooled volume before the workspace is applied with the new sizing
+	if opts.Resources.Disk != current.Disk && pooled {
+		err = opts.Volpool.ResizeWorkspaceVolume(ctx, opts.WorkspaceID, opts.Resources.Disk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resize pooled volume: %w", err)
+		}
+	}
+
+	// rewrite the resource sizing of the module
+	module.SetWorkspaceResources(opts.Resources)
+
+	// preserve the current state of the workspace - an active
+	// workspace has its pod replaced with the new sizing
+	if state == models.WorkspaceStateActive {
+		module.Environment = append(module.Environment, "GIGO_WORKSPACE_TRANSITION=start")
+	} else {
+		module.Environment = append(module.Environment, "GIGO_WORKSPACE_TRANSITION=stop")
+	}
+
+	defer func() {
+		// clean up the temporary module on fs
+		err := os.RemoveAll(module.LocalPath)
+		if err != nil {
+			opts.Logger.Error(fmt.Errorf("failed to clean up temporary module on resource update cleanup: %v", err))
+		}
+	}()
+
+	// perform apply operation
+	logs, err = opts.Provisioner.Apply(ctx, module)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply configuration: %w", err)
+	}
+
+	// store the module only once the new sizing was applied so that a failed
+	// or cancelled apply is not silently applied by a later start or stop
+	err = module.StoreModule(opts.StorageEngine)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store module: %v", err)
+	}
+
+	return logs, nil
+}
+
 func destroyWorkspace(ctx context.Context, opts destroyWorkspaceOptions) (*provisioner.DestroyLogs, error) {
 	// retrieve the current state from statefile
 	state, err := provisioner.ParseStatefileForWorkspaceState(opts.Provisioner.Backend, opts.WorkspaceID)
@@ -335,8 +532,7 @@ func destroyWorkspace(ctx context.Context, opts destroyWorkspaceOptions) (*provi
 		// ensure that the state file is removed incase it exists
 		_ = opts.Provisioner.Backend.RemoveStatefile(fmt.Sprintf("states/%d", opts.WorkspaceID))
 		return &provisioner.DestroyLogs{
-			StdOut: make([]map[string]interface{}, 0),
-			StdErr: make([]map[string]interface{}, 0),
+			Events: make([]tfevent.Event, 0),
 		}, ErrWorkspaceNotFound
 	}
 
@@ -366,7 +562,7 @@ func destroyWorkspace(ctx context.Context, opts destroyWorkspaceOptions) (*provi
 	// perform apply operation
 	logs, err := opts.Provisioner.Destroy(ctx, module)
 	if err != nil {
-		return nil, fmt.Errorf("failed to destroy configuration: %v", err)
+		return nil, fmt.Errorf("failed to destroy configuration: %w", err)
 	}
 
 	// clean up persistent state of the workspace
@@ -387,20 +583,376 @@ func destroyWorkspace(ctx context.Context, opts destroyWorkspaceOptions) (*provi
 		return nil, fmt.Errorf("failed to remove terraform statefiles: %v", err)
 	}
 
+	// delete the drift report since the scan only walks stored modules
+	err = deleteDriftReport(opts.StorageEngine, opts.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the logs are only kept to diagnose a workspace that still exists
+	err = opts.Provisioner.DeleteOperationLogs(opts.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
 	return logs, nil
 }
 
-func prepEnvironmentForCreation(opts templateOptions) []string {
-	// initialize environment with our current environment
-	// this is really important for k8s deployment because
-	// the k8s api server config is in the container's environment
-	// and we won't be able to provision unless we have access to
-	// that configuration within the provisioner commands
-	env := os.Environ()
+func planWorkspace(ctx context.Context, opts planWorkspaceOptions) (*tfjson.Plan, error) {
+	// retrieve the current state from statefile
+	state, err := provisioner.ParseStatefileForWorkspaceState(opts.Provisioner.Backend, opts.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workspace state from statefile: %v", err)
+	}
+
+	var module *models.TerraformModule
+	if opts.Operation == models.JobOperationCreate {
+		// a create can only be planned for a workspace that does not exist
+		if state != models.WorkspaceStateDestroyed {
+			return nil, fmt.Errorf("workspace has already been created")
+		}
+
+		// plan with a new volume since claiming a pooled volume would
+		// modify the pool - the remaining resources are identical
+		module, err = prepModuleForCreation(opts.TemplateOpts, "", opts.RegistryCaches, opts.WsHostOverrides)
+		if err != nil {
+			return nil, err
+		}
+		module.TerraformVersion = opts.Provisioner.DefaultVersion()
+	} else {
+		// handle a destroyed workspace by returning an error
+		if state == models.WorkspaceStateDestroyed {
+			return nil, ErrWorkspaceNotFound
+		}
+
+		// load module using the workspace id
+		module, err = models.LoadModule(opts.StorageEngine, opts.WorkspaceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load module: %v", err)
+		}
+		if module == nil {
+			return nil, ErrWorkspaceNotFound
+		}
 
+		// append the transition of the operation to module environment
+		switch opts.Operation {
+		case models.JobOperationStart:
+			module.Environment = append(module.Environment, "GIGO_WORKSPACE_TRANSITION=start")
+		case models.JobOperationStop:
+			module.Environment = append(module.Environment, "GIGO_WORKSPACE_TRANSITION=stop")
+		case models.JobOperationDestroy:
+			module.Environment = append(module.Environment, "GIGO_WORKSPACE_TRANSITION=destroy")
+		default:
+			return nil, fmt.Errorf("unsupported operation: %s", opts.Operation)
+		}
+	}
+
+	defer func() {
+		// clean up the temporary module on fs
+		err := os.RemoveAll(module.LocalPath)
+		if err != nil {
+			opts.Logger.Error(fmt.Errorf("failed to clean up temporary module on plan cleanup: %v", err))
+		}
+	}()
+
+	// perform plan operation
+	var plan *tfjson.Plan
+	switch opts.Operation {
+	case models.JobOperationDestroy:
+		plan, err = opts.Provisioner.PlanDestroy(ctx, module)
+	case models.JobOperationCreate:
+		plan, err = opts.Provisioner.PlanCreate(ctx, module)
+	default:
+		plan, err = opts.Provisioner.Plan(ctx, module)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan configuration: %w", err)
+	}
+
+	return plan, nil
+}
+
+func getWorkspace(ctx context.Context, opts getWorkspaceOptions) (*workspaceStatus, error) {
+	// retrieve the current state from statefile
+	state, err := provisioner.ParseStatefileForWorkspaceState(opts.Provisioner.Backend, opts.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workspace state from statefile: %v", err)
+	}
+
+	// handle a destroyed workspace by returning an error
+	if state == models.WorkspaceStateDestroyed {
+		return nil, ErrWorkspaceNotFound
+	}
+
+	// load module using the workspace id
+	module, err := models.LoadModule(opts.StorageEngine, opts.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load module: %v", err)
+	}
+	if module == nil {
+		return nil, ErrWorkspaceNotFound
+	}
+
+	// retrieve agent from statefile
+	agent, err := provisioner.ParseStatefileForAgent(opts.Provisioner.Backend, opts.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse agent from statefile: %v", err)
+	}
+
+	// retrieve the kubernetes resource names from the statefile
+	pvcName, podName, err := provisioner.ParseStatefileForKubernetesResources(opts.Provisioner.Backend, opts.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubernetes resources from statefile: %v", err)
+	}
+
+	// fallback on the volume claim injected into the module template
+	// since volpool volumes are not part of the workspace statefile
+	if pvcName == "" {
+		if match := claimNameRegex.FindSubmatch(module.MainTF); match != nil {
+			pvcName = string(match[1])
+		}
+	}
+
+	// retrieve the resource sizing from the module environment
+	resources, err := module.GetWorkspaceResources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve workspace resources: %v", err)
+	}
+
+	return &workspaceStatus{
+		State:            state,
+		AgentID:          agent.ID,
+		PVCName:          pvcName,
+		PodName:          podName,
+		Resources:        resources,
+		TerraformVersion: opts.Provisioner.ModuleVersion(module),
+	}, nil
+}
+
+func listWorkspaces(ctx context.Context, opts listWorkspacesOptions) ([]workspaceSummary, int64, error) {
+	// list the statefiles in the provisioner backend
+	statefiles, err := opts.Provisioner.Backend.List("states")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list statefiles: %v", err)
+	}
+
+	// list the modules in the storage engine
+	modules, err := opts.StorageEngine.ListDir("modules", false)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list modules: %v", err)
+	}
+
+	// collect the union of workspace ids that have either a statefile or a
+	// module mapped to the last modification time of the statefile
+	lastModified := make(map[int64]time.Time)
+	for _, s := range statefiles {
+		id, err := strconv.ParseInt(path.Base(s.BucketPath), 10, 64)
+		if err != nil {
+			continue
+		}
+		lastModified[id] = s.LastModified
+	}
+	for _, m := range modules {
+		id, err := strconv.ParseInt(path.Base(m), 10, 64)
+		if err != nil {
+			continue
+		}
+		if _, ok := lastModified[id]; !ok {
+			lastModified[id] = time.Time{}
+		}
+	}
+
+	// sort the ids so that the cursor is stable across pages
+	ids := make([]int64, 0, len(lastModified))
+	for id := range lastModified {
+		if id > opts.Cursor {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	// create a set of the states to filter by
+	filter := make(map[models.WorkspaceState]bool)
+	for _, state := range opts.States {
+		filter[state] = true
+	}
+
+	workspaces := make([]workspaceSummary, 0)
+	for i, id := range ids {
+		// exit if the context was cancelled
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+
+		// retrieve the current state from statefile
+		state, err := provisioner.ParseStatefileForWorkspaceState(opts.Provisioner.Backend, id)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to parse workspace state from statefile for %d: %v", id, err)
+		}
+
+		// skip workspaces that are filtered out
+		if len(filter) > 0 && !filter[state] {
+			continue
+		}
+
+		workspaces = append(workspaces, workspaceSummary{
+			WorkspaceID:  id,
+			State:        state,
+			LastModified: lastModified[id],
+		})
+
+		// return the cursor for the next page once the page is full
+		if len(workspaces) == opts.Limit {
+			if i < len(ids)-1 {
+				return workspaces, id, nil
+			}
+			break
+		}
+	}
+
+	return workspaces, 0, nil
+}
+
+// upgradeWorkspace
+//
+//	Upgrades the terraform version that the workspace module is pinned to
+//	and returns the version that the module was pinned to previously
+func upgradeWorkspace(ctx context.Context, opts upgradeWorkspaceOptions) (string, error) {
+	// retrieve the current state from statefile
+	state, err := provisioner.ParseStatefileForWorkspaceState(opts.Provisioner.Backend, opts.WorkspaceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse workspace state from statefile: %v", err)
+	}
+
+	// handle a destroyed workspace by returning an error
+	if state == models.WorkspaceStateDestroyed {
+		return "", ErrWorkspaceNotFound
+	}
+
+	// load module using the workspace id
+	module, err := models.LoadModule(opts.StorageEngine, opts.WorkspaceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load module: %v", err)
+	}
+	if module == nil {
+		return "", ErrWorkspaceNotFound
+	}
+
+	defer func() {
+		// clean up the temporary module on fs
+		err := os.RemoveAll(module.LocalPath)
+		if err != nil {
+			opts.Logger.Error(fmt.Errorf("failed to clean up temporary module on upgrade cleanup: %v", err))
+		}
+	}()
+
+	// refresh with the transition that produced the current state
+	if state == models.WorkspaceStateStopped {
+		module.Environment = append(module.Environment, "GIGO_WORKSPACE_TRANSITION=stop")
+	} else {
+		module.Environment = append(module.Environment, "GIGO_WORKSPACE_TRANSITION=start")
+	}
+
+	previous := opts.Provisioner.ModuleVersion(module)
+
+	_, err = opts.Provisioner.UpgradeModule(ctx, module, opts.Version)
+	if err != nil {
+		return "", fmt.Errorf("failed to upgrade module: %w", err)
+	}
+
+	// preserve the new version for later operations
+	err = module.StoreModule(opts.StorageEngine)
+	if err != nil {
+		return "", fmt.Errorf("failed to store module: %v", err)
+	}
+
+	opts.Logger.Infof("upgraded workspace %d from terraform %s to %s", opts.WorkspaceID, previous, module.TerraformVersion)
+
+	return previous, nil
+}
+
+// forceUnlockWorkspace
+//
+//	Removes the terraform state lock of a workspace. The caller must hold
+//	the provisioner job of the workspace to guarantee that the lock is not
+//	owned by a live operation.
+func forceUnlockWorkspace(ctx context.Context, opts forceUnlockOptions) (*backend.LockInfo, error) {
+	bucketPath := fmt.Sprintf("states/%d", opts.WorkspaceID)
+
+	lock, err := opts.Provisioner.Backend.GetLock(bucketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state lock: %v", err)
+	}
+	if lock == nil {
+		return nil, ErrStateNotLocked
+	}
+
+	err = opts.Provisioner.Backend.ForceUnlock(bucketPath, opts.LockID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to force unlock state: %w", err)
+	}
+
+	opts.Logger.Infof(
+		"force unlocked state of workspace %d: lock %s held by %s for %s since %s",
+		opts.WorkspaceID, lock.ID, lock.Who, lock.Operation, lock.Created.Format(time.RFC3339),
+	)
+
+	return lock, nil
+}
+
+// prepModuleForCreation
+//
+//	Renders the terraform module of a new workspace. The workspace mounts
+//	the pvc of a pooled volume when a pvc name is passed, otherwise the
+//	template provisions a new volume for the workspace.
+func prepModuleForCreation(opts templateOptions, pvcName string, caches []config.RegistryCacheConfig,
+	wsHostOverrides map[string]string) (*models.TerraformModule, error) {
+	// select the template disk size if the volume is nil
+	var templateBuf []byte
+	var err error
+	if pvcName == "" {
+		// read template from storage
+		templateBuf, err = embedFS.ReadFile("resources/template_vol.tf")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tf template: %v", err)
+		}
+	} else {
+		// read template from storage
+		templateBuf, err = embedFS.ReadFile("resources/template_novol.tf")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tf template: %v", err)
+		}
+
+		// inject the volume name into the template
+		templateBuf = []byte(strings.ReplaceAll(string(templateBuf), "<VOL_PVC_NAME>", pvcName))
+	}
+
+	// conditionally inject host aliases into template
+	if len(wsHostOverrides) > 0 {
+		hostAliases := ""
+		for host, ip := range wsHostOverrides {
+			hostAliases += fmt.Sprintf(hostAliasesTemplate, ip, host)
+		}
+		templateBuf = []byte(strings.ReplaceAll(string(templateBuf), "<HOST_ALIASES>", hostAliases))
+	}
+
+	// update the container with registry caching if it is configured
+	opts.Container = handleRegistryCaches(opts.Container, caches)
+
+	return &models.TerraformModule{
+		MainTF:      templateBuf,
+		ModuleID:    opts.WorkspaceID,
+		Environment: prepEnvironmentForCreation(opts),
+	}, nil
+}
+
+func prepEnvironmentForCreation(opts templateOptions) []string {
 	// format parameters into environment variables so that
-	// terraform can compute the provisioning state
-	env = append(env,
+	// terraform can compute the provisioning state - the
+	// provisioner supplies the process environment when it runs
+	env := []string{
 		fmt.Sprintf("GIGO_WORKSPACE_OWNER=%s", opts.OwnerName),
 		fmt.Sprintf("GIGO_WORKSPACE_OWNER_EMAIL=%s", opts.OwnerEmail),
 		fmt.Sprintf("GIGO_WORKSPACE_OWNER_ID=%d", opts.OwnerID),
@@ -411,7 +963,7 @@ func prepEnvironmentForCreation(opts templateOptions) []string {
 		fmt.Sprintf("GIGO_AGENT_URL=%s", opts.AccessUrl),
 		fmt.Sprintf("GIGO_WORKSPACE_ID=%d", opts.WorkspaceID),
 		"GIGO_WORKSPACE_TRANSITION=start",
-	)
+	}
 
 	// add agent scripts to the environments
 	env = append(env, AgentScriptEnv()...)
@@ -453,3 +1005,30 @@ func handleRegistryCaches(containerName string, caches []config.RegistryCacheCon
 	// return the container name if no cache was found
 	return containerName
 }
+
+// restoreWorkspaceStatefile
+//
+//	Rolls the statefile of a workspace back to a previous version. The
+//	caller must hold the provisioner job of the workspace. The restore is
+//	refused while the state is locked since the operation that owns the
+//	lock would overwrite the restored state when it completes.
+func restoreWorkspaceStatefile(ctx context.Context, opts restoreStatefileOptions) error {
+	bucketPath := fmt.Sprintf("states/%d", opts.WorkspaceID)
+
+	lock, err := opts.Provisioner.Backend.GetLock(bucketPath)
+	if err != nil {
+		return fmt.Errorf("failed to get state lock: %v", err)
+	}
+	if lock != nil {
+		return fmt.Errorf("%w by %s for %s", provisioner.ErrStateLocked, lock.Who, lock.Operation)
+	}
+
+	err = opts.Provisioner.RestoreStatefile(opts.WorkspaceID, opts.VersionID)
+	if err != nil {
+		return err
+	}
+
+	opts.Logger.Infof("restored state of workspace %d to version %s", opts.WorkspaceID, opts.VersionID)
+
+	return nil
+}
@@ -1,8 +1,20 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"gigo-ws/config"
+	"gigo-ws/models"
+	"gigo-ws/provisioner"
+	"gigo-ws/provisioner/backend"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+
+	config2 "github.com/gage-technologies/gigo-lib/config"
+	"github.com/gage-technologies/gigo-lib/storage"
 )
 
 // TODO: figure out how to test this
@@ -64,3 +76,152 @@ func TestHandleRegistryCache(t *testing.T) {
 		})
 	}
 }
+
+func TestListWorkspaces(t *testing.T) {
+	_, b, _, _ := runtime.Caller(0)
+	basepath := strings.Replace(filepath.Dir(b), "/api", "", -1)
+	pb, err := backend.NewProvisionerBackendFS(config2.StorageFSConfig{
+		Root: basepath + "/test_data/statefiles",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storageEngine, err := storage.CreateFileSystemStorage("/tmp/gigo-ws-list-workspaces-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll("/tmp/gigo-ws-list-workspaces-test")
+
+	// store a module without a statefile to ensure orphaned modules are listed
+	module := &models.TerraformModule{
+		MainTF:   []byte("resource {}"),
+		ModuleID: 422,
+	}
+	err = module.StoreModule(storageEngine)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := listWorkspacesOptions{
+		Provisioner:   &provisioner.Provisioner{Backend: pb},
+		StorageEngine: storageEngine,
+		Limit:         2,
+	}
+
+	workspaces, cursor, err := listWorkspaces(context.TODO(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(workspaces) != 2 || workspaces[0].WorkspaceID != 420 || workspaces[1].WorkspaceID != 421 {
+		t.Fatalf("unexpected first page: %+v", workspaces)
+	}
+
+	if workspaces[0].State != models.WorkspaceStateActive || workspaces[1].State != models.WorkspaceStateStopped {
+		t.Fatalf("unexpected states: %+v", workspaces)
+	}
+
+	if workspaces[0].LastModified.IsZero() {
+		t.Fatal("expected last modified time for statefile")
+	}
+
+	if cursor != 421 {
+		t.Fatalf("expected cursor 421, got %d", cursor)
+	}
+
+	opts.Cursor = cursor
+	workspaces, cursor, err = listWorkspaces(context.TODO(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(workspaces) != 1 || workspaces[0].WorkspaceID != 422 || workspaces[0].State != models.WorkspaceStateDestroyed {
+		t.Fatalf("unexpected second page: %+v", workspaces)
+	}
+
+	if cursor != 0 {
+		t.Fatalf("expected cursor 0, got %d", cursor)
+	}
+
+	// filter by state
+	opts.Cursor = 0
+	opts.States = []models.WorkspaceState{models.WorkspaceStateStopped}
+	workspaces, cursor, err = listWorkspaces(context.TODO(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(workspaces) != 1 || workspaces[0].WorkspaceID != 421 || cursor != 0 {
+		t.Fatalf("unexpected filtered page: %+v %d", workspaces, cursor)
+	}
+}
+
+func TestUpdateWorkspaceResources(t *testing.T) {
+	_, b, _, _ := runtime.Caller(0)
+	basepath := strings.Replace(filepath.Dir(b), "/api", "", -1)
+	pb, err := backend.NewProvisionerBackendFS(config2.StorageFSConfig{
+		Root: basepath + "/test_data/statefiles",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storageEngine, err := storage.CreateFileSystemStorage("/tmp/gigo-ws-update-resources-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll("/tmp/gigo-ws-update-resources-test")
+
+	// store a module for the active workspace using a pooled volume
+	module := &models.TerraformModule{
+		MainTF:   []byte(`claim_name = "gigo-ws-vol-69"`),
+		ModuleID: 420,
+		Environment: []string{
+			"GIGO_WORKSPACE_DISK=10Gi",
+			"GIGO_WORKSPACE_CPU=4",
+			"GIGO_WORKSPACE_MEM=8G",
+		},
+	}
+	err = module.StoreModule(storageEngine)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := updateWorkspaceResourcesOptions{
+		Provisioner:   &provisioner.Provisioner{Backend: pb},
+		StorageEngine: storageEngine,
+		WorkspaceID:   420,
+		Resources:     models.WorkspaceResources{CPU: 4, Memory: 8, Disk: 5},
+	}
+
+	// ensure the disk cannot shrink
+	_, err = updateWorkspaceResources(context.TODO(), opts)
+	if !errors.Is(err, ErrInvalidWorkspaceResources) {
+		t.Fatalf("expected ErrInvalidWorkspaceResources for disk shrink, got %v", err)
+	}
+
+	// ensure a pooled volume cannot be resized without the volume pool
+	opts.Resources.Disk = 20
+	_, err = updateWorkspaceResources(context.TODO(), opts)
+	if !errors.Is(err, ErrInvalidWorkspaceResources) {
+		t.Fatalf("expected ErrInvalidWorkspaceResources for pooled volume resize, got %v", err)
+	}
+
+	// an unchanged sizing is a no-op
+	opts.Resources.Disk = 10
+	logs, err := updateWorkspaceResources(context.TODO(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logs == nil || len(logs.Events) != 0 {
+		t.Fatalf("expected empty logs for no-op update, got %+v", logs)
+	}
+
+	// ensure a missing workspace is reported
+	opts.WorkspaceID = 69
+	_, err = updateWorkspaceResources(context.TODO(), opts)
+	if !errors.Is(err, ErrWorkspaceNotFound) {
+		t.Fatalf("expected ErrWorkspaceNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,361 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"gigo-ws/joblock"
+	"gigo-ws/metrics"
+	"gigo-ws/models"
+	"gigo-ws/protos/ws"
+	"gigo-ws/provisioner"
+
+	"github.com/gage-technologies/gigo-lib/logging"
+	"github.com/gage-technologies/gigo-lib/storage"
+	"golang.org/x/sync/singleflight"
+)
+
+// defaultDriftInterval is the interval between drift scans when none is configured
+const defaultDriftInterval = time.Hour
+
+var (
+	ErrDriftReportNotFound = fmt.Errorf("drift report not found")
+)
+
+type DriftDetectorOptions struct {
+	NodeID        int64
+	JobLocker     joblock.Locker
+	Provisioner   *provisioner.Provisioner
+	StorageEngine storage.Storage
+	// Interval minimum time between the start of two scans
+	Interval time.Duration
+	Logger   logging.Logger
+}
+
+// DriftDetector
+//
+//	Periodically performs a refresh-only plan against every stored
+//	module to detect changes made to the workspace resources outside
+//	of terraform. The detector is driven by the cluster leader so that
+//	only a single node scans the workspaces at a time.
+type DriftDetector struct {
+	DriftDetectorOptions
+	mu       sync.Mutex
+	lastScan time.Time
+	sflight  singleflight.Group
+}
+
+// NewDriftDetector
+//
+//	Creates a new DriftDetector
+func NewDriftDetector(opts DriftDetectorOptions) *DriftDetector {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultDriftInterval
+	}
+	return &DriftDetector{
+		DriftDetectorOptions: opts,
+	}
+}
+
+// Trigger
+//
+//	Launches a scan in the background if the interval has elapsed since
+//	the last scan. The scan exits early once the context is cancelled
+//	which happens when the node loses leadership.
+func (d *DriftDetector) Trigger(ctx context.Context) {
+	d.mu.Lock()
+	if time.Since(d.lastScan) < d.Interval {
+		d.mu.Unlock()
+		return
+	}
+	d.lastScan = time.Now()
+	d.mu.Unlock()
+
+	go d.sflight.Do("scan", func() (interface{}, error) {
+		d.scan(ctx)
+		return nil, nil
+	})
+}
+
+// scan
+//
+//	Checks every stored module for drift recording a report for each
+//	workspace. Workspaces with an active provisioner job are skipped
+//	and retain the report of the previous scan.
+func (d *DriftDetector) scan(ctx context.Context) {
+	d.Logger.Debug("beginning drift scan")
+
+	modules, err := d.StorageEngine.ListDir("modules", false)
+	if err != nil {
+		d.Logger.Error(fmt.Errorf("drift scan: failed to list modules: %v", err))
+		return
+	}
+
+	drifted := 0
+	for _, m := range modules {
+		// exit if the node lost leadership or is shutting down
+		if ctx.Err() != nil {
+			d.Logger.Debug("drift scan interrupted")
+			return
+		}
+
+		id, err := strconv.ParseInt(path.Base(m), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		report, err := d.checkWorkspace(ctx, id)
+		if err != nil {
+			d.Logger.Error(fmt.Errorf("drift scan: failed to check workspace %d: %v", id, err))
+			continue
+		}
+		if report == nil {
+			continue
+		}
+
+		metrics.ObserveDriftCheck(report.Status.String())
+		if report.Status == models.DriftStatusDrifted {
+			drifted++
+			d.Logger.Warnf("drift scan: detected drift of %d resources in workspace %d", len(report.Resources), id)
+		}
+
+		err = saveDriftReport(d.StorageEngine, report)
+		if err != nil {
+			d.Logger.Error(fmt.Errorf("drift scan: failed to save report for workspace %d: %v", id, err))
+		}
+	}
+
+	metrics.ObserveDriftScan(drifted, time.Now())
+	d.Logger.Debugf("completed drift scan: %d drifted workspaces", drifted)
+}
+
+// checkWorkspace
+//
+//	Performs the drift check of a single workspace. A nil report is
+//	returned if the workspace is busy or has been destroyed.
+func (d *DriftDetector) checkWorkspace(ctx context.Context, workspaceId int64) (*models.DriftReport, error) {
+	// retrieve the current state from statefile
+	state, err := provisioner.ParseStatefileForWorkspaceState(d.Provisioner.Backend, workspaceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workspace state from statefile: %v", err)
+	}
+
+	// destroyed workspaces have nothing to drift so we drop any stale report
+	if state == models.WorkspaceStateDestroyed {
+		return nil, deleteDriftReport(d.StorageEngine, workspaceId)
+	}
+
+	// the check shares the module directory and statefile with the other
+	// operations so we skip workspaces that have an active provisioner job
+	lock, _, err := d.JobLocker.TryLock(context.Background(), provisionerJobKey(workspaceId))
+	if err != nil {
+		if errors.Is(err, joblock.ErrLocked) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to register workspace task: %v", err)
+	}
+	metrics.JobStarted()
+	defer func() {
+		metrics.JobFinished()
+		err := lock.Release()
+		if err != nil {
+			d.Logger.Error(fmt.Errorf("failed to release provisioner job %s: %v", lock.Key, err))
+		}
+	}()
+
+	// cancel the check if cancellation is requested for the workspace
+	ctx, cancel := provisionerJobContext(ctx, lock)
+	defer cancel()
+
+	// load module using the workspace id
+	module, err := models.LoadModule(d.StorageEngine, workspaceId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load module: %v", err)
+	}
+	if module == nil {
+		return nil, nil
+	}
+
+	defer func() {
+		// clean up the temporary module on fs
+		err := os.RemoveAll(module.LocalPath)
+		if err != nil {
+			d.Logger.Error(fmt.Errorf("failed to clean up temporary module on drift cleanup: %v", err))
+		}
+	}()
+
+	// refresh against the transition that produced the current state
+	// so that the stopped workspace is not reported as drifted
+	if state == models.WorkspaceStateStopped {
+		module.Environment = append(module.Environment, "GIGO_WORKSPACE_TRANSITION=stop")
+	} else {
+		module.Environment = append(module.Environment, "GIGO_WORKSPACE_TRANSITION=start")
+	}
+
+	report := &models.DriftReport{
+		WorkspaceID: workspaceId,
+		Status:      models.DriftStatusInSync,
+		NodeID:      d.NodeID,
+	}
+
+	// drift checks are background work that yields to user operations
+	drift, err := d.Provisioner.DetectDrift(provisioner.WithPriority(ctx, provisioner.PriorityBackground), module)
+	report.CheckedAt = time.Now()
+	if err != nil {
+		// a cancelled check says nothing about the workspace and a saturated
+		// node is treated like a busy workspace that is checked next time
+		if lock.IsCancelled() || ctx.Err() != nil || errors.Is(err, provisioner.ErrQueueFull) {
+			return nil, nil
+		}
+		report.Status = models.DriftStatusFailed
+		report.Error = err.Error()
+		return report, nil
+	}
+
+	if len(drift) > 0 {
+		report.Status = models.DriftStatusDrifted
+		report.Resources = drift
+	}
+
+	return report, nil
+}
+
+// GetWorkspaceDrift
+//
+//	Retrieves the result of the last drift check of a workspace
+func (s *ProvisionerApiServer) GetWorkspaceDrift(ctx context.Context, request *ws.GetWorkspaceDriftRequest) (*ws.GetWorkspaceDriftResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("GetWorkspaceDrift (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.GetWorkspaceDriftResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	report, err := loadDriftReport(s.StorageEngine, request.GetWorkspaceId())
+	if err != nil {
+		if errors.Is(err, ErrDriftReportNotFound) {
+			return &ws.GetWorkspaceDriftResponse{
+				Status: ws.ResponseCode_NOT_FOUND,
+			}, nil
+		}
+		s.Logger.Warn(fmt.Errorf("GetWorkspaceDrift (%d): failed to get drift report: %v", ctx.Value("id"), err))
+		return &ws.GetWorkspaceDriftResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	return &ws.GetWorkspaceDriftResponse{
+		Status: ws.ResponseCode_SUCCESS,
+		Report: formatDriftReport(report),
+	}, nil
+}
+
+// driftReportPath
+//
+//	Formats the storage path of the drift report of a workspace
+func driftReportPath(workspaceId int64) string {
+	return fmt.Sprintf("provisioner/drift/%d.json", workspaceId)
+}
+
+// saveDriftReport
+//
+//	Writes the drift report to the storage engine
+func saveDriftReport(storageEngine storage.Storage, report *models.DriftReport) error {
+	buf, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal drift report: %v", err)
+	}
+
+	err = storageEngine.CreateFile(driftReportPath(report.WorkspaceID), buf)
+	if err != nil {
+		return fmt.Errorf("failed to write drift report: %v", err)
+	}
+
+	return nil
+}
+
+// loadDriftReport
+//
+//	Reads the drift report of a workspace from the storage engine
+//	returning ErrDriftReportNotFound if the workspace has not been checked
+func loadDriftReport(storageEngine storage.Storage, workspaceId int64) (*models.DriftReport, error) {
+	reader, err := storageEngine.GetFile(driftReportPath(workspaceId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read drift report: %v", err)
+	}
+	if reader == nil {
+		return nil, ErrDriftReportNotFound
+	}
+	defer reader.Close()
+
+	buf, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read drift report: %v", err)
+	}
+
+	var report models.DriftReport
+	err = json.Unmarshal(buf, &report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal drift report: %v", err)
+	}
+
+	return &report, nil
+}
+
+// deleteDriftReport
+//
+//	Removes the drift report of a workspace from the storage engine
+func deleteDriftReport(storageEngine storage.Storage, workspaceId int64) error {
+	exists, _, err := storageEngine.Exists(driftReportPath(workspaceId))
+	if err != nil {
+		return fmt.Errorf("failed to check drift report: %v", err)
+	}
+	if !exists {
+		return nil
+	}
+
+	err = storageEngine.DeleteFile(driftReportPath(workspaceId))
+	if err != nil {
+		return fmt.Errorf("failed to delete drift report: %v", err)
+	}
+
+	return nil
+}
+
+// formatDriftReport
+//
+//	Formats a drift report into a ws.DriftReport
+func formatDriftReport(report *models.DriftReport) *ws.DriftReport {
+	out := &ws.DriftReport{
+		WorkspaceId: report.WorkspaceID,
+		Status:      ws.DriftStatus(report.Status),
+		Resources:   make([]*ws.ResourceDrift, 0, len(report.Resources)),
+		Error:       report.Error,
+		NodeId:      report.NodeID,
+		CheckedAt:   report.CheckedAt.Unix(),
+	}
+	for _, r := range report.Resources {
+		out.Resources = append(out.Resources, &ws.ResourceDrift{
+			Address:    r.Address,
+			Type:       r.Type,
+			Action:     r.Action,
+			Attributes: r.Attributes,
+		})
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"gigo-ws/models"
+	"gigo-ws/protos/ws"
+
+	"github.com/gage-technologies/gigo-lib/storage"
+)
+
+func TestGetWorkspaceDrift(t *testing.T) {
+	storageEngine, err := storage.CreateFileSystemStorage("/tmp/gigo-ws-drift-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll("/tmp/gigo-ws-drift-test")
+
+	s := &ProvisionerApiServer{
+		ProvisionerApiServerOptions: ProvisionerApiServerOptions{
+			ID:            1,
+			StorageEngine: storageEngine,
+		},
+	}
+
+	res, err := s.GetWorkspaceDrift(context.Background(), &ws.GetWorkspaceDriftRequest{WorkspaceId: 420})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetStatus() != ws.ResponseCode_NOT_FOUND {
+		t.Fatalf("expected NOT_FOUND for unchecked workspace, got %s", res.GetStatus())
+	}
+
+	report := &models.DriftReport{
+		WorkspaceID: 420,
+		Status:      models.DriftStatusDrifted,
+		Resources: []models.ResourceDrift{
+			{
+				Address:    "kubernetes_pod.main[0]",
+				Type:       "kubernetes_pod",
+				Action:     "update",
+				Attributes: []string{"spec.0.container.0.image"},
+			},
+			{
+				Address: "kubernetes_service.main",
+				Type:    "kubernetes_service",
+				Action:  "delete",
+			},
+		},
+		NodeID:    1,
+		CheckedAt: time.Unix(1675188000, 0),
+	}
+	err = saveDriftReport(storageEngine, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err = s.GetWorkspaceDrift(context.Background(), &ws.GetWorkspaceDriftRequest{WorkspaceId: 420})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GetStatus() != ws.ResponseCode_SUCCESS {
+		t.Fatalf("expected SUCCESS, got %s", res.GetStatus())
+	}
+	if res.GetReport().GetStatus() != ws.DriftStatus_DRIFT_DETECTED || res.GetReport().GetCheckedAt() != 1675188000 {
+		t.Fatalf("unexpected report: %+v", res.GetReport())
+	}
+	if len(res.GetReport().GetResources()) != 2 {
+		t.Fatalf("expected 2 drifted resources, got %d", len(res.GetReport().GetResources()))
+	}
+	if res.GetReport().GetResources()[0].GetAttributes()[0] != "spec.0.container.0.image" {
+		t.Fatalf("unexpected attributes: %v", res.GetReport().GetResources()[0].GetAttributes())
+	}
+
+	err = deleteDriftReport(storageEngine, 420)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// deleting a missing report is a no-op
+	err = deleteDriftReport(storageEngine, 420)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = loadDriftReport(storageEngine, 420)
+	if !errors.Is(err, ErrDriftReportNotFound) {
+		t.Fatalf("expected ErrDriftReportNotFound, got %v", err)
+	}
+}
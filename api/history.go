@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"gigo-ws/protos/ws"
+	"gigo-ws/provisioner"
+	"gigo-ws/provisioner/backend"
+)
+
+// ListStatefileVersions
+//
+//	Lists the versions of the statefile of a workspace that can be
+//	restored ordered from the newest to the oldest version
+func (s *ProvisionerApiServer) ListStatefileVersions(ctx context.Context, request *ws.ListStatefileVersionsRequest) (*ws.ListStatefileVersionsResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("ListStatefileVersions (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.ListStatefileVersionsResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	versions, err := s.Provisioner.Backend.ListStatefileVersions(fmt.Sprintf("states/%d", request.GetWorkspaceId()))
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("ListStatefileVersions (%d): failed to list statefile versions: %v", ctx.Value("id"), err))
+		return &ws.ListStatefileVersionsResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	res := &ws.ListStatefileVersionsResponse{
+		Status:   ws.ResponseCode_SUCCESS,
+		Versions: make([]*ws.StatefileVersion, 0, len(versions)),
+	}
+	for _, v := range versions {
+		res.Versions = append(res.Versions, &ws.StatefileVersion{
+			VersionId:    v.ID,
+			LastModified: v.LastModified.Unix(),
+			Size:         v.Size,
+		})
+	}
+
+	return res, nil
+}
+
+// GetStatefileVersion
+//
+//	Returns the contents of a version of the statefile of a workspace
+//	so that it can be inspected before it is restored
+func (s *ProvisionerApiServer) GetStatefileVersion(ctx context.Context, request *ws.GetStatefileVersionRequest) (*ws.GetStatefileVersionResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("GetStatefileVersion (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.GetStatefileVersionResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	// validate version id
+	if request.GetVersionId() == "" {
+		return &ws.GetStatefileVersionResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid version id",
+			},
+		}, nil
+	}
+
+	buf, err := s.Provisioner.Backend.GetStatefileVersion(fmt.Sprintf("states/%d", request.GetWorkspaceId()), request.GetVersionId())
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("GetStatefileVersion (%d): failed to get statefile version: %v", ctx.Value("id"), err))
+		return &ws.GetStatefileVersionResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+	if buf == nil {
+		return &ws.GetStatefileVersionResponse{
+			Status: ws.ResponseCode_NOT_FOUND,
+		}, nil
+	}
+	defer buf.Close()
+
+	state, err := io.ReadAll(buf)
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("GetStatefileVersion (%d): failed to read statefile version: %v", ctx.Value("id"), err))
+		return &ws.GetStatefileVersionResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	return &ws.GetStatefileVersionResponse{
+		Status: ws.ResponseCode_SUCCESS,
+		State:  state,
+	}, nil
+}
+
+// RestoreStatefile
+//
+//	Rolls the statefile of a workspace back to a previous version. The
+//	restore is refused while any node of the cluster is executing an
+//	operation for the workspace or the state is locked.
+func (s *ProvisionerApiServer) RestoreStatefile(ctx context.Context, request *ws.RestoreStatefileRequest) (*ws.RestoreStatefileResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("RestoreStatefile (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.RestoreStatefileResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	// validate version id
+	if request.GetVersionId() == "" {
+		return &ws.RestoreStatefileResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid version id",
+			},
+		}, nil
+	}
+
+	// acquire the provisioner job for the workspace across the cluster so
+	// that no operation can read or write the state while it is restored
+	lock, err := acquireProvisionerJob(s, request.GetWorkspaceId())
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("RestoreStatefile (%d): failed to acquire provisioner job: %v", ctx.Value("id"), err))
+		return &ws.RestoreStatefileResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	// refuse to restore while an operation is active for the workspace
+	if lock == nil {
+		return &ws.RestoreStatefileResponse{
+			Status: ws.ResponseCode_ALTERNATIVE_REQUEST_ACTIVE,
+			Error: &ws.Error{
+				GoError: "workspace has an active provisioner job",
+			},
+		}, nil
+	}
+
+	// release the provisioner job once the operation completes
+	defer releaseProvisionerJob(s, lock)
+
+	err = restoreWorkspaceStatefile(ctx, restoreStatefileOptions{
+		Provisioner: s.Provisioner,
+		Logger:      s.Logger,
+		WorkspaceID: request.GetWorkspaceId(),
+		VersionID:   request.GetVersionId(),
+	})
+	if err != nil {
+		if errors.Is(err, backend.ErrStatefileVersionNotFound) {
+			return &ws.RestoreStatefileResponse{
+				Status: ws.ResponseCode_NOT_FOUND,
+			}, nil
+		}
+		if errors.Is(err, provisioner.ErrStateLocked) {
+			return &ws.RestoreStatefileResponse{
+				Status: ws.ResponseCode_STATE_LOCKED,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			}, nil
+		}
+		s.Logger.Warn(fmt.Errorf("RestoreStatefile (%d): failed to restore statefile: %v", ctx.Value("id"), err))
+		return &ws.RestoreStatefileResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	return &ws.RestoreStatefileResponse{
+		Status: ws.ResponseCode_SUCCESS,
+	}, nil
+}
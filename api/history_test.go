@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gigo-ws/provisioner"
+	"gigo-ws/provisioner/backend"
+
+	"github.com/gage-technologies/gigo-lib/config"
+	"github.com/gage-technologies/gigo-lib/logging"
+)
+
+func TestRestoreWorkspaceStatefile(t *testing.T) {
+	root := t.TempDir()
+	pb, err := backend.NewProvisionerBackendFS(config.StorageFSConfig{Root: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger, err := logging.CreateBasicLogger(logging.NewDefaultBasicLoggerOptions(filepath.Join(root, "test.log")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// write the state of a good apply and snapshot it before the bad apply
+	err = os.MkdirAll(filepath.Join(root, "states"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(root, "states", "420"), []byte(`{"serial": 1}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pb.(backend.HistoryProvisionerBackend).SnapshotStatefile("states/420", 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(root, "states", "420"), []byte(`{}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := pb.ListStatefileVersions("states/420")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 {
+		t.Fatalf("expected 1 version, got %+v", versions)
+	}
+
+	opts := restoreStatefileOptions{
+		Provisioner: &provisioner.Provisioner{Backend: pb},
+		Logger:      logger,
+		WorkspaceID: 420,
+		VersionID:   versions[0].ID,
+	}
+
+	// ensure the restore is refused while the state is locked
+	lockInfo := filepath.Join(root, "states", "states", ".420.lock.info")
+	err = os.MkdirAll(filepath.Dir(lockInfo), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(lockInfo, []byte(`{"ID":"lock-420","Operation":"OperationTypeApply","Who":"root@gigo-ws-0"}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = restoreWorkspaceStatefile(context.TODO(), opts)
+	if !errors.Is(err, provisioner.ErrStateLocked) {
+		t.Fatalf("expected ErrStateLocked, got %v", err)
+	}
+	err = os.Remove(lockInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = restoreWorkspaceStatefile(context.TODO(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := pb.GetStatefile("states/420")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := io.ReadAll(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = buf.Close()
+	if string(state) != `{"serial": 1}` {
+		t.Fatalf("statefile was not restored: %s", string(state))
+	}
+
+	// the replaced state is kept so that the restore can be rolled back
+	versions, err = pb.ListStatefileVersions("states/420")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].Size != 2 {
+		t.Fatalf("expected the replaced state to be kept, got %+v", versions)
+	}
+
+	opts.VersionID = "1"
+	err = restoreWorkspaceStatefile(context.TODO(), opts)
+	if !errors.Is(err, backend.ErrStatefileVersionNotFound) {
+		t.Fatalf("expected ErrStatefileVersionNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,544 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gigo-ws/joblock"
+	"gigo-ws/models"
+	"gigo-ws/protos/ws"
+	"gigo-ws/provisioner"
+
+	"github.com/gage-technologies/gigo-lib/storage"
+)
+
+// jobWatchInterval is the interval at which WatchJob polls the job record
+const jobWatchInterval = time.Second
+
+var (
+	ErrJobNotFound = fmt.Errorf("job not found")
+)
+
+// jobFunc
+//
+//	Operation executed by an asynchronous provisioner job. The
+//	returned agent is recorded on the job for create and start jobs.
+type jobFunc func(ctx context.Context) (*models.Agent, error)
+
+// SubmitCreateWorkspace
+//
+//	Submits an asynchronous job that provisions a new workspace
+//	from scratch and returns the id of the job immediately
+func (s *ProvisionerApiServer) SubmitCreateWorkspace(ctx context.Context, request *ws.CreateWorkspaceRequest) (*ws.SubmitJobResponse, error) {
+	// perform validation on request
+	err := validateCreateWorkspaceRequest(request)
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("SubmitCreateWorkspace (%d): failed to create workspace request: %v", ctx.Value("id"), err))
+		return &ws.SubmitJobResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	// format request into createWorkspaceOptions
+	opts := s.formatCreateWorkspaceOptions(request)
+
+	return s.submitJob(ctx, "SubmitCreateWorkspace", request.GetWorkspaceId(), models.JobOperationCreate,
+		func(ctx context.Context) (*models.Agent, error) {
+			agent, _, err := createWorkspace(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			if agent == nil {
+				return nil, fmt.Errorf("agent is nil")
+			}
+			return agent, nil
+		},
+	), nil
+}
+
+// SubmitStartWorkspace
+//
+//	Submits an asynchronous job that starts an existing workspace
+//	and returns the id of the job immediately
+func (s *ProvisionerApiServer) SubmitStartWorkspace(ctx context.Context, request *ws.StartWorkspaceRequest) (*ws.SubmitJobResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("SubmitStartWorkspace (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.SubmitJobResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	return s.submitJob(ctx, "SubmitStartWorkspace", request.GetWorkspaceId(), models.JobOperationStart,
+		func(ctx context.Context) (*models.Agent, error) {
+			agent, _, err := startWorkspace(ctx, startWorkspaceOptions{
+				Provisioner:   s.Provisioner,
+				StorageEngine: s.StorageEngine,
+				Logger:        s.Logger,
+				WorkspaceID:   request.GetWorkspaceId(),
+			})
+			return agent, err
+		},
+	), nil
+}
+
+// SubmitStopWorkspace
+//
+//	Submits an asynchronous job that stops a running workspace
+//	and returns the id of the job immediately
+func (s *ProvisionerApiServer) SubmitStopWorkspace(ctx context.Context, request *ws.StopWorkspaceRequest) (*ws.SubmitJobResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("SubmitStopWorkspace (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.SubmitJobResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	return s.submitJob(ctx, "SubmitStopWorkspace", request.GetWorkspaceId(), models.JobOperationStop,
+		func(ctx context.Context) (*models.Agent, error) {
+			_, _, err := stopWorkspace(ctx, stopWorkspaceOptions{
+				Provisioner:   s.Provisioner,
+				StorageEngine: s.StorageEngine,
+				Logger:        s.Logger,
+				WorkspaceID:   request.GetWorkspaceId(),
+			})
+			return nil, err
+		},
+	), nil
+}
+
+// SubmitDestroyWorkspace
+//
+//	Submits an asynchronous job that destroys an existing workspace
+//	and returns the id of the job immediately
+func (s *ProvisionerApiServer) SubmitDestroyWorkspace(ctx context.Context, request *ws.DestroyWorkspaceRequest) (*ws.SubmitJobResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("SubmitDestroyWorkspace (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.SubmitJobResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	return s.submitJob(ctx, "SubmitDestroyWorkspace", request.GetWorkspaceId(), models.JobOperationDestroy,
+		func(ctx context.Context) (*models.Agent, error) {
+			_, err := destroyWorkspace(ctx, destroyWorkspaceOptions{
+				Provisioner:   s.Provisioner,
+				StorageEngine: s.StorageEngine,
+				Logger:        s.Logger,
+				WorkspaceID:   request.GetWorkspaceId(),
+				Volpool:       s.Volpool,
+			})
+			// we treat a not-found as an idempotent destroy since we want it gone anyway
+			if errors.Is(err, ErrWorkspaceNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		},
+	), nil
+}
+
+// GetJob
+//
+//	Retrieves the current record of an asynchronous provisioner job
+func (s *ProvisionerApiServer) GetJob(ctx context.Context, request *ws.GetJobRequest) (*ws.GetJobResponse, error) {
+	// validate id
+	if request.JobId < 1 {
+		s.Logger.Warn(fmt.Errorf("GetJob (%d): invalid job id: %d", ctx.Value("id"), request.GetJobId()))
+		return &ws.GetJobResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid job id",
+			},
+		}, nil
+	}
+
+	job, err := getJob(s, request.GetJobId())
+	if err != nil {
+		if errors.Is(err, ErrJobNotFound) {
+			return &ws.GetJobResponse{
+				Status: ws.ResponseCode_NOT_FOUND,
+			}, nil
+		}
+		s.Logger.Warn(fmt.Errorf("GetJob (%d): failed to get job: %v", ctx.Value("id"), err))
+		return &ws.GetJobResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	return &ws.GetJobResponse{
+		Status: ws.ResponseCode_SUCCESS,
+		Job:    s.formatJobWithAgent(ctx, job),
+	}, nil
+}
+
+// WatchJob
+//
+//	Streams the record of an asynchronous provisioner job to the caller
+//	each time the status of the job changes. The stream is closed with a
+//	ws.ResponseCode_SUCCESS_STREAM_COMPLETE message containing the final
+//	record once the job has finished.
+func (s *ProvisionerApiServer) WatchJob(request *ws.WatchJobRequest, stream ws.DRPCGigoWS_WatchJobStream) error {
+	ctx := stream.Context()
+
+	// validate id
+	if request.JobId < 1 {
+		s.Logger.Warn(fmt.Errorf("WatchJob (%d): invalid job id: %d", ctx.Value("id"), request.GetJobId()))
+		return stream.Send(&ws.WatchJobResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid job id",
+			},
+		})
+	}
+
+	ticker := time.NewTicker(jobWatchInterval)
+	defer ticker.Stop()
+
+	var last *models.Job
+	for {
+		job, err := getJob(s, request.GetJobId())
+		if err != nil {
+			if errors.Is(err, ErrJobNotFound) {
+				return stream.Send(&ws.WatchJobResponse{
+					Status: ws.ResponseCode_NOT_FOUND,
+				})
+			}
+			s.Logger.Warn(fmt.Errorf("WatchJob (%d): failed to get job: %v", ctx.Value("id"), err))
+			return stream.Send(&ws.WatchJobResponse{
+				Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			})
+		}
+
+		// close the stream with the final record once the job finishes
+		if job.Status.Finished() {
+			return stream.Send(&ws.WatchJobResponse{
+				Status: ws.ResponseCode_SUCCESS_STREAM_COMPLETE,
+				Job:    s.formatJobWithAgent(ctx, job),
+			})
+		}
+
+		// only send the record when the status changes
+		if last == nil || last.Status != job.Status {
+			err = stream.Send(&ws.WatchJobResponse{
+				Status: ws.ResponseCode_SUCCESS,
+				Job:    formatJob(job),
+			})
+			if err != nil {
+				return err
+			}
+			last = job
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// submitJob
+//
+//	Acquires the provisioner job for the workspace, persists a new job
+//	record and executes the operation in the background. The operation
+//	is detached from the rpc context so that it continues to completion
+//	even if the requester disconnects.
+func (s *ProvisionerApiServer) submitJob(ctx context.Context, rpc string, workspaceId int64, op models.JobOperation, run jobFunc) *ws.SubmitJobResponse {
+	// reject new jobs once the server is closing
+	if s.ctx.Err() != nil {
+		return &ws.SubmitJobResponse{
+			Status: ws.ResponseCode_SERVICE_BLOCK,
+		}
+	}
+
+	s.Logger.Debug(fmt.Errorf("%s (%d): submitting %s job: %d", rpc, ctx.Value("id"), op, workspaceId))
+
+	// acquire the provisioner job for the workspace across the cluster
+	lock, err := acquireProvisionerJob(s, workspaceId)
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("%s (%d): failed to acquire provisioner job: %v", rpc, ctx.Value("id"), err))
+		return &ws.SubmitJobResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}
+	}
+
+	// handle the case that there is an active provisioner job
+	if lock == nil {
+		return &ws.SubmitJobResponse{
+			Status: ws.ResponseCode_ALTERNATIVE_REQUEST_ACTIVE,
+		}
+	}
+
+	// persist the job record before we hand back the id
+	job := &models.Job{
+		ID:          s.SnowflakeNode.Generate().Int64(),
+		WorkspaceID: workspaceId,
+		Operation:   op,
+		Status:      models.JobStatusPending,
+		NodeID:      s.ID,
+		CreatedAt:   time.Now(),
+	}
+	err = saveJob(s.StorageEngine, job)
+	if err != nil {
+		releaseProvisionerJob(s, lock)
+		s.Logger.Warn(fmt.Errorf("%s (%d): failed to save job: %v", rpc, ctx.Value("id"), err))
+		return &ws.SubmitJobResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}
+	}
+
+	// execute the job in the background - the server waits on the
+	// wait group during close so in-flight jobs are not abandoned
+	s.wg.Add(1)
+	go s.executeJob(job, lock, run)
+
+	return &ws.SubmitJobResponse{
+		Status: ws.ResponseCode_SUCCESS,
+		JobId:  job.ID,
+	}
+}
+
+// executeJob
+//
+//	Executes the operation of a submitted job recording the status and
+//	result of the job as it progresses. The provisioner job is released
+//	only after the final record has been persisted.
+func (s *ProvisionerApiServer) executeJob(job *models.Job, lock *joblock.Lock, run jobFunc) {
+	defer s.wg.Done()
+	defer releaseProvisionerJob(s, lock)
+
+	// mark the job as running
+	job.Status = models.JobStatusRunning
+	job.StartedAt = time.Now()
+	err := saveJob(s.StorageEngine, job)
+	if err != nil {
+		s.Logger.Error(fmt.Errorf("failed to save running job %d: %v", job.ID, err))
+	}
+
+	// we use a new context here since the job must outlive the rpc that submitted it
+	ctx, cancel := provisionerJobContext(context.Background(), lock)
+	agent, err := run(ctx)
+	cancel()
+
+	// record the result of the job
+	job.FinishedAt = time.Now()
+	if err != nil && lock.IsCancelled() {
+		s.Logger.Infof("job %d: %s of workspace %d cancelled", job.ID, job.Operation, job.WorkspaceID)
+		job.Status = models.JobStatusCancelled
+		job.Error = err.Error()
+	} else if err != nil {
+		s.Logger.Warn(fmt.Errorf("job %d: failed to %s workspace %d: %v", job.ID, job.Operation, job.WorkspaceID, err))
+		job.Status = models.JobStatusFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = models.JobStatusSucceeded
+		// only the id is recorded - the token is read from the state on demand
+		if agent != nil {
+			job.Agent = &models.Agent{ID: agent.ID}
+		}
+	}
+
+	err = saveJob(s.StorageEngine, job)
+	if err != nil {
+		s.Logger.Error(fmt.Errorf("failed to save finished job %d: %v", job.ID, err))
+		return
+	}
+
+	s.Logger.Debugf("job %d: completed %s of workspace %d: %s", job.ID, job.Operation, job.WorkspaceID, job.Status)
+}
+
+// getJob
+//
+//	Loads the record of a job resolving unfinished jobs whose
+//	owning node no longer holds the provisioner job of the workspace
+func getJob(s *ProvisionerApiServer, jobId int64) (*models.Job, error) {
+	job, err := loadJob(s.StorageEngine, jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status.Finished() {
+		return job, nil
+	}
+
+	// the job is still live if its node holds the provisioner job
+	holder, err := getProvisionerJob(s, job.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if holder != nil && holder.NodeID == job.NodeID {
+		return job, nil
+	}
+
+	// reload the job since it may have finished between the two reads
+	job, err = loadJob(s.StorageEngine, jobId)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status.Finished() {
+		return job, nil
+	}
+
+	// the node exited mid-operation so the job will never complete
+	job.Status = models.JobStatusFailed
+	job.FinishedAt = time.Now()
+	job.Error = fmt.Sprintf("node %d exited before the job completed", job.NodeID)
+	err = saveJob(s.StorageEngine, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// jobRecordPath
+//
+//	Formats the storage path of a job record
+func jobRecordPath(jobId int64) string {
+	return fmt.Sprintf("%s/records/%d.json", ProvisionerJobPrefix, jobId)
+}
+
+// saveJob
+//
+//	Writes the job record to the storage engine. The agent token is
+//	never persisted since records are kept long after the operation.
+func saveJob(storageEngine storage.Storage, job *models.Job) error {
+	record := *job
+	if record.Agent != nil {
+		record.Agent = &models.Agent{ID: record.Agent.ID}
+	}
+
+	buf, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %v", err)
+	}
+
+	err = storageEngine.CreateFile(jobRecordPath(job.ID), buf)
+	if err != nil {
+		return fmt.Errorf("failed to write job: %v", err)
+	}
+
+	return nil
+}
+
+// loadJob
+//
+//	Reads the job record from the storage engine returning
+//	ErrJobNotFound if the job does not exist
+func loadJob(storageEngine storage.Storage, jobId int64) (*models.Job, error) {
+	reader, err := storageEngine.GetFile(jobRecordPath(jobId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job: %v", err)
+	}
+	if reader == nil {
+		return nil, ErrJobNotFound
+	}
+	defer reader.Close()
+
+	buf, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job: %v", err)
+	}
+
+	var job models.Job
+	err = json.Unmarshal(buf, &job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job: %v", err)
+	}
+
+	return &job, nil
+}
+
+// formatJob
+//
+//	Formats a job record into a ws.Job
+func formatJob(job *models.Job) *ws.Job {
+	out := &ws.Job{
+		Id:          job.ID,
+		WorkspaceId: job.WorkspaceID,
+		Operation:   ws.JobOperation(job.Operation),
+		Status:      ws.JobStatus(job.Status),
+		NodeId:      job.NodeID,
+		CreatedAt:   job.CreatedAt.Unix(),
+		Error:       job.Error,
+	}
+	if !job.StartedAt.IsZero() {
+		out.StartedAt = job.StartedAt.Unix()
+	}
+	if !job.FinishedAt.IsZero() {
+		out.FinishedAt = job.FinishedAt.Unix()
+	}
+	if job.Agent != nil {
+		out.AgentId = job.Agent.ID
+	}
+	return out
+}
+
+// jobSubmitRPCs maps the operations of jobs to the rpc that submits them
+var jobSubmitRPCs = map[models.JobOperation]string{
+	models.JobOperationCreate:  "SubmitCreateWorkspace",
+	models.JobOperationStart:   "SubmitStartWorkspace",
+	models.JobOperationStop:    "SubmitStopWorkspace",
+	models.JobOperationDestroy: "SubmitDestroyWorkspace",
+}
+
+// formatJobWithAgent
+//
+//	Formats a job record into a ws.Job including the token of the agent
+//	produced by the job. The token is read from the current state of the
+//	workspace and is only returned while the workspace still runs the agent
+//	of the job and to callers that are permitted to submit the job.
+func (s *ProvisionerApiServer) formatJobWithAgent(ctx context.Context, job *models.Job) *ws.Job {
+	out := formatJob(job)
+	if job.Status != models.JobStatusSucceeded || job.Agent == nil {
+		return out
+	}
+
+	if s.authorizer != nil {
+		identity, _ := ctx.Value("identity").(*ClientIdentity)
+		if !s.authorizer.Authorize(identity, jobSubmitRPCs[job.Operation]) {
+			return out
+		}
+	}
+
+	agent, err := provisioner.ParseStatefileForAgent(s.Provisioner.Backend, job.WorkspaceID)
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("failed to read agent of job %d: %v", job.ID, err))
+		return out
+	}
+	if agent.ID == job.Agent.ID {
+		out.AgentToken = agent.Token
+	}
+
+	return out
+}
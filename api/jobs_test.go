@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gigo-ws/joblock"
+	"gigo-ws/models"
+	"gigo-ws/provisioner"
+	"gigo-ws/provisioner/backend"
+
+	"github.com/gage-technologies/gigo-lib/config"
+	"github.com/gage-technologies/gigo-lib/logging"
+	"github.com/gage-technologies/gigo-lib/storage"
+)
+
+func TestGetJob(t *testing.T) {
+	storageEngine, err := storage.CreateFileSystemStorage("/tmp/gigo-ws-get-job-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll("/tmp/gigo-ws-get-job-test")
+
+	s := &ProvisionerApiServer{
+		ProvisionerApiServerOptions: ProvisionerApiServerOptions{
+			ID:            1,
+			StorageEngine: storageEngine,
+			JobLocker:     joblock.NewMemoryLocker(1),
+		},
+	}
+
+	_, err = getJob(s, 69)
+	if !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound, got %v", err)
+	}
+
+	// save a running job that holds the provisioner job of the workspace
+	lock, err := acquireProvisionerJob(s, 420)
+	if err != nil || lock == nil {
+		t.Fatalf("failed to acquire provisioner job: %v", err)
+	}
+
+	job := &models.Job{
+		ID:          69,
+		WorkspaceID: 420,
+		Operation:   models.JobOperationCreate,
+		Status:      models.JobStatusRunning,
+		NodeID:      1,
+		CreatedAt:   time.Now(),
+		StartedAt:   time.Now(),
+	}
+	err = saveJob(storageEngine, job)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := getJob(s, 69)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Status != models.JobStatusRunning || loaded.WorkspaceID != 420 || loaded.Operation != models.JobOperationCreate {
+		t.Fatalf("unexpected job: %+v", loaded)
+	}
+
+	// releasing the provisioner job without finishing the job orphans it
+	err = lock.Release()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err = getJob(s, 69)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Status != models.JobStatusFailed || loaded.Error == "" || loaded.FinishedAt.IsZero() {
+		t.Fatalf("expected orphaned job to be failed: %+v", loaded)
+	}
+
+	// finished jobs are returned as is
+	job.ID = 70
+	job.Status = models.JobStatusSucceeded
+	job.Agent = &models.Agent{ID: 42, Token: "token"}
+	err = saveJob(storageEngine, job)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err = getJob(s, 70)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Status != models.JobStatusSucceeded || loaded.Agent == nil || loaded.Agent.ID != 42 {
+		t.Fatalf("unexpected job: %+v", loaded)
+	}
+
+	// the agent token is never persisted with the record
+	if loaded.Agent.Token != "" {
+		t.Fatalf("expected agent token not to be persisted: %+v", loaded.Agent)
+	}
+
+	formatted := formatJob(loaded)
+	if formatted.GetAgentId() != 42 || formatted.GetAgentToken() != "" || formatted.GetFinishedAt() != 0 {
+		t.Fatalf("unexpected formatted job: %+v", formatted)
+	}
+}
+
+func TestFormatJobWithAgent(t *testing.T) {
+	root := t.TempDir()
+	pb, err := backend.NewProvisionerBackendFS(config.StorageFSConfig{Root: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger, err := logging.CreateBasicLogger(logging.NewDefaultBasicLoggerOptions(filepath.Join(root, "test.log")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.MkdirAll(filepath.Join(root, "states"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(
+		filepath.Join(root, "states", "420"),
+		[]byte(`{"outputs": {"agent_id": {"value": 42}, "agent_token": {"value": "token"}}}`),
+		0600,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth := newTestConfigAuth(t)
+	s := &ProvisionerApiServer{
+		ProvisionerApiServerOptions: ProvisionerApiServerOptions{
+			Provisioner: &provisioner.Provisioner{Backend: pb},
+			Logger:      logger,
+		},
+		authorizer: auth,
+	}
+
+	job := &models.Job{
+		ID:          70,
+		WorkspaceID: 420,
+		Operation:   models.JobOperationCreate,
+		Status:      models.JobStatusSucceeded,
+		Agent:       &models.Agent{ID: 42},
+	}
+
+	// the token is read from the state for callers that can submit the job
+	ctx := context.WithValue(context.TODO(), "identity", &ClientIdentity{ID: "core"})
+	formatted := s.formatJobWithAgent(ctx, job)
+	if formatted.GetAgentId() != 42 || formatted.GetAgentToken() != "token" {
+		t.Fatalf("unexpected formatted job: %+v", formatted)
+	}
+
+	// read only callers do not receive the token
+	ctx = context.WithValue(context.TODO(), "identity", &ClientIdentity{ID: "dashboard"})
+	formatted = s.formatJobWithAgent(ctx, job)
+	if formatted.GetAgentId() != 42 || formatted.GetAgentToken() != "" {
+		t.Fatalf("unexpected formatted job: %+v", formatted)
+	}
+
+	// the token of a replaced agent is not returned
+	job.Agent.ID = 41
+	ctx = context.WithValue(context.TODO(), "identity", &ClientIdentity{ID: "core"})
+	formatted = s.formatJobWithAgent(ctx, job)
+	if formatted.GetAgentToken() != "" {
+		t.Fatalf("unexpected formatted job: %+v", formatted)
+	}
+}
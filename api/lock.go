@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gigo-ws/protos/ws"
+	"gigo-ws/provisioner/backend"
+)
+
+// ForceUnlock
+//
+//	Removes a terraform state lock that was left behind by an operation
+//	that died before releasing it. The lock is only removed when no node
+//	of the cluster holds the provisioner job of the workspace since a live
+//	job may still own the lock.
+func (s *ProvisionerApiServer) ForceUnlock(ctx context.Context, request *ws.ForceUnlockRequest) (*ws.ForceUnlockResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("ForceUnlock (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.ForceUnlockResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	// acquire the provisioner job for the workspace across the cluster - this
+	// guarantees there is no live owner of the lock and that no operation can
+	// begin while we remove the lock
+	lock, err := acquireProvisionerJob(s, request.GetWorkspaceId())
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("ForceUnlock (%d): failed to acquire provisioner job: %v", ctx.Value("id"), err))
+		return &ws.ForceUnlockResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	// refuse to unlock while an operation is active for the workspace
+	if lock == nil {
+		return &ws.ForceUnlockResponse{
+			Status: ws.ResponseCode_ALTERNATIVE_REQUEST_ACTIVE,
+			Error: &ws.Error{
+				GoError: "workspace has an active provisioner job that may own the lock",
+			},
+		}, nil
+	}
+
+	// release the provisioner job once the operation completes
+	defer releaseProvisionerJob(s, lock)
+
+	stateLock, err := forceUnlockWorkspace(ctx, forceUnlockOptions{
+		Provisioner: s.Provisioner,
+		Logger:      s.Logger,
+		WorkspaceID: request.GetWorkspaceId(),
+		LockID:      request.GetLockId(),
+	})
+	if err != nil {
+		if errors.Is(err, ErrStateNotLocked) {
+			return &ws.ForceUnlockResponse{
+				Status: ws.ResponseCode_NOT_FOUND,
+			}, nil
+		}
+		if errors.Is(err, backend.ErrLockIDMismatch) {
+			return &ws.ForceUnlockResponse{
+				Status: ws.ResponseCode_MALFORMED_REQUEST,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			}, nil
+		}
+		s.Logger.Warn(fmt.Errorf("ForceUnlock (%d): failed to force unlock workspace: %v", ctx.Value("id"), err))
+		return &ws.ForceUnlockResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	return &ws.ForceUnlockResponse{
+		Status: ws.ResponseCode_SUCCESS,
+		Lock:   formatStateLock(stateLock),
+	}, nil
+}
+
+// formatStateLock
+//
+//	Helper function to format a backend.LockInfo into a ws.StateLock
+func formatStateLock(lock *backend.LockInfo) *ws.StateLock {
+	return &ws.StateLock{
+		Id:        lock.ID,
+		Operation: lock.Operation,
+		Who:       lock.Who,
+		Version:   lock.Version,
+		Created:   lock.Created.Unix(),
+		Path:      lock.Path,
+		Info:      lock.Info,
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gigo-ws/provisioner"
+	"gigo-ws/provisioner/backend"
+
+	"github.com/gage-technologies/gigo-lib/config"
+	"github.com/gage-technologies/gigo-lib/logging"
+)
+
+func TestForceUnlockWorkspace(t *testing.T) {
+	root := t.TempDir()
+	pb, err := backend.NewProvisionerBackendFS(config.StorageFSConfig{Root: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger, err := logging.CreateBasicLogger(logging.NewDefaultBasicLoggerOptions(filepath.Join(root, "test.log")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := forceUnlockOptions{
+		Provisioner: &provisioner.Provisioner{Backend: pb},
+		Logger:      logger,
+		WorkspaceID: 420,
+	}
+
+	// ensure an unlocked workspace is reported
+	_, err = forceUnlockWorkspace(context.TODO(), opts)
+	if !errors.Is(err, ErrStateNotLocked) {
+		t.Fatalf("expected ErrStateNotLocked, got %v", err)
+	}
+
+	// write the lock info left behind by the local backend
+	err = os.MkdirAll(filepath.Join(root, "states", "states"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(
+		filepath.Join(root, "states", "states", ".420.lock.info"),
+		[]byte(`{"ID":"lock-420","Path":"states/420","Operation":"OperationTypeApply","Who":"root@gigo-ws-0","Version":"1.3.7","Created":"2023-01-31T18:00:00Z","Info":""}`),
+		0600,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ensure a mismatched lock id is rejected
+	opts.LockID = "lock-69"
+	_, err = forceUnlockWorkspace(context.TODO(), opts)
+	if !errors.Is(err, backend.ErrLockIDMismatch) {
+		t.Fatalf("expected ErrLockIDMismatch, got %v", err)
+	}
+
+	opts.LockID = "lock-420"
+	lock, err := forceUnlockWorkspace(context.TODO(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := formatStateLock(lock)
+	if res.GetId() != "lock-420" || res.GetWho() != "root@gigo-ws-0" || res.GetCreated() != 1675188000 {
+		t.Fatalf("unexpected lock: %+v", res)
+	}
+
+	_, err = forceUnlockWorkspace(context.TODO(), opts)
+	if !errors.Is(err, ErrStateNotLocked) {
+		t.Fatalf("expected ErrStateNotLocked after unlock, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gigo-ws/models"
+	"gigo-ws/protos/ws"
+	"gigo-ws/provisioner"
+)
+
+// GetOperationLogs
+//
+//	Lists the stored terraform operation logs of a workspace and returns
+//	the tail of the requested log or the most recent log if no log is
+//	requested. Failed operations reference their log in the command error.
+func (s *ProvisionerApiServer) GetOperationLogs(ctx context.Context, request *ws.GetOperationLogsRequest) (*ws.GetOperationLogsResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("GetOperationLogs (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.GetOperationLogsResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	// validate log id
+	if request.GetLogId() != "" {
+		_, err := models.ParseOperationLog(request.GetWorkspaceId(), request.GetLogId())
+		if err != nil {
+			s.Logger.Warn(fmt.Errorf("GetOperationLogs (%d): %v", ctx.Value("id"), err))
+			return &ws.GetOperationLogsResponse{
+				Status: ws.ResponseCode_MALFORMED_REQUEST,
+				Error: &ws.Error{
+					GoError: "invalid log id",
+				},
+			}, nil
+		}
+	}
+
+	logs, err := s.Provisioner.OperationLogs(request.GetWorkspaceId())
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("GetOperationLogs (%d): failed to list operation logs: %v", ctx.Value("id"), err))
+		return &ws.GetOperationLogsResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	res := &ws.GetOperationLogsResponse{
+		Status: ws.ResponseCode_SUCCESS,
+		Logs:   make([]*ws.OperationLog, 0, len(logs)),
+	}
+	for _, l := range logs {
+		res.Logs = append(res.Logs, &ws.OperationLog{
+			LogId:     l.ID,
+			Operation: l.Operation,
+			StartedAt: l.StartedAt.Unix(),
+		})
+	}
+
+	// default to the most recent log
+	res.LogId = request.GetLogId()
+	if res.LogId == "" {
+		if len(logs) == 0 {
+			return res, nil
+		}
+		res.LogId = logs[len(logs)-1].ID
+	}
+
+	// limit the size of the response
+	limit := int(request.GetTailBytes())
+	if limit <= 0 || limit > maxOperationLogBytes {
+		limit = maxOperationLogBytes
+	}
+
+	res.Content, res.Truncated, err = s.Provisioner.ReadOperationLog(request.GetWorkspaceId(), res.LogId, limit)
+	if err != nil {
+		if errors.Is(err, provisioner.ErrOperationLogNotFound) {
+			return &ws.GetOperationLogsResponse{
+				Status: ws.ResponseCode_NOT_FOUND,
+			}, nil
+		}
+		s.Logger.Warn(fmt.Errorf("GetOperationLogs (%d): failed to read operation log: %v", ctx.Value("id"), err))
+		return &ws.GetOperationLogsResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	return res, nil
+}
@@ -2,12 +2,23 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"reflect"
 	"runtime/debug"
 	"sync"
+	"time"
+
+	"gigo-ws/metrics"
+	"gigo-ws/protos/ws"
+	"gigo-ws/utils"
 
 	"github.com/bwmarrin/snowflake"
+	"github.com/gage-technologies/drpc-lib/muxserver"
 	"github.com/gage-technologies/gigo-lib/logging"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"storj.io/drpc"
 	"storj.io/drpc/drpcmetadata"
 )
@@ -16,6 +27,27 @@ type streamWrapper struct {
 	drpc.Stream
 	ctx context.Context
 	id  int64
+	// status response code of the last response sent on the stream
+	status string
+	// body encoded request message that was read from the stream to
+	// verify its signature - returned by the next call to MsgRecv
+	body []byte
+}
+
+// rawReceiver
+//
+//	Stream that can receive the encoded form of a message
+type rawReceiver interface {
+	RawRecv() ([]byte, error)
+}
+
+// rpcResponse
+//
+//	Encoding and response message type of an rpc used to
+//	respond to calls that are rejected by the middleware
+type rpcResponse struct {
+	enc drpc.Encoding
+	out reflect.Type
 }
 
 type DrpcMiddlewareOptions struct {
@@ -23,6 +55,14 @@ type DrpcMiddlewareOptions struct {
 	Handler       drpc.Handler
 	Logger        logging.Logger
 	SnowflakeNode *snowflake.Node
+	// Description of the rpcs served by the handler - required when auth is configured
+	Description drpc.Description
+	// Authenticator optional authenticator - auth is disabled when nil
+	Authenticator Authenticator
+	// Authorizer optional authorizer - all authenticated callers are authorized when nil
+	Authorizer Authorizer
+	// TLSIdentities optional lookup of the verified client certificate of a connection
+	TLSIdentities func(addr net.Addr) *TLSIdentity
 }
 
 type DrpcMiddleware struct {
@@ -30,21 +70,163 @@ type DrpcMiddleware struct {
 	handler       drpc.Handler
 	logger        logging.Logger
 	snowflakeNode *snowflake.Node
+	authenticator Authenticator
+	authorizer    Authorizer
+	tlsIdentities func(addr net.Addr) *TLSIdentity
+	responses     map[string]rpcResponse
 }
 
-func NewDrpcMiddleware(opts DrpcMiddlewareOptions) *DrpcMiddleware {
-	return &DrpcMiddleware{
+func NewDrpcMiddleware(opts DrpcMiddlewareOptions) (*DrpcMiddleware, error) {
+	mw := &DrpcMiddleware{
 		wg:            opts.WaitGroup,
 		handler:       opts.Handler,
 		logger:        opts.Logger,
 		snowflakeNode: opts.SnowflakeNode,
+		authenticator: opts.Authenticator,
+		authorizer:    opts.Authorizer,
+		tlsIdentities: opts.TLSIdentities,
+		responses:     make(map[string]rpcResponse),
+	}
+
+	// we need the response types to reject calls before they reach the handler
+	if opts.Authenticator != nil {
+		if opts.Description == nil {
+			return nil, fmt.Errorf("description is required when auth is configured")
+		}
+		err := mw.loadResponses(opts.Description)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rpc responses: %v", err)
+		}
 	}
+
+	return mw, nil
+}
+
+// loadResponses
+//
+//	Resolves the response message type of every rpc in the description.
+//	Unary rpcs return the response message and server streams send the
+//	response message via the Send method of the stream.
+func (mw *DrpcMiddleware) loadResponses(desc drpc.Description) error {
+	for i := 0; i < desc.NumMethods(); i++ {
+		rpc, enc, _, method, ok := desc.Method(i)
+		if !ok {
+			return fmt.Errorf("description returned invalid method for index %d", i)
+		}
+
+		mt := reflect.TypeOf(method)
+
+		var out reflect.Type
+		switch {
+		// unitary input, unitary output
+		case mt.NumOut() == 2:
+			out = mt.Out(0)
+		// stream output
+		case mt.NumIn() >= 2:
+			send, ok := mt.In(mt.NumIn() - 1).MethodByName("Send")
+			if !ok || send.Type.NumIn() != 1 {
+				return fmt.Errorf("rpc %q has no stream send method", rpc)
+			}
+			out = send.Type.In(0)
+		default:
+			return fmt.Errorf("rpc %q has an unsupported signature", rpc)
+		}
+
+		if _, ok := reflect.New(out.Elem()).Interface().(proto.Message); !ok {
+			return fmt.Errorf("rpc %q response is not a proto message", rpc)
+		}
+
+		mw.responses[rpc] = rpcResponse{
+			enc: enc,
+			out: out,
+		}
+	}
+	return nil
+}
+
+// reject
+//
+//	Responds to an rpc with the passed status code and error without
+//	calling the handler. Every response message carries a status field
+//	and an error field that are populated via reflection.
+func (mw *DrpcMiddleware) reject(stream drpc.Stream, rpc string, status ws.ResponseCode, goErr string) error {
+	res, ok := mw.responses[rpc]
+	if !ok {
+		return drpc.ProtocolError.New("unknown rpc: %q", rpc)
+	}
+
+	msg := reflect.New(res.out.Elem()).Interface().(proto.Message)
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+
+	if f := fields.ByName("status"); f != nil {
+		m.Set(f, protoreflect.ValueOfEnum(status.Number()))
+	}
+
+	if f := fields.ByName("error"); f != nil {
+		m.Set(f, protoreflect.ValueOfMessage((&ws.Error{GoError: goErr}).ProtoReflect()))
+	}
+
+	return stream.MsgSend(msg, res.enc)
+}
+
+// authenticate
+//
+//	Authenticates and authorizes the rpc. If the rpc is rejected the response
+//	code is returned with false. The identity is nil when auth is disabled.
+func (mw *DrpcMiddleware) authenticate(ctx context.Context, rpc string, metadata map[string]string, body []byte) (*ClientIdentity, ws.ResponseCode, bool) {
+	// skip auth if it is disabled
+	if mw.authenticator == nil {
+		return nil, ws.ResponseCode_SUCCESS, true
+	}
+
+	identity, err := mw.authenticator.Authenticate(ctx, rpc, metadata, body)
+	if err != nil {
+		if !errors.Is(err, ErrAuthenticationFailed) {
+			mw.logger.Warn(fmt.Errorf("failed to authenticate rpc %q: %v", rpc, err))
+		}
+		return nil, ws.ResponseCode_FAILED_AUTHENTICATION, false
+	}
+
+	if mw.authorizer != nil && !mw.authorizer.Authorize(identity, rpc) {
+		return identity, ws.ResponseCode_FAILED_AUTHORIZATION, false
+	}
+
+	return identity, ws.ResponseCode_SUCCESS, true
 }
 
 func (s *streamWrapper) Context() context.Context {
 	return s.ctx
 }
 
+// MsgRecv
+//
+//	Receives the next message from the underlying stream unless the
+//	request message was already read to verify its signature
+func (s *streamWrapper) MsgRecv(msg drpc.Message, enc drpc.Encoding) error {
+	if s.body != nil {
+		body := s.body
+		s.body = nil
+		return enc.Unmarshal(body, msg)
+	}
+	return s.Stream.MsgRecv(msg, enc)
+}
+
+// MsgSend
+//
+//	Sends the message on the underlying stream and tracks the
+//	response code of the message for the rpc metrics
+func (s *streamWrapper) MsgSend(msg drpc.Message, enc drpc.Encoding) error {
+	if m, ok := msg.(proto.Message); ok {
+		r := m.ProtoReflect()
+		f := r.Descriptor().Fields().ByName("status")
+		if f != nil && f.Enum() != nil && f.Enum().FullName() == ws.ResponseCode(0).Descriptor().FullName() {
+			s.status = ws.ResponseCode(r.Get(f).Enum()).String()
+		}
+	}
+	return s.Stream.MsgSend(msg, enc)
+}
+
 func (mw *DrpcMiddleware) initRpc(rpc string, metadata map[string]string) int64 {
 	// create unique id for the request
 	id := mw.snowflakeNode.Generate().Int64()
@@ -77,11 +259,74 @@ func (mw *DrpcMiddleware) completeRpc(id int64, rpc string) {
 func (mw *DrpcMiddleware) HandleRPC(stream drpc.Stream, rpc string) (err error) {
 	fmt.Println("starting: ", rpc)
 	metadata, _ := drpcmetadata.Get(stream.Context())
-	id := mw.initRpc(rpc, metadata)
+	id := mw.initRpc(rpc, sanitizeMetadata(metadata))
 	defer mw.completeRpc(id, rpc)
-	return mw.handler.HandleRPC(&streamWrapper{
+
+	// wrap the stream so that the response code is captured for the metrics
+	wrapper := &streamWrapper{
 		Stream: stream,
-		ctx:    context.WithValue(stream.Context(), "id", id),
 		id:     id,
-	}, rpc)
+		status: "UNKNOWN",
+	}
+	start := time.Now()
+	defer func() {
+		metrics.ObserveRPC(rpc, wrapper.status, time.Since(start))
+	}()
+
+	ctx := context.WithValue(stream.Context(), "id", id)
+
+	// resolve the client certificate of the connection for mtls callers
+	if mw.tlsIdentities != nil {
+		addr, _ := ctx.Value(muxserver.RemoteAddrKey).(net.Addr)
+		if tlsIdentity := mw.tlsIdentities(addr); tlsIdentity != nil {
+			ctx = context.WithValue(ctx, "tls_identity", tlsIdentity)
+		}
+	}
+
+	// read the request message of signed calls since the signature covers it - every
+	// rpc of the api receives exactly one message before it responds
+	if mw.authenticator != nil && metadata[utils.AuthMetadataSignature] != "" {
+		receiver, ok := stream.(rawReceiver)
+		if !ok {
+			return mw.reject(wrapper, rpc, ws.ResponseCode_FAILED_AUTHENTICATION, "stream does not support signed requests")
+		}
+		body, err := receiver.RawRecv()
+		if err != nil {
+			return err
+		}
+		// an empty message must still be returned to the handler
+		if body == nil {
+			body = []byte{}
+		}
+		wrapper.body = body
+	}
+
+	// authenticate the caller before handing off to the handler
+	identity, status, ok := mw.authenticate(ctx, rpc, metadata, wrapper.body)
+	if !ok {
+		mw.logger.Warnf("rejected rpc %q\n    id: %d\n    status: %s", rpc, id, status.String())
+		return mw.reject(wrapper, rpc, status, status.String())
+	}
+
+	if identity != nil {
+		ctx = context.WithValue(ctx, "identity", identity)
+	}
+
+	wrapper.ctx = ctx
+	return mw.handler.HandleRPC(wrapper, rpc)
+}
+
+// sanitizeMetadata
+//
+//	Returns a copy of the metadata with credentials redacted for logging
+func sanitizeMetadata(metadata map[string]string) map[string]string {
+	sanitized := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		switch k {
+		case utils.AuthMetadataAuthorization, utils.AuthMetadataSignature:
+			v = "<redacted>"
+		}
+		sanitized[k] = v
+	}
+	return sanitized
 }
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gigo-ws/models"
+	"gigo-ws/protos/ws"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// PlanWorkspace
+//
+//	Plans an operation against a workspace and returns the resource
+//	changes that terraform would make without modifying the workspace
+func (s *ProvisionerApiServer) PlanWorkspace(ctx context.Context, request *ws.PlanWorkspaceRequest) (*ws.PlanWorkspaceResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("PlanWorkspace (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.PlanWorkspaceResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	err := validatePlanWorkspaceRequest(request)
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("PlanWorkspace (%d): invalid plan workspace request: %v", ctx.Value("id"), err))
+		return &ws.PlanWorkspaceResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	s.Logger.Debug(fmt.Errorf("PlanWorkspace (%d): beginning workspace plan: %d", ctx.Value("id"), request.GetWorkspaceId()))
+
+	// acquire the provisioner job for the workspace across the cluster - the
+	// plan shares the module directory and statefile with the other operations
+	lock, err := acquireProvisionerJob(s, request.GetWorkspaceId())
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("PlanWorkspace (%d): failed to acquire provisioner job: %v", ctx.Value("id"), err))
+		return &ws.PlanWorkspaceResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	// handle the case that there is an active provisioner job
+	if lock == nil {
+		return &ws.PlanWorkspaceResponse{
+			Status: ws.ResponseCode_ALTERNATIVE_REQUEST_ACTIVE,
+		}, nil
+	}
+
+	// release the provisioner job once the operation completes
+	defer releaseProvisionerJob(s, lock)
+
+	// cancel the operation if cancellation is requested for the workspace
+	ctx, cancel := provisionerJobContext(ctx, lock)
+	defer cancel()
+
+	opts := planWorkspaceOptions{
+		Provisioner:     s.Provisioner,
+		StorageEngine:   s.StorageEngine,
+		Logger:          s.Logger,
+		WorkspaceID:     request.GetWorkspaceId(),
+		Operation:       models.JobOperation(request.GetOperation()),
+		RegistryCaches:  s.RegistryCaches,
+		WsHostOverrides: s.WsHostOverrides,
+	}
+	if opts.Operation == models.JobOperationCreate {
+		opts.TemplateOpts = s.formatCreateWorkspaceOptions(request.GetCreate()).TemplateOpts
+	}
+
+	// perform workspace plan
+	plan, err := planWorkspace(ctx, opts)
+	if err != nil {
+		// report the operation as cancelled if cancellation was requested
+		if lock.IsCancelled() {
+			s.Logger.Infof("PlanWorkspace (%d): workspace plan cancelled: %d", ctx.Value("id"), request.GetWorkspaceId())
+			return &ws.PlanWorkspaceResponse{
+				Status: ws.ResponseCode_OPERATION_CANCELLED,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			}, nil
+		}
+		if errors.Is(err, ErrWorkspaceNotFound) {
+			return &ws.PlanWorkspaceResponse{
+				Status: ws.ResponseCode_NOT_FOUND,
+			}, nil
+		}
+		s.Logger.Warn(fmt.Errorf("PlanWorkspace (%d): failed to plan workspace: %v", ctx.Value("id"), err))
+		status, wsErr := formatOperationError(err)
+		return &ws.PlanWorkspaceResponse{
+			Status: status,
+			Error:  wsErr,
+		}, nil
+	}
+
+	s.Logger.Debug(fmt.Errorf("PlanWorkspace (%d): completed workspace plan: %d", ctx.Value("id"), request.GetWorkspaceId()))
+
+	res := formatPlan(plan)
+	res.Status = ws.ResponseCode_SUCCESS
+	return res, nil
+}
+
+// validatePlanWorkspaceRequest
+//
+//	Helper function to validate ws.PlanWorkspaceRequest
+func validatePlanWorkspaceRequest(request *ws.PlanWorkspaceRequest) error {
+	switch request.GetOperation() {
+	case ws.JobOperation_JOB_START, ws.JobOperation_JOB_STOP, ws.JobOperation_JOB_DESTROY:
+		return nil
+	case ws.JobOperation_JOB_CREATE:
+		// a create is planned from the parameters of the workspace
+		if request.GetCreate() == nil {
+			return fmt.Errorf("create parameters are required to plan a create")
+		}
+		if request.GetCreate().GetWorkspaceId() != request.GetWorkspaceId() {
+			return fmt.Errorf("create parameters are for a different workspace")
+		}
+		return validateCreateWorkspaceRequest(request.GetCreate())
+	default:
+		return fmt.Errorf("invalid operation: %d", request.GetOperation())
+	}
+}
+
+// formatPlan
+//
+//	Helper function to format the resource changes of a
+//	terraform plan into a ws.PlanWorkspaceResponse
+func formatPlan(plan *tfjson.Plan) *ws.PlanWorkspaceResponse {
+	res := &ws.PlanWorkspaceResponse{
+		ResourceChanges: make([]*ws.ResourceChange, 0, len(plan.ResourceChanges)),
+	}
+
+	for _, rc := range plan.ResourceChanges {
+		if rc.Change == nil {
+			continue
+		}
+
+		actions := make([]string, 0, len(rc.Change.Actions))
+		for _, action := range rc.Change.Actions {
+			actions = append(actions, string(action))
+		}
+
+		// count the changes the same way terraform summarizes a plan
+		switch {
+		case rc.Change.Actions.Replace():
+			res.Add++
+			res.Destroy++
+		case rc.Change.Actions.Create():
+			res.Add++
+		case rc.Change.Actions.Update():
+			res.Change++
+		case rc.Change.Actions.Delete():
+			res.Destroy++
+		}
+
+		res.ResourceChanges = append(res.ResourceChanges, &ws.ResourceChange{
+			Address: rc.Address,
+			Type:    rc.Type,
+			Name:    rc.Name,
+			Actions: actions,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"testing"
+
+	"gigo-ws/protos/ws"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func TestValidatePlanWorkspaceRequest(t *testing.T) {
+	create := &ws.CreateWorkspaceRequest{
+		WorkspaceId: 69,
+		OwnerId:     420,
+		OwnerEmail:  "test@gigo.dev",
+		OwnerName:   "test",
+		Disk:        10,
+		Cpu:         2,
+		Memory:      4,
+		Container:   "gigodev/test:latest",
+		AccessUrl:   "http://localhost:8080",
+	}
+
+	tests := []struct {
+		name    string
+		request *ws.PlanWorkspaceRequest
+		valid   bool
+	}{
+		{"start", &ws.PlanWorkspaceRequest{WorkspaceId: 69, Operation: ws.JobOperation_JOB_START}, true},
+		{"destroy", &ws.PlanWorkspaceRequest{WorkspaceId: 69, Operation: ws.JobOperation_JOB_DESTROY}, true},
+		{"create", &ws.PlanWorkspaceRequest{WorkspaceId: 69, Operation: ws.JobOperation_JOB_CREATE, Create: create}, true},
+		{"create without parameters", &ws.PlanWorkspaceRequest{WorkspaceId: 69, Operation: ws.JobOperation_JOB_CREATE}, false},
+		{"create for other workspace", &ws.PlanWorkspaceRequest{WorkspaceId: 70, Operation: ws.JobOperation_JOB_CREATE, Create: create}, false},
+		{"invalid operation", &ws.PlanWorkspaceRequest{WorkspaceId: 69, Operation: ws.JobOperation(42)}, false},
+	}
+
+	for _, tt := range tests {
+		err := validatePlanWorkspaceRequest(tt.request)
+		if tt.valid && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestFormatPlan(t *testing.T) {
+	plan := &tfjson.Plan{
+		ResourceChanges: []*tfjson.ResourceChange{
+			{
+				Address: "kubernetes_pod.main[0]",
+				Type:    "kubernetes_pod",
+				Name:    "main",
+				Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate}},
+			},
+			{
+				Address: "kubernetes_persistent_volume_claim.home",
+				Type:    "kubernetes_persistent_volume_claim",
+				Name:    "home",
+				Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionUpdate}},
+			},
+			{
+				Address: "coder_agent.main",
+				Type:    "coder_agent",
+				Name:    "main",
+				Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}},
+			},
+		},
+	}
+
+	res := formatPlan(plan)
+	if res.GetAdd() != 1 || res.GetChange() != 1 || res.GetDestroy() != 1 {
+		t.Fatalf("unexpected plan summary: add %d change %d destroy %d", res.GetAdd(), res.GetChange(), res.GetDestroy())
+	}
+
+	if len(res.GetResourceChanges()) != 3 {
+		t.Fatalf("expected 3 resource changes, got %d", len(res.GetResourceChanges()))
+	}
+
+	pod := res.GetResourceChanges()[0]
+	if pod.GetAddress() != "kubernetes_pod.main[0]" || len(pod.GetActions()) != 2 || pod.GetActions()[0] != "delete" {
+		t.Fatalf("unexpected resource change: %+v", pod)
+	}
+}
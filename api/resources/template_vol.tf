@@ -202,4 +202,18 @@ resource "kubernetes_pod" "main" {
 
 <HOST_ALIASES>
   }
-}
\ No newline at end of file
+}
+
+# outputs read by the provisioner to retrieve the agent and workspace state
+output "agent_id" {
+  value = gigo_agent.main.id
+}
+
+output "agent_token" {
+  value     = gigo_agent.main.token
+  sensitive = true
+}
+
+output "start_count" {
+  value = data.gigo_workspace.me.start_count
+}
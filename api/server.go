@@ -10,6 +10,9 @@ import (
 	"time"
 
 	"gigo-ws/config"
+	"gigo-ws/joblock"
+	"gigo-ws/metrics"
+	"gigo-ws/models"
 	"gigo-ws/volpool"
 
 	"gigo-ws/protos/ws"
@@ -24,12 +27,25 @@ import (
 )
 
 const (
-	ProvisionerJobPrefix = "provisioner/job/active"
+	ProvisionerJobPrefix = "provisioner/job"
+
+	// maxListWorkspacesLimit is the maximum page size of ListWorkspaces
+	maxListWorkspacesLimit = 1000
+
+	// maxCommandErrorLines is the number of trailing stderr lines of a
+	// failed terraform command that are returned to the caller - the full
+	// output is retrieved through the operation log of the command
+	maxCommandErrorLines = 20
+
+	// maxOperationLogBytes is the maximum number of trailing bytes of an
+	// operation log returned by GetOperationLogs
+	maxOperationLogBytes = 1024 * 1024
 )
 
 type ProvisionerApiServerOptions struct {
 	ID              int64
 	ClusterNode     cluster.Node
+	JobLocker       joblock.Locker
 	Provisioner     *provisioner.Provisioner
 	Volpool         *volpool.VolumePool
 	StorageEngine   storage.Storage
@@ -38,6 +54,8 @@ type ProvisionerApiServerOptions struct {
 	Port            int
 	RegistryCaches  []config.RegistryCacheConfig
 	WsHostOverrides map[string]string
+	Auth            config.AuthConfig
+	TLS             config.TLSConfig
 	Logger          logging.Logger
 }
 
@@ -51,6 +69,8 @@ type ProvisionerApiServer struct {
 	server   *muxserver.Server
 	wg       *sync.WaitGroup
 	Listener net.Listener
+	// authorizer of the callers - nil when auth is disabled
+	authorizer Authorizer
 }
 
 // NewProvisionerApiServer
@@ -64,6 +84,19 @@ func NewProvisionerApiServer(options ProvisionerApiServerOptions) (*ProvisionerA
 		return nil, fmt.Errorf("failed to created listener: %v", err)
 	}
 
+	// conditionally wrap the listener with tls and expose the
+	// client certificates of mtls callers to the middleware
+	var tlsIdentities func(addr net.Addr) *TLSIdentity
+	if options.TLS.Enabled {
+		tl, err := newTLSListener(listener, options.TLS)
+		if err != nil {
+			_ = listener.Close()
+			return nil, fmt.Errorf("failed to create tls listener: %v", err)
+		}
+		listener = tl
+		tlsIdentities = tl.Identity
+	}
+
 	// create a new context for the provisioner server
 	ctx, cancel := context.WithCancel(context.Background())
 	s := &ProvisionerApiServer{
@@ -86,13 +119,32 @@ func NewProvisionerApiServer(options ProvisionerApiServerOptions) (*ProvisionerA
 		return nil, fmt.Errorf("failed to register provisioner api server: %v", err)
 	}
 
-	// TODO: configure auth middleware
-	handler := NewDrpcMiddleware(DrpcMiddlewareOptions{
+	// configure auth from the server config
+	var authenticator Authenticator
+	var authorizer Authorizer
+	if options.Auth.Enabled {
+		auth, err := NewConfigAuth(options.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure auth: %v", err)
+		}
+		authenticator = auth
+		authorizer = auth
+	}
+	s.authorizer = authorizer
+
+	handler, err := NewDrpcMiddleware(DrpcMiddlewareOptions{
 		WaitGroup:     s.wg,
 		Logger:        options.Logger,
 		Handler:       mux,
 		SnowflakeNode: options.SnowflakeNode,
+		Description:   ws.DRPCGigoWSDescription{},
+		Authenticator: authenticator,
+		Authorizer:    authorizer,
+		TLSIdentities: tlsIdentities,
 	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create middleware: %v", err)
+	}
 
 	// create a new server
 	s.server = muxserver.New(handler)
@@ -111,7 +163,7 @@ func (s *ProvisionerApiServer) Serve() error {
 // Close
 //
 //	Gracefully close the server by rejecting all new connections
-//	and waiting for existing connections to finish.
+//	and waiting for existing connections and submitted jobs to finish.
 func (s *ProvisionerApiServer) Close() error {
 	// cancel the context to block any new connections from occurring
 	s.cancel()
@@ -145,16 +197,10 @@ func (s *ProvisionerApiServer) CreateWorkspace(ctx context.Context, request *ws.
 
 	s.Logger.Debug(fmt.Errorf("CreateWorkspace (%d): beginning workspace creation: %d", ctx.Value("id"), request.GetWorkspaceId()))
 
-	// defer the removal of the provisioner job - if we fail or don't get the job
-	// this will become a no-op
-	defer func() {
-		_ = removeProvisionerJob(s, request.GetWorkspaceId())
-	}()
-
-	// register provisioner job with the cluster
-	ok, err := registerProvisionerJob(s, request.GetWorkspaceId())
+	// acquire the provisioner job for the workspace across the cluster
+	lock, err := acquireProvisionerJob(s, request.GetWorkspaceId())
 	if err != nil {
-		s.Logger.Warn(fmt.Errorf("CreateWorkspace (%d): failed to register provisioner job: %v", ctx.Value("id"), err))
+		s.Logger.Warn(fmt.Errorf("CreateWorkspace (%d): failed to acquire provisioner job: %v", ctx.Value("id"), err))
 		return &ws.CreateWorkspaceResponse{
 			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
 			Error: &ws.Error{
@@ -164,42 +210,40 @@ func (s *ProvisionerApiServer) CreateWorkspace(ctx context.Context, request *ws.
 	}
 
 	// handle the case that there is an active provisioner job
-	if !ok {
+	if lock == nil {
 		return &ws.CreateWorkspaceResponse{
 			Status: ws.ResponseCode_ALTERNATIVE_REQUEST_ACTIVE,
 		}, nil
 	}
 
+	// release the provisioner job once the operation completes
+	defer releaseProvisionerJob(s, lock)
+
+	// cancel the operation if cancellation is requested for the workspace
+	ctx, cancel := provisionerJobContext(ctx, lock)
+	defer cancel()
+
 	// format request into createWorkspaceOptions
-	opts := createWorkspaceOptions{
-		Provisioner:   s.Provisioner,
-		StorageEngine: s.StorageEngine,
-		Logger:        s.Logger,
-		TemplateOpts: templateOptions{
-			WorkspaceID: request.GetWorkspaceId(),
-			OwnerID:     request.GetOwnerId(),
-			OwnerEmail:  request.GetOwnerEmail(),
-			OwnerName:   request.GetOwnerName(),
-			Disk:        int(request.GetDisk()),
-			CPU:         int(request.GetCpu()),
-			Memory:      int(request.GetMemory()),
-			Container:   request.GetContainer(),
-			AccessUrl:   request.GetAccessUrl(),
-		},
-		RegistryCaches:  s.RegistryCaches,
-		WsHostOverrides: s.WsHostOverrides,
-		Volpool:         s.Volpool,
-	}
+	opts := s.formatCreateWorkspaceOptions(request)
 
 	// perform workspace creation
 	agent, _, err := createWorkspace(ctx, opts)
 	if err != nil {
+		// report the operation as cancelled if cancellation was requested
+		if lock.IsCancelled() {
+			s.Logger.Infof("CreateWorkspace (%d): workspace create cancelled: %d", ctx.Value("id"), request.GetWorkspaceId())
+			return &ws.CreateWorkspaceResponse{
+				Status: ws.ResponseCode_OPERATION_CANCELLED,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			}, nil
+		}
 		s.Logger.Warn(fmt.Errorf("CreateWorkspace (%d): failed to create workspace: %v", ctx.Value("id"), err))
+		status, wsErr := formatOperationError(err)
 		return &ws.CreateWorkspaceResponse{
-			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
-			Error: &ws.Error{
-				GoError: err.Error(),
-			},
+			Status: status,
+			Error:  wsErr,
 		}, nil
 	}
 
@@ -241,16 +285,10 @@ func (s *ProvisionerApiServer) StartWorkspace(ctx context.Context, request *ws.S
 
 	s.Logger.Debug(fmt.Errorf("StartWorkspace (%d): beginning workspace start: %d", ctx.Value("id"), request.GetWorkspaceId()))
 
-	// defer the removal of the provisioner job - if we fail or don't get the job
-	// this will become a no-op
-	defer func() {
-		_ = removeProvisionerJob(s, request.GetWorkspaceId())
-	}()
-
-	// register provisioner job with the cluster
-	ok, err := registerProvisionerJob(s, request.GetWorkspaceId())
+	// acquire the provisioner job for the workspace across the cluster
+	lock, err := acquireProvisionerJob(s, request.GetWorkspaceId())
 	if err != nil {
-		s.Logger.Warn(fmt.Errorf("StartWorkspace (%d): failed to register provisioner job: %v", ctx.Value("id"), err))
+		s.Logger.Warn(fmt.Errorf("StartWorkspace (%d): failed to acquire provisioner job: %v", ctx.Value("id"), err))
 		return &ws.StartWorkspaceResponse{
 			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
 			Error: &ws.Error{
@@ -260,12 +298,19 @@ func (s *ProvisionerApiServer) StartWorkspace(ctx context.Context, request *ws.S
 	}
 
 	// handle the case that there is an active provisioner job
-	if !ok {
+	if lock == nil {
 		return &ws.StartWorkspaceResponse{
 			Status: ws.ResponseCode_ALTERNATIVE_REQUEST_ACTIVE,
 		}, nil
 	}
 
+	// release the provisioner job once the operation completes
+	defer releaseProvisionerJob(s, lock)
+
+	// cancel the operation if cancellation is requested for the workspace
+	ctx, cancel := provisionerJobContext(ctx, lock)
+	defer cancel()
+
 	// perform workspace stop
 	agent, _, err := startWorkspace(ctx, startWorkspaceOptions{
 		Provisioner:   s.Provisioner,
@@ -274,17 +319,26 @@ func (s *ProvisionerApiServer) StartWorkspace(ctx context.Context, request *ws.S
 		WorkspaceID:   request.GetWorkspaceId(),
 	})
 	if err != nil {
+		// report the operation as cancelled if cancellation was requested
+		if lock.IsCancelled() {
+			s.Logger.Infof("StartWorkspace (%d): workspace start cancelled: %d", ctx.Value("id"), request.GetWorkspaceId())
+			return &ws.StartWorkspaceResponse{
+				Status: ws.ResponseCode_OPERATION_CANCELLED,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			}, nil
+		}
 		s.Logger.Warn(fmt.Errorf("StartWorkspace (%d): failed to start workspace: %v", ctx.Value("id"), err))
 		if errors.Is(err, ErrWorkspaceNotFound) {
 			return &ws.StartWorkspaceResponse{
 				Status: ws.ResponseCode_NOT_FOUND,
 			}, nil
 		}
+		status, wsErr := formatOperationError(err)
 		return &ws.StartWorkspaceResponse{
-			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
-			Error: &ws.Error{
-				GoError: err.Error(),
-			},
+			Status: status,
+			Error:  wsErr,
 		}, nil
 	}
 
@@ -315,16 +369,10 @@ func (s *ProvisionerApiServer) StopWorkspace(ctx context.Context, request *ws.St
 
 	s.Logger.Debug(fmt.Errorf("StopWorkspace (%d): beginning workspace stop: %d", ctx.Value("id"), request.GetWorkspaceId()))
 
-	// defer the removal of the provisioner job - if we fail or don't get the job
-	// this will become a no-op
-	defer func() {
-		_ = removeProvisionerJob(s, request.GetWorkspaceId())
-	}()
-
-	// register provisioner job with the cluster
-	ok, err := registerProvisionerJob(s, request.GetWorkspaceId())
+	// acquire the provisioner job for the workspace across the cluster
+	lock, err := acquireProvisionerJob(s, request.GetWorkspaceId())
 	if err != nil {
-		s.Logger.Warn(fmt.Errorf("StopWorkspace (%d): failed to register provisioner job: %v", ctx.Value("id"), err))
+		s.Logger.Warn(fmt.Errorf("StopWorkspace (%d): failed to acquire provisioner job: %v", ctx.Value("id"), err))
 		return &ws.StopWorkspaceResponse{
 			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
 			Error: &ws.Error{
@@ -334,12 +382,19 @@ func (s *ProvisionerApiServer) StopWorkspace(ctx context.Context, request *ws.St
 	}
 
 	// handle the case that there is an active provisioner job
-	if !ok {
+	if lock == nil {
 		return &ws.StopWorkspaceResponse{
 			Status: ws.ResponseCode_ALTERNATIVE_REQUEST_ACTIVE,
 		}, nil
 	}
 
+	// release the provisioner job once the operation completes
+	defer releaseProvisionerJob(s, lock)
+
+	// cancel the operation if cancellation is requested for the workspace
+	ctx, cancel := provisionerJobContext(ctx, lock)
+	defer cancel()
+
 	// perform workspace stop
 	_, _, err = stopWorkspace(ctx, stopWorkspaceOptions{
 		Provisioner:   s.Provisioner,
@@ -348,17 +403,26 @@ func (s *ProvisionerApiServer) StopWorkspace(ctx context.Context, request *ws.St
 		WorkspaceID:   request.GetWorkspaceId(),
 	})
 	if err != nil {
+		// report the operation as cancelled if cancellation was requested
+		if lock.IsCancelled() {
+			s.Logger.Infof("StopWorkspace (%d): workspace stop cancelled: %d", ctx.Value("id"), request.GetWorkspaceId())
+			return &ws.StopWorkspaceResponse{
+				Status: ws.ResponseCode_OPERATION_CANCELLED,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			}, nil
+		}
 		s.Logger.Warn(fmt.Errorf("StopWorkspace (%d): failed to stop workspace: %v", ctx.Value("id"), err))
 		if errors.Is(err, ErrWorkspaceNotFound) {
 			return &ws.StopWorkspaceResponse{
 				Status: ws.ResponseCode_NOT_FOUND,
 			}, nil
 		}
+		status, wsErr := formatOperationError(err)
 		return &ws.StopWorkspaceResponse{
-			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
-			Error: &ws.Error{
-				GoError: err.Error(),
-			},
+			Status: status,
+			Error:  wsErr,
 		}, nil
 	}
 
@@ -386,16 +450,10 @@ func (s *ProvisionerApiServer) DestroyWorkspace(ctx context.Context, request *ws
 
 	s.Logger.Debug(fmt.Errorf("DestroyWorkspace (%d): beginning workspace destroy: %d", ctx.Value("id"), request.GetWorkspaceId()))
 
-	// defer the removal of the provisioner job - if we fail or don't get the job
-	// this will become a no-op
-	defer func() {
-		_ = removeProvisionerJob(s, request.GetWorkspaceId())
-	}()
-
-	// register provisioner job with the cluster
-	ok, err := registerProvisionerJob(s, request.GetWorkspaceId())
+	// acquire the provisioner job for the workspace across the cluster
+	lock, err := acquireProvisionerJob(s, request.GetWorkspaceId())
 	if err != nil {
-		s.Logger.Warn(fmt.Errorf("DestroyWorkspace (%d): failed to register provisioner job: %v", ctx.Value("id"), err))
+		s.Logger.Warn(fmt.Errorf("DestroyWorkspace (%d): failed to acquire provisioner job: %v", ctx.Value("id"), err))
 		return &ws.DestroyWorkspaceResponse{
 			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
 			Error: &ws.Error{
@@ -405,12 +463,19 @@ func (s *ProvisionerApiServer) DestroyWorkspace(ctx context.Context, request *ws
 	}
 
 	// handle the case that there is an active provisioner job
-	if !ok {
+	if lock == nil {
 		return &ws.DestroyWorkspaceResponse{
 			Status: ws.ResponseCode_ALTERNATIVE_REQUEST_ACTIVE,
 		}, nil
 	}
 
+	// release the provisioner job once the operation completes
+	defer releaseProvisionerJob(s, lock)
+
+	// cancel the operation if cancellation is requested for the workspace
+	ctx, cancel := provisionerJobContext(ctx, lock)
+	defer cancel()
+
 	// perform workspace stop
 	_, err = destroyWorkspace(ctx, destroyWorkspaceOptions{
 		Provisioner:   s.Provisioner,
@@ -420,6 +485,16 @@ func (s *ProvisionerApiServer) DestroyWorkspace(ctx context.Context, request *ws
 		Volpool:       s.Volpool,
 	})
 	if err != nil {
+		// report the operation as cancelled if cancellation was requested
+		if lock.IsCancelled() {
+			s.Logger.Infof("DestroyWorkspace (%d): workspace destroy cancelled: %d", ctx.Value("id"), request.GetWorkspaceId())
+			return &ws.DestroyWorkspaceResponse{
+				Status: ws.ResponseCode_OPERATION_CANCELLED,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			}, nil
+		}
 		// we treat a not-found as an idempotent destroy since we want it gone anyway
 		if errors.Is(err, ErrWorkspaceNotFound) {
 			return &ws.DestroyWorkspaceResponse{
@@ -427,7 +502,50 @@ func (s *ProvisionerApiServer) DestroyWorkspace(ctx context.Context, request *ws
 			}, nil
 		}
 		s.Logger.Warn(fmt.Errorf("DestroyWorkspace (%d): failed to destroy workspace: %v", ctx.Value("id"), err))
+		status, wsErr := formatOperationError(err)
 		return &ws.DestroyWorkspaceResponse{
+			Status: status,
+			Error:  wsErr,
+		}, nil
+	}
+
+	s.Logger.Debug(fmt.Errorf("DestroyWorkspace (%d): completed workspace destroy: %d", ctx.Value("id"), request.GetWorkspaceId()))
+
+	return &ws.DestroyWorkspaceResponse{
+		Status: ws.ResponseCode_SUCCESS,
+	}, nil
+}
+
+// GetWorkspace
+//
+//	Retrieves the current state, agent, kubernetes resources and
+//	resource sizing of an existing workspace without modifying it
+func (s *ProvisionerApiServer) GetWorkspace(ctx context.Context, request *ws.GetWorkspaceRequest) (*ws.GetWorkspaceResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("GetWorkspace (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.GetWorkspaceResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	// retrieve the workspace status
+	status, err := getWorkspace(ctx, getWorkspaceOptions{
+		Provisioner:   s.Provisioner,
+		StorageEngine: s.StorageEngine,
+		WorkspaceID:   request.GetWorkspaceId(),
+	})
+	if err != nil {
+		if errors.Is(err, ErrWorkspaceNotFound) {
+			return &ws.GetWorkspaceResponse{
+				Status: ws.ResponseCode_NOT_FOUND,
+			}, nil
+		}
+		s.Logger.Warn(fmt.Errorf("GetWorkspace (%d): failed to get workspace: %v", ctx.Value("id"), err))
+		return &ws.GetWorkspaceResponse{
 			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
 			Error: &ws.Error{
 				GoError: err.Error(),
@@ -435,13 +553,306 @@ func (s *ProvisionerApiServer) DestroyWorkspace(ctx context.Context, request *ws
 		}, nil
 	}
 
-	s.Logger.Debug(fmt.Errorf("DestroyWorkspace (%d): completed workspace destroy: %d", ctx.Value("id"), request.GetWorkspaceId()))
+	// check for an active provisioner job for the workspace
+	job, err := getProvisionerJob(s, request.GetWorkspaceId())
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("GetWorkspace (%d): failed to check for active provisioner job: %v", ctx.Value("id"), err))
+		return &ws.GetWorkspaceResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
 
-	return &ws.DestroyWorkspaceResponse{
+	res := &ws.GetWorkspaceResponse{
+		Status:  ws.ResponseCode_SUCCESS,
+		State:   ws.WorkspaceState(status.State),
+		AgentId: status.AgentID,
+		PvcName: status.PVCName,
+		PodName: status.PodName,
+		Resources: &ws.WorkspaceResources{
+			Cpu:    int32(status.Resources.CPU),
+			Memory: int32(status.Resources.Memory),
+			Disk:   int32(status.Resources.Disk),
+		},
+		TerraformVersion: status.TerraformVersion,
+	}
+
+	// expose the owner of the active job so operators can see who holds the workspace
+	if job != nil {
+		res.ActiveJob = true
+		res.JobNodeId = job.NodeID
+		res.JobStartTime = job.StartTime.Unix()
+	}
+
+	return res, nil
+}
+
+// ListWorkspaces
+//
+//	Lists the workspaces managed by the provisioner in order of
+//	workspace id using cursor pagination and an optional state filter
+func (s *ProvisionerApiServer) ListWorkspaces(ctx context.Context, request *ws.ListWorkspacesRequest) (*ws.ListWorkspacesResponse, error) {
+	// validate cursor
+	if request.GetCursor() < 0 {
+		s.Logger.Warn(fmt.Errorf("ListWorkspaces (%d): invalid cursor: %d", ctx.Value("id"), request.GetCursor()))
+		return &ws.ListWorkspacesResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid cursor",
+			},
+		}, nil
+	}
+
+	// validate limit defaulting to the max page size when unset
+	limit := int(request.GetLimit())
+	if limit < 0 || limit > maxListWorkspacesLimit {
+		s.Logger.Warn(fmt.Errorf("ListWorkspaces (%d): invalid limit: %d", ctx.Value("id"), request.GetLimit()))
+		return &ws.ListWorkspacesResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: fmt.Sprintf("invalid limit - must be 0 <= x <= %d", maxListWorkspacesLimit),
+			},
+		}, nil
+	}
+	if limit == 0 {
+		limit = maxListWorkspacesLimit
+	}
+
+	// format state filter
+	states := make([]models.WorkspaceState, 0, len(request.GetStates()))
+	for _, state := range request.GetStates() {
+		states = append(states, models.WorkspaceState(state))
+	}
+
+	// list the workspaces
+	workspaces, nextCursor, err := listWorkspaces(ctx, listWorkspacesOptions{
+		Provisioner:   s.Provisioner,
+		StorageEngine: s.StorageEngine,
+		Cursor:        request.GetCursor(),
+		Limit:         limit,
+		States:        states,
+	})
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("ListWorkspaces (%d): failed to list workspaces: %v", ctx.Value("id"), err))
+		return &ws.ListWorkspacesResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	// format workspaces into protos
+	summaries := make([]*ws.WorkspaceSummary, 0, len(workspaces))
+	for _, w := range workspaces {
+		summary := &ws.WorkspaceSummary{
+			WorkspaceId: w.WorkspaceID,
+			State:       ws.WorkspaceState(w.State),
+		}
+		if !w.LastModified.IsZero() {
+			summary.LastModified = w.LastModified.Unix()
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return &ws.ListWorkspacesResponse{
+		Status:     ws.ResponseCode_SUCCESS,
+		Workspaces: summaries,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// CancelOperation
+//
+//	Cancels the in-flight provisioner operation of a workspace on
+//	whichever node of the cluster is executing it. The terraform process
+//	is interrupted so that it can stop gracefully and the operation then
+//	records a cancelled end state.
+func (s *ProvisionerApiServer) CancelOperation(ctx context.Context, request *ws.CancelOperationRequest) (*ws.CancelOperationResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("CancelOperation (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.CancelOperationResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	// request cancellation of the provisioner job from the node that holds it
+	job, err := s.JobLocker.Cancel(ctx, provisionerJobKey(request.GetWorkspaceId()))
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("CancelOperation (%d): failed to cancel provisioner job: %v", ctx.Value("id"), err))
+		return &ws.CancelOperationResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	// handle the case that there is no in-flight operation
+	if job == nil {
+		return &ws.CancelOperationResponse{
+			Status: ws.ResponseCode_NOT_FOUND,
+		}, nil
+	}
+
+	s.Logger.Infof("CancelOperation (%d): requested cancellation of workspace %d on node %d", ctx.Value("id"), request.GetWorkspaceId(), job.NodeID)
+
+	return &ws.CancelOperationResponse{
 		Status: ws.ResponseCode_SUCCESS,
+		NodeId: job.NodeID,
 	}, nil
 }
 
+// UpdateWorkspaceResources
+//
+//	Resizes the cpu, memory and disk of an existing workspace in place.
+//	The disk of a workspace can only grow and an active workspace has
+//	its pod restarted with the new sizing. Pooled volumes are expanded
+//	by the volume pool which requires a storage class that allows
+//	volume expansion.
+func (s *ProvisionerApiServer) UpdateWorkspaceResources(ctx context.Context, request *ws.UpdateWorkspaceResourcesRequest) (*ws.UpdateWorkspaceResourcesResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("UpdateWorkspaceResources (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.UpdateWorkspaceResourcesResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	// validate the new sizing
+	err := validateWorkspaceResources(request.GetCpu(), request.GetMemory(), request.GetDisk())
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("UpdateWorkspaceResources (%d): invalid workspace resources: %v", ctx.Value("id"), err))
+		return &ws.UpdateWorkspaceResourcesResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	s.Logger.Debug(fmt.Errorf("UpdateWorkspaceResources (%d): beginning workspace resource update: %d", ctx.Value("id"), request.GetWorkspaceId()))
+
+	// acquire the provisioner job for the workspace across the cluster
+	lock, err := acquireProvisionerJob(s, request.GetWorkspaceId())
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("UpdateWorkspaceResources (%d): failed to acquire provisioner job: %v", ctx.Value("id"), err))
+		return &ws.UpdateWorkspaceResourcesResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	// handle the case that there is an active provisioner job
+	if lock == nil {
+		return &ws.UpdateWorkspaceResourcesResponse{
+			Status: ws.ResponseCode_ALTERNATIVE_REQUEST_ACTIVE,
+		}, nil
+	}
+
+	// release the provisioner job once the operation completes
+	defer releaseProvisionerJob(s, lock)
+
+	// cancel the operation if cancellation is requested for the workspace
+	ctx, cancel := provisionerJobContext(ctx, lock)
+	defer cancel()
+
+	resources := models.WorkspaceResources{
+		CPU:    int(request.GetCpu()),
+		Memory: int(request.GetMemory()),
+		Disk:   int(request.GetDisk()),
+	}
+
+	// perform workspace resource update
+	_, err = updateWorkspaceResources(ctx, updateWorkspaceResourcesOptions{
+		Provisioner:   s.Provisioner,
+		StorageEngine: s.StorageEngine,
+		Volpool:       s.Volpool,
+		Logger:        s.Logger,
+		WorkspaceID:   request.GetWorkspaceId(),
+		Resources:     resources,
+	})
+	if err != nil {
+		// report the operation as cancelled if cancellation was requested
+		if lock.IsCancelled() {
+			s.Logger.Infof("UpdateWorkspaceResources (%d): workspace resource update cancelled: %d", ctx.Value("id"), request.GetWorkspaceId())
+			return &ws.UpdateWorkspaceResourcesResponse{
+				Status: ws.ResponseCode_OPERATION_CANCELLED,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			}, nil
+		}
+		if errors.Is(err, ErrWorkspaceNotFound) {
+			return &ws.UpdateWorkspaceResourcesResponse{
+				Status: ws.ResponseCode_NOT_FOUND,
+			}, nil
+		}
+		if errors.Is(err, ErrInvalidWorkspaceResources) {
+			s.Logger.Warn(fmt.Errorf("UpdateWorkspaceResources (%d): invalid workspace resources: %v", ctx.Value("id"), err))
+			return &ws.UpdateWorkspaceResourcesResponse{
+				Status: ws.ResponseCode_MALFORMED_REQUEST,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			}, nil
+		}
+		s.Logger.Warn(fmt.Errorf("UpdateWorkspaceResources (%d): failed to update workspace resources: %v", ctx.Value("id"), err))
+		status, wsErr := formatOperationError(err)
+		return &ws.UpdateWorkspaceResourcesResponse{
+			Status: status,
+			Error:  wsErr,
+		}, nil
+	}
+
+	s.Logger.Debug(fmt.Errorf("UpdateWorkspaceResources (%d): completed workspace resource update: %d", ctx.Value("id"), request.GetWorkspaceId()))
+
+	return &ws.UpdateWorkspaceResourcesResponse{
+		Status: ws.ResponseCode_SUCCESS,
+		Resources: &ws.WorkspaceResources{
+			Cpu:    request.GetCpu(),
+			Memory: request.GetMemory(),
+			Disk:   request.GetDisk(),
+		},
+	}, nil
+}
+
+// formatCreateWorkspaceOptions
+//
+//	Helper function to format a ws.CreateWorkspaceRequest into createWorkspaceOptions
+func (s *ProvisionerApiServer) formatCreateWorkspaceOptions(request *ws.CreateWorkspaceRequest) createWorkspaceOptions {
+	return createWorkspaceOptions{
+		Provisioner:   s.Provisioner,
+		StorageEngine: s.StorageEngine,
+		Logger:        s.Logger,
+		TemplateOpts: templateOptions{
+			WorkspaceID: request.GetWorkspaceId(),
+			OwnerID:     request.GetOwnerId(),
+			OwnerEmail:  request.GetOwnerEmail(),
+			OwnerName:   request.GetOwnerName(),
+			Disk:        int(request.GetDisk()),
+			CPU:         int(request.GetCpu()),
+			Memory:      int(request.GetMemory()),
+			Container:   request.GetContainer(),
+			AccessUrl:   request.GetAccessUrl(),
+		},
+		RegistryCaches:  s.RegistryCaches,
+		WsHostOverrides: s.WsHostOverrides,
+		Volpool:         s.Volpool,
+	}
+}
+
 // validateCreateWorkspaceRequest
 //
 //	Helper function to validate ws.CreateWorkspaceRequest
@@ -462,16 +873,8 @@ func validateCreateWorkspaceRequest(request *ws.CreateWorkspaceRequest) error {
 		return fmt.Errorf("invalid owner name")
 	}
 
-	if request.GetDisk() < 5 || request.GetDisk() > 250 {
-		return fmt.Errorf("invalid disk - must be 5 <= x <= 250")
-	}
-
-	if request.GetCpu() < 2 || request.GetCpu() > 32 {
-		return fmt.Errorf("invalid cpu - must be 2 <= x <= 32")
-	}
-
-	if request.GetMemory() < 2 || request.GetMemory() > 32 {
-		return fmt.Errorf("invalid memory - must be 2 <= x <= 32")
+	if err := validateWorkspaceResources(request.GetCpu(), request.GetMemory(), request.GetDisk()); err != nil {
+		return err
 	}
 
 	if request.GetContainer() == "" {
@@ -485,55 +888,136 @@ func validateCreateWorkspaceRequest(request *ws.CreateWorkspaceRequest) error {
 	return nil
 }
 
-// registerProvisionerJob
+// validateWorkspaceResources
 //
-//		Registers an active provisioner job with the cluster bound to this node.
-//	 This function will return true if the job was accepted and registered and false
-//	 if there is currently an active job for the same workspace.
-func registerProvisionerJob(s *ProvisionerApiServer, workspaceId int64) (bool, error) {
-	// TODO: handle the race condition of another node/caller acquiring the job between the
-	// job check and the registration.
-
-	// check for an active provisioner job in the cluster
-	activeJobs, err := s.ClusterNode.GetCluster(fmt.Sprintf("%s/%d", ProvisionerJobPrefix, workspaceId))
+//	Validates the resource sizing of a workspace
+func validateWorkspaceResources(cpu, memory, disk int32) error {
+	if disk < 5 || disk > 250 {
+		return fmt.Errorf("invalid disk - must be 5 <= x <= 250")
+	}
+
+	if cpu < 2 || cpu > 32 {
+		return fmt.Errorf("invalid cpu - must be 2 <= x <= 32")
+	}
+
+	if memory < 2 || memory > 32 {
+		return fmt.Errorf("invalid memory - must be 2 <= x <= 32")
+	}
+
+	return nil
+}
+
+// provisionerJobKey
+//
+//	Formats the job lock key of a workspace
+func provisionerJobKey(workspaceId int64) string {
+	return fmt.Sprintf("%s/active/%d", ProvisionerJobPrefix, workspaceId)
+}
+
+// getProvisionerJob
+//
+//	Returns the ownership details of the active provisioner job for the
+//	workspace on any node or nil if there is no active job.
+func getProvisionerJob(s *ProvisionerApiServer, workspaceId int64) (*joblock.LockInfo, error) {
+	info, err := s.JobLocker.Get(context.Background(), provisionerJobKey(workspaceId))
 	if err != nil {
-		return false, fmt.Errorf("failed to get active provisioner job: %v", err)
+		return nil, fmt.Errorf("failed to get active provisioner job: %v", err)
+	}
+	return info, nil
+}
+
+// formatOperationError
+//
+//	Formats the error of a failed provisioner operation into the response
+//	code and error returned to the caller. Terraform failures are reported
+//	with the response code of the stage that failed and the result of the
+//	terraform command. Operations rejected by a saturated node are reported
+//	as SERVICE_BLOCK so that the caller can retry on another node.
+func formatOperationError(err error) (ws.ResponseCode, *ws.Error) {
+	wsErr := &ws.Error{
+		GoError: err.Error(),
 	}
-	active := false
-	for _, kvs := range activeJobs {
-		if len(kvs) > 0 {
-			active = true
-			break
+
+	if errors.Is(err, provisioner.ErrQueueFull) {
+		return ws.ResponseCode_SERVICE_BLOCK, wsErr
+	}
+
+	var tfErr *provisioner.TerraformError
+	if !errors.As(err, &tfErr) {
+		return ws.ResponseCode_SERVER_EXECUTION_ERROR, wsErr
+	}
+
+	if tfErr.Result != nil {
+		wsErr.CmdError = &ws.CommandError{
+			ExitCode:    int32(tfErr.Result.ExitCode),
+			Stderr:      tfErr.StderrTail(maxCommandErrorLines),
+			StartTime:   tfErr.Result.Start.Unix(),
+			EndTime:     tfErr.Result.End.Unix(),
+			ElapsedTime: int64(tfErr.Result.Cost.Seconds()),
 		}
 	}
+	if wsErr.CmdError != nil && tfErr.Log != nil {
+		wsErr.CmdError.LogId = tfErr.Log.ID
+	}
 
-	// return false to indicate that there is currently an active provisioner job
-	if active {
-		return false, nil
+	switch tfErr.Kind {
+	case provisioner.FailureInit:
+		return ws.ResponseCode_TF_INIT_FAILURE, wsErr
+	case provisioner.FailureValidation:
+		return ws.ResponseCode_TF_VALIDATION_ERROR, wsErr
+	case provisioner.FailureStateLocked:
+		return ws.ResponseCode_STATE_LOCKED, wsErr
+	default:
+		return ws.ResponseCode_TF_PROVISIONING_FAILURE, wsErr
 	}
+}
 
-	// insert a new provisioner job under this node
-	// we set the key's value to the time we started as a debug
-	// measure incase we ever need to know how long the job took
-	err = s.ClusterNode.Put(
-		fmt.Sprintf("%s/%d", ProvisionerJobPrefix, workspaceId),
-		fmt.Sprintf("%d", time.Now().Unix()),
-	)
+// acquireProvisionerJob
+//
+//	Atomically acquires the provisioner job for the workspace across the cluster.
+//	A nil lock is returned if there is currently an active job for the same
+//	workspace. The returned lock must be released via releaseProvisionerJob.
+func acquireProvisionerJob(s *ProvisionerApiServer, workspaceId int64) (*joblock.Lock, error) {
+	lock, holder, err := s.JobLocker.TryLock(context.Background(), provisionerJobKey(workspaceId))
 	if err != nil {
-		return false, fmt.Errorf("failed to register workspace task: %v", err)
+		if errors.Is(err, joblock.ErrLocked) {
+			if holder != nil {
+				s.Logger.Debugf(
+					"provisioner job for workspace %d is held by node %d since %s",
+					workspaceId, holder.NodeID, holder.StartTime.Format(time.RFC3339),
+				)
+			}
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to register workspace task: %v", err)
 	}
-
-	return true, nil
+	metrics.JobStarted()
+	return lock, nil
 }
 
-// removeProvisionerJob
+// releaseProvisionerJob
 //
-//	Removes an active provisioner job with the cluster bound to this node.
-func removeProvisionerJob(s *ProvisionerApiServer, workspaceId int64) error {
-	// remove the provisioner job from this node in the cluster
-	err := s.ClusterNode.Delete(fmt.Sprintf("%s/%d", ProvisionerJobPrefix, workspaceId))
+//	Releases a provisioner job acquired by this caller
+func releaseProvisionerJob(s *ProvisionerApiServer, lock *joblock.Lock) {
+	defer metrics.JobFinished()
+	err := lock.Release()
 	if err != nil {
-		return fmt.Errorf("failed to remove workspace task from cluster node: %v", err)
+		s.Logger.Error(fmt.Errorf("failed to release provisioner job %s: %v", lock.Key, err))
 	}
-	return nil
+}
+
+// provisionerJobContext
+//
+//	Derives a context from the parent that is cancelled once
+//	cancellation is requested for the held provisioner job
+func provisionerJobContext(parent context.Context, lock *joblock.Lock) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-lock.Cancelled():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
 }
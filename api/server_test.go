@@ -0,0 +1,97 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gigo-ws/models"
+	"gigo-ws/protos/ws"
+	"gigo-ws/provisioner"
+	utils2 "gigo-ws/utils"
+)
+
+func TestFormatOperationError(t *testing.T) {
+	start := time.Unix(1675188000, 0)
+	res := &utils2.CommandResult{
+		ExitCode: 1,
+		Stderr:   "Error: failed to create pod",
+		Start:    start,
+		End:      start.Add(time.Minute),
+		Cost:     time.Minute,
+	}
+	oplog := models.NewOperationLog(1, "init", start)
+
+	tests := []struct {
+		name   string
+		err    error
+		status ws.ResponseCode
+		cmd    bool
+		log    string
+	}{
+		{
+			name:   "go error",
+			err:    fmt.Errorf("failed to load module"),
+			status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+		},
+		{
+			name:   "queue full",
+			err:    fmt.Errorf("failed to apply configuration: %w", provisioner.ErrQueueFull),
+			status: ws.ResponseCode_SERVICE_BLOCK,
+		},
+		{
+			name:   "init failure",
+			err:    fmt.Errorf("failed to prepare module: %w", &provisioner.TerraformError{Kind: provisioner.FailureInit, Operation: "init", Result: res, Log: oplog}),
+			status: ws.ResponseCode_TF_INIT_FAILURE,
+			cmd:    true,
+			log:    oplog.ID,
+		},
+		{
+			name:   "validation error",
+			err:    fmt.Errorf("failed to apply configuration: %w", &provisioner.TerraformError{Kind: provisioner.FailureValidation, Operation: "apply", Result: res}),
+			status: ws.ResponseCode_TF_VALIDATION_ERROR,
+			cmd:    true,
+		},
+		{
+			name:   "state locked",
+			err:    fmt.Errorf("failed to apply configuration: %w", &provisioner.TerraformError{Kind: provisioner.FailureStateLocked, Operation: "apply", Result: res}),
+			status: ws.ResponseCode_STATE_LOCKED,
+			cmd:    true,
+		},
+		{
+			name:   "provisioning failure",
+			err:    fmt.Errorf("failed to destroy configuration: %w", &provisioner.TerraformError{Kind: provisioner.FailureProvisioning, Operation: "destroy"}),
+			status: ws.ResponseCode_TF_PROVISIONING_FAILURE,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, wsErr := formatOperationError(test.err)
+			if status != test.status {
+				t.Fatalf("expected status %s, got %s", test.status, status)
+			}
+			if wsErr.GetGoError() != test.err.Error() {
+				t.Errorf("expected go error %q, got %q", test.err.Error(), wsErr.GetGoError())
+			}
+			if !test.cmd {
+				if wsErr.GetCmdError() != nil {
+					t.Errorf("expected no command error, got %+v", wsErr.GetCmdError())
+				}
+				return
+			}
+			if wsErr.GetCmdError().GetExitCode() != 1 {
+				t.Errorf("expected exit code 1, got %d", wsErr.GetCmdError().GetExitCode())
+			}
+			if wsErr.GetCmdError().GetStderr() != res.Stderr {
+				t.Errorf("expected stderr %q, got %q", res.Stderr, wsErr.GetCmdError().GetStderr())
+			}
+			if wsErr.GetCmdError().GetElapsedTime() != 60 {
+				t.Errorf("expected elapsed time 60, got %d", wsErr.GetCmdError().GetElapsedTime())
+			}
+			if wsErr.GetCmdError().GetLogId() != test.log {
+				t.Errorf("expected log id %q, got %q", test.log, wsErr.GetCmdError().GetLogId())
+			}
+		})
+	}
+}
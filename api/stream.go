@@ -0,0 +1,314 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"gigo-ws/protos/ws"
+	"gigo-ws/provisioner/tfevent"
+)
+
+// streamedTerraformEvents
+//
+//	Set of terraform machine-readable ui event types that
+//	are forwarded to the caller of a streaming rpc
+var streamedTerraformEvents = map[tfevent.Type]bool{
+	tfevent.TypePlannedChange: true,
+	tfevent.TypeApplyStart:    true,
+	tfevent.TypeApplyProgress: true,
+	tfevent.TypeApplyComplete: true,
+	tfevent.TypeApplyErrored:  true,
+	tfevent.TypeDiagnostic:    true,
+}
+
+// CreateWorkspaceStream
+//
+//	Provisions a new workspace from scratch streaming the terraform
+//	events to the caller as the workspace is provisioned. The stream
+//	is closed with a ws.ResponseCode_SUCCESS_STREAM_COMPLETE message
+//	containing the new agent on success.
+func (s *ProvisionerApiServer) CreateWorkspaceStream(request *ws.CreateWorkspaceRequest, stream ws.DRPCGigoWS_CreateWorkspaceStreamStream) error {
+	ctx := stream.Context()
+
+	// perform validation on request
+	err := validateCreateWorkspaceRequest(request)
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("CreateWorkspaceStream (%d): failed to create workspace request: %v", ctx.Value("id"), err))
+		return stream.Send(&ws.CreateWorkspaceStreamResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		})
+	}
+
+	s.Logger.Debug(fmt.Errorf("CreateWorkspaceStream (%d): beginning workspace creation: %d", ctx.Value("id"), request.GetWorkspaceId()))
+
+	// acquire the provisioner job for the workspace across the cluster
+	lock, err := acquireProvisionerJob(s, request.GetWorkspaceId())
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("CreateWorkspaceStream (%d): failed to acquire provisioner job: %v", ctx.Value("id"), err))
+		return stream.Send(&ws.CreateWorkspaceStreamResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		})
+	}
+
+	// handle the case that there is an active provisioner job
+	if lock == nil {
+		return stream.Send(&ws.CreateWorkspaceStreamResponse{
+			Status: ws.ResponseCode_ALTERNATIVE_REQUEST_ACTIVE,
+		})
+	}
+
+	// release the provisioner job once the operation completes
+	defer releaseProvisionerJob(s, lock)
+
+	// cancel the operation if cancellation is requested for the workspace
+	ctx, cancel := provisionerJobContext(ctx, lock)
+	defer cancel()
+
+	// forward the terraform events to the caller
+	events := make(chan tfevent.Event)
+	streamErr := forwardTerraformEvents(events, func(event *ws.TerraformEvent) error {
+		return stream.Send(&ws.CreateWorkspaceStreamResponse{
+			Status: ws.ResponseCode_SUCCESS,
+			Event:  event,
+		})
+	})
+
+	// format request into createWorkspaceOptions
+	opts := s.formatCreateWorkspaceOptions(request)
+	opts.Events = events
+
+	// perform workspace creation
+	agent, _, err := createWorkspace(ctx, opts)
+
+	// close the events channel and wait for the forwarder to exit
+	close(events)
+	if sErr := <-streamErr; sErr != nil {
+		s.Logger.Warn(fmt.Errorf("CreateWorkspaceStream (%d): failed to stream terraform events: %v", ctx.Value("id"), sErr))
+		return stream.Send(&ws.CreateWorkspaceStreamResponse{
+			Status: ws.ResponseCode_SERVER_STREAM_FAILURE,
+			Error: &ws.Error{
+				GoError: sErr.Error(),
+			},
+		})
+	}
+
+	if err != nil {
+		// report the operation as cancelled if cancellation was requested
+		if lock.IsCancelled() {
+			s.Logger.Infof("CreateWorkspaceStream (%d): workspace create cancelled: %d", ctx.Value("id"), request.GetWorkspaceId())
+			return stream.Send(&ws.CreateWorkspaceStreamResponse{
+				Status: ws.ResponseCode_OPERATION_CANCELLED,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			})
+		}
+		s.Logger.Warn(fmt.Errorf("CreateWorkspaceStream (%d): failed to create workspace: %v", ctx.Value("id"), err))
+		status, wsErr := formatOperationError(err)
+		return stream.Send(&ws.CreateWorkspaceStreamResponse{
+			Status: status,
+			Error:  wsErr,
+		})
+	}
+
+	// ensure agent is not nil
+	if agent == nil {
+		s.Logger.Warnf("CreateWorkspaceStream (%d): agent was nil", ctx.Value("id"))
+		return stream.Send(&ws.CreateWorkspaceStreamResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: "agent is nil",
+			},
+		})
+	}
+
+	s.Logger.Debug(fmt.Errorf("CreateWorkspaceStream (%d): completed workspace creation: %d", ctx.Value("id"), request.GetWorkspaceId()))
+
+	// close the stream with the new agent
+	return stream.Send(&ws.CreateWorkspaceStreamResponse{
+		Status:     ws.ResponseCode_SUCCESS_STREAM_COMPLETE,
+		AgentId:    agent.ID,
+		AgentToken: agent.Token,
+	})
+}
+
+// StartWorkspaceStream
+//
+//	Starts an existing workspace that is currently stopped streaming
+//	the terraform events to the caller as the workspace is started.
+//	The stream is closed with a ws.ResponseCode_SUCCESS_STREAM_COMPLETE
+//	message containing the agent on success.
+func (s *ProvisionerApiServer) StartWorkspaceStream(request *ws.StartWorkspaceRequest, stream ws.DRPCGigoWS_StartWorkspaceStreamStream) error {
+	ctx := stream.Context()
+
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("StartWorkspaceStream (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return stream.Send(&ws.StartWorkspaceStreamResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		})
+	}
+
+	s.Logger.Debug(fmt.Errorf("StartWorkspaceStream (%d): beginning workspace start: %d", ctx.Value("id"), request.GetWorkspaceId()))
+
+	// acquire the provisioner job for the workspace across the cluster
+	lock, err := acquireProvisionerJob(s, request.GetWorkspaceId())
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("StartWorkspaceStream (%d): failed to acquire provisioner job: %v", ctx.Value("id"), err))
+		return stream.Send(&ws.StartWorkspaceStreamResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		})
+	}
+
+	// handle the case that there is an active provisioner job
+	if lock == nil {
+		return stream.Send(&ws.StartWorkspaceStreamResponse{
+			Status: ws.ResponseCode_ALTERNATIVE_REQUEST_ACTIVE,
+		})
+	}
+
+	// release the provisioner job once the operation completes
+	defer releaseProvisionerJob(s, lock)
+
+	// cancel the operation if cancellation is requested for the workspace
+	ctx, cancel := provisionerJobContext(ctx, lock)
+	defer cancel()
+
+	// forward the terraform events to the caller
+	events := make(chan tfevent.Event)
+	streamErr := forwardTerraformEvents(events, func(event *ws.TerraformEvent) error {
+		return stream.Send(&ws.StartWorkspaceStreamResponse{
+			Status: ws.ResponseCode_SUCCESS,
+			Event:  event,
+		})
+	})
+
+	// perform workspace start
+	agent, _, err := startWorkspace(ctx, startWorkspaceOptions{
+		Provisioner:   s.Provisioner,
+		StorageEngine: s.StorageEngine,
+		Logger:        s.Logger,
+		WorkspaceID:   request.GetWorkspaceId(),
+		Events:        events,
+	})
+
+	// close the events channel and wait for the forwarder to exit
+	close(events)
+	if sErr := <-streamErr; sErr != nil {
+		s.Logger.Warn(fmt.Errorf("StartWorkspaceStream (%d): failed to stream terraform events: %v", ctx.Value("id"), sErr))
+		return stream.Send(&ws.StartWorkspaceStreamResponse{
+			Status: ws.ResponseCode_SERVER_STREAM_FAILURE,
+			Error: &ws.Error{
+				GoError: sErr.Error(),
+			},
+		})
+	}
+
+	if err != nil {
+		// report the operation as cancelled if cancellation was requested
+		if lock.IsCancelled() {
+			s.Logger.Infof("StartWorkspaceStream (%d): workspace start cancelled: %d", ctx.Value("id"), request.GetWorkspaceId())
+			return stream.Send(&ws.StartWorkspaceStreamResponse{
+				Status: ws.ResponseCode_OPERATION_CANCELLED,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			})
+		}
+		s.Logger.Warn(fmt.Errorf("StartWorkspaceStream (%d): failed to start workspace: %v", ctx.Value("id"), err))
+		if errors.Is(err, ErrWorkspaceNotFound) {
+			return stream.Send(&ws.StartWorkspaceStreamResponse{
+				Status: ws.ResponseCode_NOT_FOUND,
+			})
+		}
+		status, wsErr := formatOperationError(err)
+		return stream.Send(&ws.StartWorkspaceStreamResponse{
+			Status: status,
+			Error:  wsErr,
+		})
+	}
+
+	s.Logger.Debug(fmt.Errorf("StartWorkspaceStream (%d): completed workspace start: %d", ctx.Value("id"), request.GetWorkspaceId()))
+
+	// close the stream with the agent
+	return stream.Send(&ws.StartWorkspaceStreamResponse{
+		Status:     ws.ResponseCode_SUCCESS_STREAM_COMPLETE,
+		AgentId:    agent.ID,
+		AgentToken: agent.Token,
+	})
+}
+
+// forwardTerraformEvents
+//
+//	Launches a go routine that consumes the events channel and sends
+//	each streamable terraform event via the passed send function. The
+//	events channel is always drained so that the terraform operation is
+//	never blocked by a failed stream. The first send error (or nil) is
+//	written to the returned channel once the events channel is closed.
+func forwardTerraformEvents(events <-chan tfevent.Event, send func(event *ws.TerraformEvent) error) <-chan error {
+	out := make(chan error, 1)
+	go func() {
+		var streamErr error
+		for e := range events {
+			// skip sending once the stream has failed
+			if streamErr != nil {
+				continue
+			}
+
+			// skip events that we don't forward
+			event := formatTerraformEvent(e)
+			if event == nil {
+				continue
+			}
+
+			streamErr = send(event)
+		}
+		out <- streamErr
+	}()
+	return out
+}
+
+// formatTerraformEvent
+//
+//	Formats a terraform machine-readable ui event into a ws.TerraformEvent.
+//	Returns nil if the event is not a type that is streamed to the caller.
+func formatTerraformEvent(e tfevent.Event) *ws.TerraformEvent {
+	if !streamedTerraformEvents[e.Type] {
+		return nil
+	}
+
+	event := &ws.TerraformEvent{
+		Type:      string(e.Type),
+		Level:     e.Level,
+		Message:   e.Message,
+		Timestamp: e.Timestamp,
+		// include the raw event so that the caller has access to all the details
+		Raw: string(e.Raw),
+	}
+
+	// apply events carry the resource in the hook and planned
+	// changes carry the resource in the change
+	switch {
+	case e.Hook != nil:
+		event.Resource = e.Hook.Resource.Addr
+		event.Action = e.Hook.Action
+		event.ElapsedSeconds = int64(e.Hook.ElapsedSeconds)
+	case e.Change != nil:
+		event.Resource = e.Change.Resource.Addr
+		event.Action = e.Change.Action
+	}
+
+	return event
+}
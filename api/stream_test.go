@@ -0,0 +1,86 @@
+package api
+
+import (
+	"testing"
+
+	"gigo-ws/provisioner/tfevent"
+)
+
+func TestFormatTerraformEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		skip     bool
+		resource string
+		action   string
+		elapsed  int64
+	}{
+		{
+			name: "version",
+			line: `{"@level":"info","@message":"Terraform 1.3.7","@module":"terraform.ui","@timestamp":"2023-01-31T12:00:00.000000-06:00","terraform":"1.3.7","type":"version","ui":"1.0"}`,
+			skip: true,
+		},
+		{
+			name:     "planned change",
+			line:     `{"@level":"info","@message":"gigo_agent.main: Plan to create","@module":"terraform.ui","@timestamp":"2023-01-31T12:00:00.000000-06:00","change":{"resource":{"addr":"gigo_agent.main","module":"","resource":"gigo_agent.main","implied_provider":"gigo","resource_type":"gigo_agent","resource_name":"main","resource_key":null},"action":"create"},"type":"planned_change"}`,
+			resource: "gigo_agent.main",
+			action:   "create",
+		},
+		{
+			name:     "apply progress",
+			line:     `{"@level":"info","@message":"kubernetes_pod.main[0]: Still creating... [10s elapsed]","@module":"terraform.ui","@timestamp":"2023-01-31T12:00:10.000000-06:00","hook":{"resource":{"addr":"kubernetes_pod.main[0]","module":"","resource":"kubernetes_pod.main[0]","implied_provider":"kubernetes","resource_type":"kubernetes_pod","resource_name":"main","resource_key":0},"action":"create","elapsed_seconds":10},"type":"apply_progress"}`,
+			resource: "kubernetes_pod.main[0]",
+			action:   "create",
+			elapsed:  10,
+		},
+		{
+			name: "diagnostic",
+			line: `{"@level":"error","@message":"Error: failed to create pod","@module":"terraform.ui","@timestamp":"2023-01-31T12:00:20.000000-06:00","diagnostic":{"severity":"error","summary":"failed to create pod","detail":""},"type":"diagnostic"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, ok := tfevent.Parse(test.line)
+			if !ok {
+				t.Fatalf("failed to parse event: %s", test.line)
+			}
+
+			event := formatTerraformEvent(e)
+			if test.skip {
+				if event != nil {
+					t.Fatalf("expected event to be skipped, got %+v", event)
+				}
+				return
+			}
+
+			if event == nil {
+				t.Fatal("expected event, got nil")
+			}
+
+			if event.Type != string(e.Type) {
+				t.Errorf("expected type %s, got %s", e.Type, event.Type)
+			}
+
+			if event.Message != e.Message {
+				t.Errorf("expected message %s, got %s", e.Message, event.Message)
+			}
+
+			if event.Resource != test.resource {
+				t.Errorf("expected resource %s, got %s", test.resource, event.Resource)
+			}
+
+			if event.Action != test.action {
+				t.Errorf("expected action %s, got %s", test.action, event.Action)
+			}
+
+			if event.ElapsedSeconds != test.elapsed {
+				t.Errorf("expected elapsed %d, got %d", test.elapsed, event.ElapsedSeconds)
+			}
+
+			if event.Raw != test.line {
+				t.Errorf("expected raw event %s, got %s", test.line, event.Raw)
+			}
+		})
+	}
+}
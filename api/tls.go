@@ -0,0 +1,132 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+
+	"gigo-ws/config"
+)
+
+// TLSIdentity
+//
+//	Identity of a caller presented via a verified client certificate
+type TLSIdentity struct {
+	CommonName  string
+	Certificate *x509.Certificate
+}
+
+// tlsListener
+//
+//	Wraps a tls listener to track the accepted connections by remote
+//	address so that the middleware can resolve the client certificate
+//	of the connection that an rpc arrived on
+type tlsListener struct {
+	net.Listener
+	conns *sync.Map
+}
+
+type trackedConn struct {
+	*tls.Conn
+	once    sync.Once
+	onClose func()
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(c.onClose)
+	return c.Conn.Close()
+}
+
+// newTLSListener
+//
+//	Creates a tls listener from the server's tls configuration
+func newTLSListener(listener net.Listener, cfg config.TLSConfig) (*tlsListener, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %v", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	// configure client certificate verification
+	if cfg.ClientCAFile != "" {
+		buf, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client ca: %v", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(buf) {
+			return nil, fmt.Errorf("failed to parse client ca")
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		if cfg.RequireClientCert {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	} else if cfg.RequireClientCert {
+		return nil, fmt.Errorf("client ca is required to verify client certificates")
+	}
+
+	return &tlsListener{
+		Listener: tls.NewListener(listener, tlsConfig),
+		conns:    &sync.Map{},
+	}, nil
+}
+
+func (l *tlsListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return conn, nil
+	}
+
+	key := conn.RemoteAddr().String()
+	l.conns.Store(key, tlsConn)
+
+	return &trackedConn{
+		Conn: tlsConn,
+		onClose: func() {
+			l.conns.Delete(key)
+		},
+	}, nil
+}
+
+// Identity
+//
+//	Returns the identity of the verified client certificate presented on the
+//	connection with the passed remote address. Nil is returned if the
+//	connection is unknown or did not present a verified certificate.
+func (l *tlsListener) Identity(addr net.Addr) *TLSIdentity {
+	if addr == nil {
+		return nil
+	}
+
+	conn, ok := l.conns.Load(addr.String())
+	if !ok {
+		return nil
+	}
+
+	// only verified chains are trusted as an identity
+	state := conn.(*tls.Conn).ConnectionState()
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	cert := state.VerifiedChains[0][0]
+	return &TLSIdentity{
+		CommonName:  cert.Subject.CommonName,
+		Certificate: cert,
+	}
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gigo-ws/protos/ws"
+	"gigo-ws/provisioner"
+
+	"github.com/hashicorp/go-version"
+)
+
+// UpgradeWorkspace
+//
+//	Pins a workspace module to a newer terraform version. The statefile
+//	is refreshed with the new version so that an incompatible upgrade
+//	fails here and leaves the workspace on its previous version.
+func (s *ProvisionerApiServer) UpgradeWorkspace(ctx context.Context, request *ws.UpgradeWorkspaceRequest) (*ws.UpgradeWorkspaceResponse, error) {
+	// validate id
+	if request.WorkspaceId < 1 {
+		s.Logger.Warn(fmt.Errorf("UpgradeWorkspace (%d): invalid workspace id: %d", ctx.Value("id"), request.GetWorkspaceId()))
+		return &ws.UpgradeWorkspaceResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid workspace id",
+			},
+		}, nil
+	}
+
+	// validate version
+	vrs, err := version.NewVersion(request.GetTerraformVersion())
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("UpgradeWorkspace (%d): invalid terraform version %q: %v", ctx.Value("id"), request.GetTerraformVersion(), err))
+		return &ws.UpgradeWorkspaceResponse{
+			Status: ws.ResponseCode_MALFORMED_REQUEST,
+			Error: &ws.Error{
+				GoError: "invalid terraform version",
+			},
+		}, nil
+	}
+
+	s.Logger.Debug(fmt.Errorf("UpgradeWorkspace (%d): beginning workspace upgrade: %d -> %s", ctx.Value("id"), request.GetWorkspaceId(), vrs))
+
+	// acquire the provisioner job for the workspace across the cluster
+	lock, err := acquireProvisionerJob(s, request.GetWorkspaceId())
+	if err != nil {
+		s.Logger.Warn(fmt.Errorf("UpgradeWorkspace (%d): failed to acquire provisioner job: %v", ctx.Value("id"), err))
+		return &ws.UpgradeWorkspaceResponse{
+			Status: ws.ResponseCode_SERVER_EXECUTION_ERROR,
+			Error: &ws.Error{
+				GoError: err.Error(),
+			},
+		}, nil
+	}
+
+	// handle the case that there is an active provisioner job
+	if lock == nil {
+		return &ws.UpgradeWorkspaceResponse{
+			Status: ws.ResponseCode_ALTERNATIVE_REQUEST_ACTIVE,
+		}, nil
+	}
+
+	// release the provisioner job once the operation completes
+	defer releaseProvisionerJob(s, lock)
+
+	// cancel the operation if cancellation is requested for the workspace
+	ctx, cancel := provisionerJobContext(ctx, lock)
+	defer cancel()
+
+	previous, err := upgradeWorkspace(ctx, upgradeWorkspaceOptions{
+		Provisioner:   s.Provisioner,
+		StorageEngine: s.StorageEngine,
+		Logger:        s.Logger,
+		WorkspaceID:   request.GetWorkspaceId(),
+		Version:       vrs,
+	})
+	if err != nil {
+		// report the operation as cancelled if cancellation was requested
+		if lock.IsCancelled() {
+			s.Logger.Infof("UpgradeWorkspace (%d): workspace upgrade cancelled: %d", ctx.Value("id"), request.GetWorkspaceId())
+			return &ws.UpgradeWorkspaceResponse{
+				Status: ws.ResponseCode_OPERATION_CANCELLED,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			}, nil
+		}
+		if errors.Is(err, ErrWorkspaceNotFound) {
+			return &ws.UpgradeWorkspaceResponse{
+				Status: ws.ResponseCode_NOT_FOUND,
+			}, nil
+		}
+		if errors.Is(err, provisioner.ErrVersionDowngrade) {
+			return &ws.UpgradeWorkspaceResponse{
+				Status: ws.ResponseCode_MALFORMED_REQUEST,
+				Error: &ws.Error{
+					GoError: err.Error(),
+				},
+			}, nil
+		}
+		s.Logger.Warn(fmt.Errorf("UpgradeWorkspace (%d): failed to upgrade workspace: %v", ctx.Value("id"), err))
+		status, wsErr := formatOperationError(err)
+		return &ws.UpgradeWorkspaceResponse{
+			Status: status,
+			Error:  wsErr,
+		}, nil
+	}
+
+	s.Logger.Debug(fmt.Errorf("UpgradeWorkspace (%d): completed workspace upgrade: %d", ctx.Value("id"), request.GetWorkspaceId()))
+
+	return &ws.UpgradeWorkspaceResponse{
+		Status:           ws.ResponseCode_SUCCESS,
+		PreviousVersion:  previous,
+		TerraformVersion: vrs.String(),
+	}, nil
+}
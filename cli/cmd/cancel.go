@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	rootCmd.AddCommand(cancelCmd)
+}
+
+var cancelCmd = &cobra.Command{
+	Use:   "cancel <host>:<port> workspace_id",
+	Short: "Cancels the in-flight operation of a workspace",
+	Long:  `Cancels the in-flight operation of a workspace`,
+	Run:   cancelOperation,
+	Args:  cobra.ExactArgs(2),
+}
+
+func cancelOperation(cmd *cobra.Command, args []string) {
+	// ensure our server is passed
+	if len(args) != 2 {
+		pterm.Error.Printf("invalid arguments passed - should be 2\n")
+		return
+	}
+
+	// split the target
+	split := strings.Split(args[0], ":")
+	if len(split) != 2 {
+		pterm.Error.Printf("invalid server - should be <host>:<port>\n")
+		return
+	}
+
+	port, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		pterm.Error.Printf("invalid port for server\n")
+		return
+	}
+
+	wsId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		pterm.Error.Printf("invalid workspace id\n")
+		return
+	}
+
+	client, err := NewWorkspaceClient(WorkspaceClientOptions{
+		Host: split[0],
+		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
+	})
+	if err != nil {
+		pterm.Error.Printf("failed to create client: %v\n", err)
+		return
+	}
+
+	pterm.Debug.Printf("Cancel Operation Request: %+v\n", wsId)
+
+	nodeId, err := client.CancelOperation(context.TODO(), wsId)
+	if err != nil {
+		pterm.Error.Printf("OPERATION CANCEL FAILED\n%v\n", err)
+		return
+	}
+
+	pterm.Info.Printf("OPERATION CANCEL REQUESTED\nNODE: %d\n", nodeId)
+}
@@ -2,12 +2,21 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	proto "gigo-ws/protos/ws"
 	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"gigo-ws/utils"
 
 	"github.com/google/uuid"
+	"storj.io/drpc"
 	"storj.io/drpc/drpcconn"
+	"storj.io/drpc/drpcmetadata"
 )
 
 // TODO: add tests
@@ -29,25 +38,249 @@ type NewAgent struct {
 	Token uuid.UUID
 }
 
+type WorkspaceStatus struct {
+	State            string
+	AgentID          int64
+	PVCName          string
+	PodName          string
+	CPU              int
+	Memory           int
+	Disk             int
+	ActiveJob        bool
+	JobNodeID        int64
+	JobStart         time.Time
+	TerraformVersion string
+}
+
+type Job struct {
+	ID          int64
+	WorkspaceID int64
+	Operation   string
+	Status      string
+	NodeID      int64
+	CreatedAt   time.Time
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	AgentID     int64
+	AgentToken  string
+	Error       string
+}
+
+type WorkspacePlan struct {
+	ResourceChanges []PlannedResourceChange
+	Add             int
+	Change          int
+	Destroy         int
+}
+
+type PlannedResourceChange struct {
+	Address string
+	Actions []string
+}
+
+type StateLock struct {
+	ID        string
+	Operation string
+	Who       string
+	Version   string
+	Created   time.Time
+	Path      string
+}
+
+type ResourceDrift struct {
+	Address    string
+	Type       string
+	Action     string
+	Attributes []string
+}
+
+type DriftReport struct {
+	WorkspaceID int64
+	Status      string
+	Resources   []ResourceDrift
+	Error       string
+	NodeID      int64
+	CheckedAt   time.Time
+}
+
+type OperationLog struct {
+	ID        string
+	Operation string
+	StartedAt time.Time
+}
+
+type OperationLogs struct {
+	Logs      []OperationLog
+	LogID     string
+	Content   []byte
+	Truncated bool
+}
+
+type StatefileVersion struct {
+	ID           string
+	LastModified time.Time
+	Size         int64
+}
+
+type WorkspaceSummary struct {
+	WorkspaceID  int64
+	State        string
+	LastModified time.Time
+}
+
+type ClientAuthOptions struct {
+	// Token bearer token sent with every call
+	Token string
+	// ClientID and HmacSecret are used to sign every call with hmac
+	ClientID   string
+	HmacSecret string
+}
+
+type ClientTLSOptions struct {
+	Enabled bool
+	// CAFile optional CA bundle used to verify the server - system roots are used when empty
+	CAFile string
+	// CertFile and KeyFile optional client certificate presented for mutual tls
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
 type WorkspaceClientOptions struct {
 	Host string
 	Port int
+	Auth ClientAuthOptions
+	TLS  ClientTLSOptions
 }
 
 type WorkspaceClient struct {
-	conn   *drpcconn.Conn
+	conn   drpc.Conn
 	client proto.DRPCGigoWSClient
 }
 
+// authConn
+//
+//	Wraps a drpc connection to attach the client credentials
+//	to the metadata of every call
+type authConn struct {
+	*drpcconn.Conn
+	auth ClientAuthOptions
+}
+
+func (c *authConn) withCredentials(ctx context.Context, rpc string, body []byte) context.Context {
+	if c.auth.Token != "" {
+		ctx = drpcmetadata.Add(ctx, utils.AuthMetadataAuthorization, "Bearer "+c.auth.Token)
+	}
+
+	if c.auth.ClientID != "" && c.auth.HmacSecret != "" {
+		timestamp := fmt.Sprintf("%d", time.Now().Unix())
+		nonce := uuid.NewString()
+		ctx = drpcmetadata.AddPairs(ctx, map[string]string{
+			utils.AuthMetadataClientID:  c.auth.ClientID,
+			utils.AuthMetadataTimestamp: timestamp,
+			utils.AuthMetadataNonce:     nonce,
+			utils.AuthMetadataSignature: utils.SignRPC(c.auth.HmacSecret, rpc, timestamp, nonce, body),
+		})
+	}
+
+	return ctx
+}
+
+func (c *authConn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+	// the signature covers the encoded request message
+	body, err := enc.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return c.Conn.Invoke(c.withCredentials(ctx, rpc, body), rpc, enc, in, out)
+}
+
+func (c *authConn) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (drpc.Stream, error) {
+	return &signedStream{
+		conn: c,
+		ctx:  ctx,
+		rpc:  rpc,
+	}, nil
+}
+
+// signedStream
+//
+//	Stream that is opened when the request message is sent so
+//	that the credentials can include the signature of the message
+type signedStream struct {
+	conn   *authConn
+	ctx    context.Context
+	rpc    string
+	stream drpc.Stream
+}
+
+func (s *signedStream) Context() context.Context {
+	if s.stream == nil {
+		return s.ctx
+	}
+	return s.stream.Context()
+}
+
+func (s *signedStream) MsgSend(msg drpc.Message, enc drpc.Encoding) error {
+	if s.stream == nil {
+		body, err := enc.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		s.stream, err = s.conn.Conn.NewStream(s.conn.withCredentials(s.ctx, s.rpc, body), s.rpc, enc)
+		if err != nil {
+			return err
+		}
+	}
+	return s.stream.MsgSend(msg, enc)
+}
+
+func (s *signedStream) MsgRecv(msg drpc.Message, enc drpc.Encoding) error {
+	if s.stream == nil {
+		return fmt.Errorf("stream has not sent a request")
+	}
+	return s.stream.MsgRecv(msg, enc)
+}
+
+func (s *signedStream) CloseSend() error {
+	if s.stream == nil {
+		return nil
+	}
+	return s.stream.CloseSend()
+}
+
+func (s *signedStream) Close() error {
+	if s.stream == nil {
+		return nil
+	}
+	return s.stream.Close()
+}
+
 func NewWorkspaceClient(opts WorkspaceClientOptions) (*WorkspaceClient, error) {
 	// dial server
-	rawconn, err := net.Dial("tcp", fmt.Sprintf("%s:%d", opts.Host, opts.Port))
-	if err != nil {
-		return nil, fmt.Errorf("failed to dial remote server: %v", err)
+	var rawconn net.Conn
+	var err error
+	if opts.TLS.Enabled {
+		tlsConfig, err := formatClientTLSConfig(opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure tls: %v", err)
+		}
+		rawconn, err = tls.Dial("tcp", net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port)), tlsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial remote server: %v", err)
+		}
+	} else {
+		rawconn, err = net.Dial("tcp", net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial remote server: %v", err)
+		}
 	}
 
-	// create a drpc connection
-	conn := drpcconn.New(rawconn)
+	// create a drpc connection that attaches our credentials to every call
+	conn := &authConn{
+		Conn: drpcconn.New(rawconn),
+		auth: opts.Auth,
+	}
 
 	// create new client
 	client := proto.NewDRPCGigoWSClient(conn)
@@ -58,6 +291,40 @@ func NewWorkspaceClient(opts WorkspaceClientOptions) (*WorkspaceClient, error) {
 	}, nil
 }
 
+func formatClientTLSConfig(opts WorkspaceClientOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName: opts.TLS.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = opts.Host
+	}
+
+	// load the ca used to verify the server
+	if opts.TLS.CAFile != "" {
+		buf, err := os.ReadFile(opts.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(buf) {
+			return nil, fmt.Errorf("failed to parse ca")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	// load the client certificate for mutual tls
+	if opts.TLS.CertFile != "" || opts.TLS.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.TLS.CertFile, opts.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
 func (c *WorkspaceClient) Echo(ctx context.Context, echo string) (string, error) {
 	// execute remote echo call
 	res, err := c.client.Echo(ctx, &proto.EchoRequest{
@@ -177,6 +444,141 @@ func (c *WorkspaceClient) StartWorkspace(ctx context.Context, workspaceId int64)
 	}, nil
 }
 
+func (c *WorkspaceClient) CreateWorkspaceStream(ctx context.Context, opts CreateWorkspaceOptions, handler func(event *proto.TerraformEvent)) (*NewAgent, error) {
+	// create proto for request
+	req := &proto.CreateWorkspaceRequest{
+		WorkspaceId: opts.WorkspaceID,
+		OwnerId:     opts.OwnerID,
+		OwnerEmail:  opts.OwnerEmail,
+		OwnerName:   opts.OwnerName,
+		Disk:        int32(opts.Disk),
+		Cpu:         int32(opts.CPU),
+		Memory:      int32(opts.Memory),
+		Container:   opts.Container,
+		AccessUrl:   opts.AccessUrl,
+	}
+
+	// execute remote provision call
+	stream, err := c.client.CreateWorkspaceStream(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %v", err)
+	}
+	defer stream.Close()
+
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			return nil, fmt.Errorf("failed to receive from workspace stream: %v", err)
+		}
+
+		// forward events to the handler
+		if res.GetStatus() == proto.ResponseCode_SUCCESS {
+			if res.GetEvent() != nil && handler != nil {
+				handler(res.GetEvent())
+			}
+			continue
+		}
+
+		// check status code
+		if res.GetStatus() != proto.ResponseCode_SUCCESS_STREAM_COMPLETE {
+			// handle go error
+			if res.GetError() != nil && res.GetError().GetGoError() != "" {
+				return nil, fmt.Errorf("remote server error creating workspace: %v", res.GetError().GetGoError())
+			}
+
+			// handle command error
+			if res.GetError() != nil && res.GetError().GetCmdError() != nil {
+				cmdErr := res.GetError().GetCmdError()
+				return nil, fmt.Errorf(
+					"remote command error creating workspace\n    status: %d\n    out: %s\n    err: %s",
+					cmdErr.GetExitCode(), cmdErr.GetStdout(), cmdErr.GetStderr(),
+				)
+			}
+
+			// handle unknown error
+			return nil, fmt.Errorf("failed to create workspace: %v", res.GetStatus().String())
+		}
+
+		// ensure that agent id and token are present
+		if res.GetAgentId() == 0 || res.GetAgentToken() == "" {
+			return nil, fmt.Errorf("failed to create workspace: new agent data missing")
+		}
+
+		// format token to uuid
+		tokenUuid, err := uuid.Parse(res.GetAgentToken())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse uuid: %v", err)
+		}
+
+		return &NewAgent{
+			ID:    res.GetAgentId(),
+			Token: tokenUuid,
+		}, nil
+	}
+}
+
+func (c *WorkspaceClient) StartWorkspaceStream(ctx context.Context, workspaceId int64, handler func(event *proto.TerraformEvent)) (*NewAgent, error) {
+	// execute remote provision call
+	stream, err := c.client.StartWorkspaceStream(ctx, &proto.StartWorkspaceRequest{
+		WorkspaceId: workspaceId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start workspace: %v", err)
+	}
+	defer stream.Close()
+
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			return nil, fmt.Errorf("failed to receive from workspace stream: %v", err)
+		}
+
+		// forward events to the handler
+		if res.GetStatus() == proto.ResponseCode_SUCCESS {
+			if res.GetEvent() != nil && handler != nil {
+				handler(res.GetEvent())
+			}
+			continue
+		}
+
+		// check status code
+		if res.GetStatus() != proto.ResponseCode_SUCCESS_STREAM_COMPLETE {
+			// handle go error
+			if res.GetError() != nil && res.GetError().GetGoError() != "" {
+				return nil, fmt.Errorf("remote server error start workspace: %v", res.GetError().GetGoError())
+			}
+
+			// handle command error
+			if res.GetError() != nil && res.GetError().GetCmdError() != nil {
+				cmdErr := res.GetError().GetCmdError()
+				return nil, fmt.Errorf(
+					"remote command error start workspace\n    status: %d\n    out: %s\n    err: %s",
+					cmdErr.GetExitCode(), cmdErr.GetStdout(), cmdErr.GetStderr(),
+				)
+			}
+
+			// handle unknown error
+			return nil, fmt.Errorf("failed to start workspace: %v", res.GetStatus().String())
+		}
+
+		// ensure that agent id and token are present
+		if res.GetAgentId() == 0 || res.GetAgentToken() == "" {
+			return nil, fmt.Errorf("failed to start workspace: new agent data missing")
+		}
+
+		// format token to uuid
+		tokenUuid, err := uuid.Parse(res.GetAgentToken())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse uuid: %v", err)
+		}
+
+		return &NewAgent{
+			ID:    res.GetAgentId(),
+			Token: tokenUuid,
+		}, nil
+	}
+}
+
 func (c *WorkspaceClient) StopWorkspace(ctx context.Context, workspaceId int64) error {
 	// execute remote provision call
 	res, err := c.client.StopWorkspace(ctx, &proto.StopWorkspaceRequest{
@@ -240,3 +642,540 @@ func (c *WorkspaceClient) DestroyWorkspace(ctx context.Context, workspaceId int6
 
 	return nil
 }
+
+func (c *WorkspaceClient) GetWorkspace(ctx context.Context, workspaceId int64) (*WorkspaceStatus, error) {
+	// execute remote get call
+	res, err := c.client.GetWorkspace(ctx, &proto.GetWorkspaceRequest{
+		WorkspaceId: workspaceId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace: %v", err)
+	}
+
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return nil, fmt.Errorf("remote server error get workspace: %v", res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return nil, fmt.Errorf("failed to get workspace: %v", res.GetStatus().String())
+	}
+
+	return &WorkspaceStatus{
+		State:            res.GetState().String(),
+		AgentID:          res.GetAgentId(),
+		PVCName:          res.GetPvcName(),
+		PodName:          res.GetPodName(),
+		CPU:              int(res.GetResources().GetCpu()),
+		Memory:           int(res.GetResources().GetMemory()),
+		Disk:             int(res.GetResources().GetDisk()),
+		ActiveJob:        res.GetActiveJob(),
+		JobNodeID:        res.GetJobNodeId(),
+		JobStart:         time.Unix(res.GetJobStartTime(), 0),
+		TerraformVersion: res.GetTerraformVersion(),
+	}, nil
+}
+
+func (c *WorkspaceClient) ListWorkspaces(ctx context.Context, cursor int64, limit int, states []proto.WorkspaceState) ([]WorkspaceSummary, int64, error) {
+	// execute remote list call
+	res, err := c.client.ListWorkspaces(ctx, &proto.ListWorkspacesRequest{
+		Cursor: cursor,
+		Limit:  int32(limit),
+		States: states,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list workspaces: %v", err)
+	}
+
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return nil, 0, fmt.Errorf("remote server error list workspaces: %v", res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return nil, 0, fmt.Errorf("failed to list workspaces: %v", res.GetStatus().String())
+	}
+
+	workspaces := make([]WorkspaceSummary, 0, len(res.GetWorkspaces()))
+	for _, w := range res.GetWorkspaces() {
+		summary := WorkspaceSummary{
+			WorkspaceID: w.GetWorkspaceId(),
+			State:       w.GetState().String(),
+		}
+		if w.GetLastModified() > 0 {
+			summary.LastModified = time.Unix(w.GetLastModified(), 0)
+		}
+		workspaces = append(workspaces, summary)
+	}
+
+	return workspaces, res.GetNextCursor(), nil
+}
+
+func (c *WorkspaceClient) SubmitCreateWorkspace(ctx context.Context, opts CreateWorkspaceOptions) (int64, error) {
+	// execute remote submit call
+	res, err := c.client.SubmitCreateWorkspace(ctx, &proto.CreateWorkspaceRequest{
+		WorkspaceId: opts.WorkspaceID,
+		OwnerId:     opts.OwnerID,
+		OwnerEmail:  opts.OwnerEmail,
+		OwnerName:   opts.OwnerName,
+		Disk:        int32(opts.Disk),
+		Cpu:         int32(opts.CPU),
+		Memory:      int32(opts.Memory),
+		Container:   opts.Container,
+		AccessUrl:   opts.AccessUrl,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to submit create workspace: %v", err)
+	}
+	return handleSubmitJobResponse("create workspace", res)
+}
+
+func (c *WorkspaceClient) SubmitStartWorkspace(ctx context.Context, workspaceId int64) (int64, error) {
+	// execute remote submit call
+	res, err := c.client.SubmitStartWorkspace(ctx, &proto.StartWorkspaceRequest{
+		WorkspaceId: workspaceId,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to submit start workspace: %v", err)
+	}
+	return handleSubmitJobResponse("start workspace", res)
+}
+
+func (c *WorkspaceClient) SubmitStopWorkspace(ctx context.Context, workspaceId int64) (int64, error) {
+	// execute remote submit call
+	res, err := c.client.SubmitStopWorkspace(ctx, &proto.StopWorkspaceRequest{
+		WorkspaceId: workspaceId,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to submit stop workspace: %v", err)
+	}
+	return handleSubmitJobResponse("stop workspace", res)
+}
+
+func (c *WorkspaceClient) SubmitDestroyWorkspace(ctx context.Context, workspaceId int64) (int64, error) {
+	// execute remote submit call
+	res, err := c.client.SubmitDestroyWorkspace(ctx, &proto.DestroyWorkspaceRequest{
+		WorkspaceId: workspaceId,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to submit destroy workspace: %v", err)
+	}
+	return handleSubmitJobResponse("destroy workspace", res)
+}
+
+func (c *WorkspaceClient) GetJob(ctx context.Context, jobId int64) (*Job, error) {
+	// execute remote get call
+	res, err := c.client.GetJob(ctx, &proto.GetJobRequest{
+		JobId: jobId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %v", err)
+	}
+
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return nil, fmt.Errorf("remote server error get job: %v", res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return nil, fmt.Errorf("failed to get job: %v", res.GetStatus().String())
+	}
+
+	return formatJob(res.GetJob()), nil
+}
+
+func (c *WorkspaceClient) WatchJob(ctx context.Context, jobId int64, handler func(job *Job)) (*Job, error) {
+	// execute remote watch call
+	stream, err := c.client.WatchJob(ctx, &proto.WatchJobRequest{
+		JobId: jobId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch job: %v", err)
+	}
+	defer stream.Close()
+
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			return nil, fmt.Errorf("failed to receive from job stream: %v", err)
+		}
+
+		// forward status changes to the handler
+		if res.GetStatus() == proto.ResponseCode_SUCCESS {
+			if res.GetJob() != nil && handler != nil {
+				handler(formatJob(res.GetJob()))
+			}
+			continue
+		}
+
+		// check status code
+		if res.GetStatus() != proto.ResponseCode_SUCCESS_STREAM_COMPLETE {
+			// handle go error
+			if res.GetError() != nil && res.GetError().GetGoError() != "" {
+				return nil, fmt.Errorf("remote server error watch job: %v", res.GetError().GetGoError())
+			}
+
+			// handle unknown error
+			return nil, fmt.Errorf("failed to watch job: %v", res.GetStatus().String())
+		}
+
+		return formatJob(res.GetJob()), nil
+	}
+}
+
+func handleSubmitJobResponse(op string, res *proto.SubmitJobResponse) (int64, error) {
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return 0, fmt.Errorf("remote server error submit %s: %v", op, res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return 0, fmt.Errorf("failed to submit %s: %v", op, res.GetStatus().String())
+	}
+
+	return res.GetJobId(), nil
+}
+
+func formatJob(j *proto.Job) *Job {
+	job := &Job{
+		ID:          j.GetId(),
+		WorkspaceID: j.GetWorkspaceId(),
+		Operation:   j.GetOperation().String(),
+		Status:      j.GetStatus().String(),
+		NodeID:      j.GetNodeId(),
+		CreatedAt:   time.Unix(j.GetCreatedAt(), 0),
+		AgentID:     j.GetAgentId(),
+		AgentToken:  j.GetAgentToken(),
+		Error:       j.GetError(),
+	}
+	if j.GetStartedAt() > 0 {
+		job.StartedAt = time.Unix(j.GetStartedAt(), 0)
+	}
+	if j.GetFinishedAt() > 0 {
+		job.FinishedAt = time.Unix(j.GetFinishedAt(), 0)
+	}
+	return job
+}
+
+func (c *WorkspaceClient) CancelOperation(ctx context.Context, workspaceId int64) (int64, error) {
+	// execute remote cancel call
+	res, err := c.client.CancelOperation(ctx, &proto.CancelOperationRequest{
+		WorkspaceId: workspaceId,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel operation: %v", err)
+	}
+
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return 0, fmt.Errorf("remote server error cancel operation: %v", res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return 0, fmt.Errorf("failed to cancel operation: %v", res.GetStatus().String())
+	}
+
+	return res.GetNodeId(), nil
+}
+
+func (c *WorkspaceClient) UpdateWorkspaceResources(ctx context.Context, workspaceId int64, cpu int, memory int, disk int) error {
+	// execute remote update call
+	res, err := c.client.UpdateWorkspaceResources(ctx, &proto.UpdateWorkspaceResourcesRequest{
+		WorkspaceId: workspaceId,
+		Cpu:         int32(cpu),
+		Memory:      int32(memory),
+		Disk:        int32(disk),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update workspace resources: %v", err)
+	}
+
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return fmt.Errorf("remote server error update workspace resources: %v", res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return fmt.Errorf("failed to update workspace resources: %v", res.GetStatus().String())
+	}
+
+	return nil
+}
+
+func (c *WorkspaceClient) PlanWorkspace(ctx context.Context, workspaceId int64, operation proto.JobOperation, create *CreateWorkspaceOptions) (*WorkspacePlan, error) {
+	req := &proto.PlanWorkspaceRequest{
+		WorkspaceId: workspaceId,
+		Operation:   operation,
+	}
+	if create != nil {
+		req.Create = &proto.CreateWorkspaceRequest{
+			WorkspaceId: create.WorkspaceID,
+			OwnerId:     create.OwnerID,
+			OwnerEmail:  create.OwnerEmail,
+			OwnerName:   create.OwnerName,
+			Disk:        int32(create.Disk),
+			Cpu:         int32(create.CPU),
+			Memory:      int32(create.Memory),
+			Container:   create.Container,
+			AccessUrl:   create.AccessUrl,
+		}
+	}
+
+	// execute remote plan call
+	res, err := c.client.PlanWorkspace(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan workspace: %v", err)
+	}
+
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return nil, fmt.Errorf("remote server error planning workspace: %v", res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return nil, fmt.Errorf("failed to plan workspace: %v", res.GetStatus().String())
+	}
+
+	plan := &WorkspacePlan{
+		ResourceChanges: make([]PlannedResourceChange, 0, len(res.GetResourceChanges())),
+		Add:             int(res.GetAdd()),
+		Change:          int(res.GetChange()),
+		Destroy:         int(res.GetDestroy()),
+	}
+	for _, rc := range res.GetResourceChanges() {
+		plan.ResourceChanges = append(plan.ResourceChanges, PlannedResourceChange{
+			Address: rc.GetAddress(),
+			Actions: rc.GetActions(),
+		})
+	}
+
+	return plan, nil
+}
+
+func (c *WorkspaceClient) ForceUnlock(ctx context.Context, workspaceId int64, lockId string) (*StateLock, error) {
+	// execute remote force unlock call
+	res, err := c.client.ForceUnlock(ctx, &proto.ForceUnlockRequest{
+		WorkspaceId: workspaceId,
+		LockId:      lockId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to force unlock workspace: %v", err)
+	}
+
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return nil, fmt.Errorf("remote server error force unlock workspace: %v", res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return nil, fmt.Errorf("failed to force unlock workspace: %v", res.GetStatus().String())
+	}
+
+	return &StateLock{
+		ID:        res.GetLock().GetId(),
+		Operation: res.GetLock().GetOperation(),
+		Who:       res.GetLock().GetWho(),
+		Version:   res.GetLock().GetVersion(),
+		Created:   time.Unix(res.GetLock().GetCreated(), 0),
+		Path:      res.GetLock().GetPath(),
+	}, nil
+}
+
+func (c *WorkspaceClient) GetWorkspaceDrift(ctx context.Context, workspaceId int64) (*DriftReport, error) {
+	// execute remote get workspace drift call
+	res, err := c.client.GetWorkspaceDrift(ctx, &proto.GetWorkspaceDriftRequest{
+		WorkspaceId: workspaceId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace drift: %v", err)
+	}
+
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return nil, fmt.Errorf("remote server error get workspace drift: %v", res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return nil, fmt.Errorf("failed to get workspace drift: %v", res.GetStatus().String())
+	}
+
+	report := &DriftReport{
+		WorkspaceID: res.GetReport().GetWorkspaceId(),
+		Status:      res.GetReport().GetStatus().String(),
+		Resources:   make([]ResourceDrift, 0, len(res.GetReport().GetResources())),
+		Error:       res.GetReport().GetError(),
+		NodeID:      res.GetReport().GetNodeId(),
+		CheckedAt:   time.Unix(res.GetReport().GetCheckedAt(), 0),
+	}
+	for _, r := range res.GetReport().GetResources() {
+		report.Resources = append(report.Resources, ResourceDrift{
+			Address:    r.GetAddress(),
+			Type:       r.GetType(),
+			Action:     r.GetAction(),
+			Attributes: r.GetAttributes(),
+		})
+	}
+
+	return report, nil
+}
+
+func (c *WorkspaceClient) UpgradeWorkspace(ctx context.Context, workspaceId int64, terraformVersion string) (string, error) {
+	// execute remote upgrade call
+	res, err := c.client.UpgradeWorkspace(ctx, &proto.UpgradeWorkspaceRequest{
+		WorkspaceId:      workspaceId,
+		TerraformVersion: terraformVersion,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upgrade workspace: %v", err)
+	}
+
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return "", fmt.Errorf("remote server error upgrade workspace: %v", res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return "", fmt.Errorf("failed to upgrade workspace: %v", res.GetStatus().String())
+	}
+
+	return res.GetPreviousVersion(), nil
+}
+
+func (c *WorkspaceClient) GetOperationLogs(ctx context.Context, workspaceId int64, logId string, tailBytes int64) (*OperationLogs, error) {
+	// execute remote get operation logs call
+	res, err := c.client.GetOperationLogs(ctx, &proto.GetOperationLogsRequest{
+		WorkspaceId: workspaceId,
+		LogId:       logId,
+		TailBytes:   tailBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operation logs: %v", err)
+	}
+
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return nil, fmt.Errorf("remote server error get operation logs: %v", res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return nil, fmt.Errorf("failed to get operation logs: %v", res.GetStatus().String())
+	}
+
+	logs := &OperationLogs{
+		Logs:      make([]OperationLog, 0, len(res.GetLogs())),
+		LogID:     res.GetLogId(),
+		Content:   res.GetContent(),
+		Truncated: res.GetTruncated(),
+	}
+	for _, l := range res.GetLogs() {
+		logs.Logs = append(logs.Logs, OperationLog{
+			ID:        l.GetLogId(),
+			Operation: l.GetOperation(),
+			StartedAt: time.Unix(l.GetStartedAt(), 0),
+		})
+	}
+
+	return logs, nil
+}
+
+func (c *WorkspaceClient) ListStatefileVersions(ctx context.Context, workspaceId int64) ([]StatefileVersion, error) {
+	// execute remote list statefile versions call
+	res, err := c.client.ListStatefileVersions(ctx, &proto.ListStatefileVersionsRequest{
+		WorkspaceId: workspaceId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefile versions: %v", err)
+	}
+
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return nil, fmt.Errorf("remote server error list statefile versions: %v", res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return nil, fmt.Errorf("failed to list statefile versions: %v", res.GetStatus().String())
+	}
+
+	versions := make([]StatefileVersion, 0, len(res.GetVersions()))
+	for _, v := range res.GetVersions() {
+		versions = append(versions, StatefileVersion{
+			ID:           v.GetVersionId(),
+			LastModified: time.Unix(v.GetLastModified(), 0),
+			Size:         v.GetSize(),
+		})
+	}
+
+	return versions, nil
+}
+
+func (c *WorkspaceClient) GetStatefileVersion(ctx context.Context, workspaceId int64, versionId string) ([]byte, error) {
+	// execute remote get statefile version call
+	res, err := c.client.GetStatefileVersion(ctx, &proto.GetStatefileVersionRequest{
+		WorkspaceId: workspaceId,
+		VersionId:   versionId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statefile version: %v", err)
+	}
+
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return nil, fmt.Errorf("remote server error get statefile version: %v", res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return nil, fmt.Errorf("failed to get statefile version: %v", res.GetStatus().String())
+	}
+
+	return res.GetState(), nil
+}
+
+func (c *WorkspaceClient) RestoreStatefile(ctx context.Context, workspaceId int64, versionId string) error {
+	// execute remote restore statefile call
+	res, err := c.client.RestoreStatefile(ctx, &proto.RestoreStatefileRequest{
+		WorkspaceId: workspaceId,
+		VersionId:   versionId,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore statefile: %v", err)
+	}
+
+	// check status code
+	if res.GetStatus() != proto.ResponseCode_SUCCESS {
+		// handle go error
+		if res.GetError() != nil && res.GetError().GetGoError() != "" {
+			return fmt.Errorf("remote server error restore statefile: %v", res.GetError().GetGoError())
+		}
+
+		// handle unknown error
+		return fmt.Errorf("failed to restore statefile: %v", res.GetStatus().String())
+	}
+
+	return nil
+}
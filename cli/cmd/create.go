@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	proto "gigo-ws/protos/ws"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -27,6 +28,12 @@ func init() {
 	createCmd.Flags().StringP("container", "b", "", "container")
 	createCmd.Flags().StringP("access_url", "u", "", "access url")
 
+	// optional streaming of the terraform events
+	createCmd.Flags().BoolP("stream", "s", false, "stream terraform events during creation")
+
+	// optional asynchronous submission returning the job id
+	createCmd.Flags().BoolP("async", "a", false, "submit the creation as an asynchronous job")
+
 }
 
 var createCmd = &cobra.Command{
@@ -60,6 +67,8 @@ func createWorkspace(cmd *cobra.Command, args []string) {
 	client, err := NewWorkspaceClient(WorkspaceClientOptions{
 		Host: split[0],
 		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
 	})
 	if err != nil {
 		pterm.Error.Printf("failed to create client: %v\n", err)
@@ -90,8 +99,42 @@ func createWorkspace(cmd *cobra.Command, args []string) {
 		panic("not implemented")
 	}
 
+	stream, err := cmd.Flags().GetBool("stream")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve stream flag: %v\n", err)
+		return
+	}
+
 	pterm.Debug.Printf("Create Workspace Request: %+v\n", opts)
 
+	async, err := cmd.Flags().GetBool("async")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve async flag: %v\n", err)
+		return
+	}
+
+	if async {
+		jobId, err := client.SubmitCreateWorkspace(context.TODO(), opts)
+		if err != nil {
+			pterm.Error.Printf("WORKSPACE CREATION FAILED\n%v\n", err)
+			return
+		}
+
+		pterm.Info.Printf("JOB SUBMITTED\nJOB ID: %d\n", jobId)
+		return
+	}
+
+	if stream {
+		agent, err := client.CreateWorkspaceStream(context.TODO(), opts, printTerraformEvent)
+		if err != nil {
+			pterm.Error.Printf("WORKSPACE CREATION FAILED\n%v\n", err)
+			return
+		}
+
+		pterm.Info.Printf("WORKSPACE CREATED\nAGENT ID: %d\nTOKEN   : %s\n", agent.ID, agent.Token)
+		return
+	}
+
 	spinner, err := pterm.DefaultSpinner.Start("Creating Workspace")
 	if err != nil {
 		pterm.Error.Printf("failed to start spinner: %v\n", err)
@@ -109,3 +152,14 @@ func createWorkspace(cmd *cobra.Command, args []string) {
 
 	pterm.Info.Printf("WORKSPACE CREATED\nAGENT ID: %d\nTOKEN   : %s\n", agent.ID, agent.Token)
 }
+
+func printTerraformEvent(event *proto.TerraformEvent) {
+	switch event.GetLevel() {
+	case "error":
+		pterm.Error.Printf("%s\n", event.GetMessage())
+	case "warn":
+		pterm.Warning.Printf("%s\n", event.GetMessage())
+	default:
+		pterm.Info.Printf("%s\n", event.GetMessage())
+	}
+}
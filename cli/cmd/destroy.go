@@ -10,6 +10,9 @@ import (
 
 func init() {
 	rootCmd.AddCommand(destroyCmd)
+
+	// optional asynchronous submission returning the job id
+	destroyCmd.Flags().BoolP("async", "a", false, "submit the destroy as an asynchronous job")
 }
 
 var destroyCmd = &cobra.Command{
@@ -49,6 +52,8 @@ func destroyWorkspace(cmd *cobra.Command, args []string) {
 	client, err := NewWorkspaceClient(WorkspaceClientOptions{
 		Host: split[0],
 		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
 	})
 	if err != nil {
 		pterm.Error.Printf("failed to create client: %v\n", err)
@@ -57,6 +62,23 @@ func destroyWorkspace(cmd *cobra.Command, args []string) {
 
 	pterm.Debug.Printf("Destroy Workspace Request: %+v\n", wsId)
 
+	async, err := cmd.Flags().GetBool("async")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve async flag: %v\n", err)
+		return
+	}
+
+	if async {
+		jobId, err := client.SubmitDestroyWorkspace(context.TODO(), wsId)
+		if err != nil {
+			pterm.Error.Printf("WORKSPACE DESTROY FAILED\n%v\n", err)
+			return
+		}
+
+		pterm.Info.Printf("JOB SUBMITTED\nJOB ID: %d\n", jobId)
+		return
+	}
+
 	spinner, err := pterm.DefaultSpinner.Start("Destroying Workspace")
 	if err != nil {
 		pterm.Error.Printf("failed to start spinner: %v\n", err)
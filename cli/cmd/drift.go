@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	rootCmd.AddCommand(driftCmd)
+}
+
+var driftCmd = &cobra.Command{
+	Use:   "drift <host>:<port> workspace_id",
+	Short: "Displays the last drift check of a workspace",
+	Long: `Displays the result of the last drift check of a workspace.
+Drift checks are performed periodically by the cluster leader and report
+resources of the workspace that were changed or deleted outside of terraform.`,
+	Run:  getDrift,
+	Args: cobra.ExactArgs(2),
+}
+
+func getDrift(cmd *cobra.Command, args []string) {
+	// ensure our server is passed
+	if len(args) != 2 {
+		pterm.Error.Printf("invalid arguments passed - should be 2\n")
+		return
+	}
+
+	// split the target
+	split := strings.Split(args[0], ":")
+	if len(split) != 2 {
+		pterm.Error.Printf("invalid server - should be <host>:<port>\n")
+		return
+	}
+
+	port, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		pterm.Error.Printf("invalid port for server\n")
+		return
+	}
+
+	wsId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		pterm.Error.Printf("invalid workspace id\n")
+		return
+	}
+
+	client, err := NewWorkspaceClient(WorkspaceClientOptions{
+		Host: split[0],
+		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
+	})
+	if err != nil {
+		pterm.Error.Printf("failed to create client: %v\n", err)
+		return
+	}
+
+	pterm.Debug.Printf("Get Workspace Drift Request: %d\n", wsId)
+
+	report, err := client.GetWorkspaceDrift(context.TODO(), wsId)
+	if err != nil {
+		pterm.Error.Printf("GET WORKSPACE DRIFT FAILED\n%v\n", err)
+		return
+	}
+
+	pterm.Info.Printf(
+		"STATUS: %s\nCHECKED AT: %s\nNODE: %d\n",
+		report.Status, report.CheckedAt.Format(time.RFC3339), report.NodeID,
+	)
+
+	if report.Error != "" {
+		pterm.Error.Printf("%s\n", report.Error)
+	}
+
+	if len(report.Resources) == 0 {
+		return
+	}
+
+	table := pterm.TableData{{"ADDRESS", "ACTION", "ATTRIBUTES"}}
+	for _, r := range report.Resources {
+		table = append(table, []string{r.Address, r.Action, strings.Join(r.Attributes, ", ")})
+	}
+
+	_ = pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+}
@@ -43,6 +43,8 @@ func echo(cmd *cobra.Command, args []string) {
 	client, err := NewWorkspaceClient(WorkspaceClientOptions{
 		Host: split[0],
 		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
 	})
 	if err != nil {
 		pterm.Error.Printf("failed to create client: %v\n", err)
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	rootCmd.AddCommand(getCmd)
+}
+
+var getCmd = &cobra.Command{
+	Use:   "get <host>:<port> workspace_id",
+	Short: "Retrieves the status of an existing workspace",
+	Long:  `Retrieves the status of an existing workspace`,
+	Run:   getWorkspace,
+	Args:  cobra.ExactArgs(2),
+}
+
+func getWorkspace(cmd *cobra.Command, args []string) {
+	// ensure our server is passed
+	if len(args) != 2 {
+		pterm.Error.Printf("invalid arguments passed - should be 2\n")
+		return
+	}
+
+	// split the target
+	split := strings.Split(args[0], ":")
+	if len(split) != 2 {
+		pterm.Error.Printf("invalid server - should be <host>:<port>\n")
+		return
+	}
+
+	port, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		pterm.Error.Printf("invalid port for server\n")
+		return
+	}
+
+	wsId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		pterm.Error.Printf("invalid workspace id\n")
+		return
+	}
+
+	client, err := NewWorkspaceClient(WorkspaceClientOptions{
+		Host: split[0],
+		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
+	})
+	if err != nil {
+		pterm.Error.Printf("failed to create client: %v\n", err)
+		return
+	}
+
+	pterm.Debug.Printf("Get Workspace Request: %+v\n", wsId)
+
+	status, err := client.GetWorkspace(context.TODO(), wsId)
+	if err != nil {
+		pterm.Error.Printf("WORKSPACE GET FAILED\n%v\n", err)
+		return
+	}
+
+	pterm.Info.Printf(
+		"WORKSPACE %d\nSTATE     : %s\nAGENT ID  : %d\nPVC       : %s\nPOD       : %s\nCPU       : %d\nMEMORY    : %dG\nDISK      : %dGi\nTERRAFORM : %s\nACTIVE JOB: %t\n",
+		wsId, status.State, status.AgentID, status.PVCName, status.PodName,
+		status.CPU, status.Memory, status.Disk, status.TerraformVersion, status.ActiveJob,
+	)
+
+	// show the owner of the active job if the workspace is held
+	if status.ActiveJob {
+		pterm.Info.Printf(
+			"JOB NODE  : %d\nJOB START : %s\n",
+			status.JobNodeID, status.JobStart.Format(time.RFC3339),
+		)
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	rootCmd.AddCommand(jobCmd)
+
+	// optional watching of the job until it finishes
+	jobCmd.Flags().BoolP("watch", "w", false, "watch the job until it finishes")
+}
+
+var jobCmd = &cobra.Command{
+	Use:   "job <host>:<port> job_id",
+	Short: "Retrieves the status of an asynchronous job",
+	Long:  `Retrieves the status of an asynchronous job`,
+	Run:   getJob,
+	Args:  cobra.ExactArgs(2),
+}
+
+func getJob(cmd *cobra.Command, args []string) {
+	// ensure our server is passed
+	if len(args) != 2 {
+		pterm.Error.Printf("invalid arguments passed - should be 2\n")
+		return
+	}
+
+	// split the target
+	split := strings.Split(args[0], ":")
+	if len(split) != 2 {
+		pterm.Error.Printf("invalid server - should be <host>:<port>\n")
+		return
+	}
+
+	port, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		pterm.Error.Printf("invalid port for server\n")
+		return
+	}
+
+	jobId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		pterm.Error.Printf("invalid job id\n")
+		return
+	}
+
+	client, err := NewWorkspaceClient(WorkspaceClientOptions{
+		Host: split[0],
+		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
+	})
+	if err != nil {
+		pterm.Error.Printf("failed to create client: %v\n", err)
+		return
+	}
+
+	watch, err := cmd.Flags().GetBool("watch")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve watch flag: %v\n", err)
+		return
+	}
+
+	pterm.Debug.Printf("Get Job Request: %+v\n", jobId)
+
+	var job *Job
+	if watch {
+		job, err = client.WatchJob(context.TODO(), jobId, func(job *Job) {
+			pterm.Info.Printf("JOB %d: %s\n", job.ID, job.Status)
+		})
+	} else {
+		job, err = client.GetJob(context.TODO(), jobId)
+	}
+	if err != nil {
+		pterm.Error.Printf("JOB GET FAILED\n%v\n", err)
+		return
+	}
+
+	printJob(job)
+}
+
+func printJob(job *Job) {
+	pterm.Info.Printf(
+		"JOB %d\nWORKSPACE : %d\nOPERATION : %s\nSTATUS    : %s\nNODE      : %d\nCREATED   : %s\n",
+		job.ID, job.WorkspaceID, job.Operation, job.Status, job.NodeID, job.CreatedAt.Format(time.RFC3339),
+	)
+
+	if !job.StartedAt.IsZero() {
+		pterm.Info.Printf("STARTED   : %s\n", job.StartedAt.Format(time.RFC3339))
+	}
+
+	if !job.FinishedAt.IsZero() {
+		pterm.Info.Printf("FINISHED  : %s\n", job.FinishedAt.Format(time.RFC3339))
+	}
+
+	if job.AgentID > 0 {
+		pterm.Info.Printf("AGENT ID  : %d\nTOKEN     : %s\n", job.AgentID, job.AgentToken)
+	}
+
+	if job.Error != "" {
+		pterm.Error.Printf("%s\n", job.Error)
+	}
+}
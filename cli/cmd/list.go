@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	proto "gigo-ws/protos/ws"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().Int64P("cursor", "c", 0, "workspace id to begin listing after")
+	listCmd.Flags().IntP("limit", "l", 0, "maximum number of workspaces to list")
+	listCmd.Flags().StringSliceP("state", "s", nil, "filter workspaces by state (active, stopped, destroyed)")
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list <host>:<port> [options]",
+	Short: "Lists the workspaces managed by the provisioner",
+	Long:  `Lists the workspaces managed by the provisioner`,
+	Run:   listWorkspaces,
+	Args:  cobra.ExactArgs(1),
+}
+
+func listWorkspaces(cmd *cobra.Command, args []string) {
+	// ensure our server is passed
+	if len(args) != 1 {
+		pterm.Error.Printf("no server passed\n")
+		return
+	}
+
+	// split the target
+	split := strings.Split(args[0], ":")
+	if len(split) != 2 {
+		pterm.Error.Printf("invalid server - should be <host>:<port>\n")
+		return
+	}
+
+	port, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		pterm.Error.Printf("invalid port for server\n")
+		return
+	}
+
+	cursor, err := cmd.Flags().GetInt64("cursor")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve cursor: %v\n", err)
+		return
+	}
+
+	limit, err := cmd.Flags().GetInt("limit")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve limit: %v\n", err)
+		return
+	}
+
+	stateNames, err := cmd.Flags().GetStringSlice("state")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve states: %v\n", err)
+		return
+	}
+
+	// parse the state filter
+	states := make([]proto.WorkspaceState, 0, len(stateNames))
+	for _, name := range stateNames {
+		state, ok := proto.WorkspaceState_value[strings.ToUpper(name)]
+		if !ok {
+			pterm.Error.Printf("invalid state: %s\n", name)
+			return
+		}
+		states = append(states, proto.WorkspaceState(state))
+	}
+
+	client, err := NewWorkspaceClient(WorkspaceClientOptions{
+		Host: split[0],
+		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
+	})
+	if err != nil {
+		pterm.Error.Printf("failed to create client: %v\n", err)
+		return
+	}
+
+	workspaces, nextCursor, err := client.ListWorkspaces(context.TODO(), cursor, limit, states)
+	if err != nil {
+		pterm.Error.Printf("WORKSPACE LIST FAILED\n%v\n", err)
+		return
+	}
+
+	table := pterm.TableData{{"WORKSPACE ID", "STATE", "LAST MODIFIED"}}
+	for _, w := range workspaces {
+		lastModified := ""
+		if !w.LastModified.IsZero() {
+			lastModified = w.LastModified.String()
+		}
+		table = append(table, []string{fmt.Sprintf("%d", w.WorkspaceID), w.State, lastModified})
+	}
+
+	_ = pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+
+	if nextCursor > 0 {
+		pterm.Info.Printf("NEXT CURSOR: %d\n", nextCursor)
+	}
+}
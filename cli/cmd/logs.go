@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	// optional limit of the log output
+	logsCmd.Flags().Int64P("tail", "t", 0, "maximum number of trailing bytes of the log to display")
+}
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <host>:<port> workspace_id [log_id]",
+	Short: "Displays the logs of past terraform operations of a workspace",
+	Long: `Lists the stored logs of the terraform operations performed on a workspace
+and displays the requested log or the most recent log if no log id is passed.
+Failed operations reference the id of their log in the returned error.`,
+	Run:  getLogs,
+	Args: cobra.RangeArgs(2, 3),
+}
+
+func getLogs(cmd *cobra.Command, args []string) {
+	// ensure our server is passed
+	if len(args) < 2 || len(args) > 3 {
+		pterm.Error.Printf("invalid arguments passed - should be 2 or 3\n")
+		return
+	}
+
+	// split the target
+	split := strings.Split(args[0], ":")
+	if len(split) != 2 {
+		pterm.Error.Printf("invalid server - should be <host>:<port>\n")
+		return
+	}
+
+	port, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		pterm.Error.Printf("invalid port for server\n")
+		return
+	}
+
+	wsId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		pterm.Error.Printf("invalid workspace id\n")
+		return
+	}
+
+	logId := ""
+	if len(args) == 3 {
+		logId = args[2]
+	}
+
+	tail, err := cmd.Flags().GetInt64("tail")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve tail flag: %v\n", err)
+		return
+	}
+
+	client, err := NewWorkspaceClient(WorkspaceClientOptions{
+		Host: split[0],
+		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
+	})
+	if err != nil {
+		pterm.Error.Printf("failed to create client: %v\n", err)
+		return
+	}
+
+	pterm.Debug.Printf("Get Operation Logs Request: %d %s\n", wsId, logId)
+
+	logs, err := client.GetOperationLogs(context.TODO(), wsId, logId, tail)
+	if err != nil {
+		pterm.Error.Printf("GET OPERATION LOGS FAILED\n%v\n", err)
+		return
+	}
+
+	if len(logs.Logs) == 0 {
+		pterm.Info.Printf("NO OPERATION LOGS\n")
+		return
+	}
+
+	table := pterm.TableData{{"LOG ID", "OPERATION", "STARTED AT"}}
+	for _, l := range logs.Logs {
+		table = append(table, []string{l.ID, l.Operation, l.StartedAt.Format(time.RFC3339)})
+	}
+
+	_ = pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+
+	pterm.Info.Printf("LOG: %s\n", logs.LogID)
+	if logs.Truncated {
+		pterm.Warning.Printf("log truncated to the last %d bytes\n", len(logs.Content))
+	}
+	fmt.Print(string(logs.Content))
+}
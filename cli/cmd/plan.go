@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	proto "gigo-ws/protos/ws"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+
+	// config of the workspace required to plan a create
+	planCmd.Flags().StringP("config", "c", "", "config for the workspace when planning a create")
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan <host>:<port> workspace_id create|start|stop|destroy",
+	Short: "Plans an operation against a workspace without applying it",
+	Long:  `Plans an operation against a workspace and prints the resource changes without applying them`,
+	Run:   planWorkspace,
+	Args:  cobra.ExactArgs(3),
+}
+
+func planWorkspace(cmd *cobra.Command, args []string) {
+	// ensure our server is passed
+	if len(args) != 3 {
+		pterm.Error.Printf("invalid arguments passed - should be 3\n")
+		return
+	}
+
+	// split the target
+	split := strings.Split(args[0], ":")
+	if len(split) != 2 {
+		pterm.Error.Printf("invalid server - should be <host>:<port>\n")
+		return
+	}
+
+	port, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		pterm.Error.Printf("invalid port for server\n")
+		return
+	}
+
+	wsId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		pterm.Error.Printf("invalid workspace id\n")
+		return
+	}
+
+	var operation proto.JobOperation
+	switch args[2] {
+	case "create":
+		operation = proto.JobOperation_JOB_CREATE
+	case "start":
+		operation = proto.JobOperation_JOB_START
+	case "stop":
+		operation = proto.JobOperation_JOB_STOP
+	case "destroy":
+		operation = proto.JobOperation_JOB_DESTROY
+	default:
+		pterm.Error.Printf("invalid operation - should be create, start, stop or destroy\n")
+		return
+	}
+
+	cfgPath, err := cmd.Flags().GetString("config")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve config path: %v\n", err)
+		return
+	}
+
+	var opts *CreateWorkspaceOptions
+	if operation == proto.JobOperation_JOB_CREATE {
+		if cfgPath == "" {
+			pterm.Error.Printf("config is required to plan a create\n")
+			return
+		}
+
+		buf, err := os.ReadFile(cfgPath)
+		if err != nil {
+			pterm.Error.Printf("failed to read file: %v\n", err)
+			return
+		}
+
+		opts = &CreateWorkspaceOptions{}
+		err = yaml.Unmarshal(buf, opts)
+		if err != nil {
+			pterm.Error.Printf("failed to unmarshall config - is it yaml?\n")
+			return
+		}
+	}
+
+	client, err := NewWorkspaceClient(WorkspaceClientOptions{
+		Host: split[0],
+		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
+	})
+	if err != nil {
+		pterm.Error.Printf("failed to create client: %v\n", err)
+		return
+	}
+
+	pterm.Debug.Printf("Plan Workspace Request: %d %s\n", wsId, args[2])
+
+	spinner, err := pterm.DefaultSpinner.Start("Planning Workspace")
+	if err != nil {
+		pterm.Error.Printf("failed to start spinner: %v\n", err)
+		return
+	}
+
+	plan, err := client.PlanWorkspace(context.TODO(), wsId, operation, opts)
+	if err != nil {
+		_ = spinner.Stop()
+		pterm.Error.Printf("WORKSPACE PLAN FAILED\n%v\n", err)
+		return
+	}
+
+	_ = spinner.Stop()
+
+	for _, rc := range plan.ResourceChanges {
+		pterm.Info.Printf("%-60s %s\n", rc.Address, strings.Join(rc.Actions, ", "))
+	}
+
+	pterm.Info.Printf("WORKSPACE PLANNED\nADD    : %d\nCHANGE : %d\nDESTROY: %d\n", plan.Add, plan.Change, plan.Destroy)
+}
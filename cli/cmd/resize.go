@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	rootCmd.AddCommand(resizeCmd)
+
+	// new sizing of the workspace
+	resizeCmd.Flags().IntP("cpu", "q", 0, "cpu")
+	resizeCmd.Flags().IntP("memory", "m", 0, "memory")
+	resizeCmd.Flags().IntP("disk", "d", 0, "disk")
+	_ = resizeCmd.MarkFlagRequired("cpu")
+	_ = resizeCmd.MarkFlagRequired("memory")
+	_ = resizeCmd.MarkFlagRequired("disk")
+}
+
+var resizeCmd = &cobra.Command{
+	Use:   "resize <host>:<port> workspace_id",
+	Short: "Resizes the cpu, memory and disk of an existing workspace",
+	Long:  `Resizes the cpu, memory and disk of an existing workspace`,
+	Run:   resizeWorkspace,
+	Args:  cobra.ExactArgs(2),
+}
+
+func resizeWorkspace(cmd *cobra.Command, args []string) {
+	// ensure our server is passed
+	if len(args) != 2 {
+		pterm.Error.Printf("invalid arguments passed - should be 2\n")
+		return
+	}
+
+	// split the target
+	split := strings.Split(args[0], ":")
+	if len(split) != 2 {
+		pterm.Error.Printf("invalid server - should be <host>:<port>\n")
+		return
+	}
+
+	port, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		pterm.Error.Printf("invalid port for server\n")
+		return
+	}
+
+	wsId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		pterm.Error.Printf("invalid workspace id\n")
+		return
+	}
+
+	cpu, err := cmd.Flags().GetInt("cpu")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve cpu flag: %v\n", err)
+		return
+	}
+
+	memory, err := cmd.Flags().GetInt("memory")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve memory flag: %v\n", err)
+		return
+	}
+
+	disk, err := cmd.Flags().GetInt("disk")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve disk flag: %v\n", err)
+		return
+	}
+
+	client, err := NewWorkspaceClient(WorkspaceClientOptions{
+		Host: split[0],
+		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
+	})
+	if err != nil {
+		pterm.Error.Printf("failed to create client: %v\n", err)
+		return
+	}
+
+	pterm.Debug.Printf("Resize Workspace Request: %d cpu=%d memory=%d disk=%d\n", wsId, cpu, memory, disk)
+
+	spinner, err := pterm.DefaultSpinner.Start("Resizing Workspace")
+	if err != nil {
+		pterm.Error.Printf("failed to start spinner: %v\n", err)
+		return
+	}
+
+	err = client.UpdateWorkspaceResources(context.TODO(), wsId, cpu, memory, disk)
+	if err != nil {
+		_ = spinner.Stop()
+		pterm.Error.Printf("WORKSPACE RESIZE FAILED\n%v\n", err)
+		return
+	}
+
+	_ = spinner.Stop()
+
+	pterm.Info.Printf("WORKSPACE RESIZED\nCPU   : %d\nMEMORY: %dG\nDISK  : %dGi\n", cpu, memory, disk)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <host>:<port> workspace_id version_id",
+	Short: "Restores a previous version of the terraform state of a workspace",
+	Long: `Replaces the terraform statefile of a workspace with a previous version listed by the state command.
+The current statefile is kept as a version of its own so that the restore can be rolled back.
+The restore is refused while any node of the cluster is executing an operation for the workspace.`,
+	Run:  restoreState,
+	Args: cobra.ExactArgs(3),
+}
+
+func restoreState(cmd *cobra.Command, args []string) {
+	// ensure our server is passed
+	if len(args) != 3 {
+		pterm.Error.Printf("invalid arguments passed - should be 3\n")
+		return
+	}
+
+	// split the target
+	split := strings.Split(args[0], ":")
+	if len(split) != 2 {
+		pterm.Error.Printf("invalid server - should be <host>:<port>\n")
+		return
+	}
+
+	port, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		pterm.Error.Printf("invalid port for server\n")
+		return
+	}
+
+	wsId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		pterm.Error.Printf("invalid workspace id\n")
+		return
+	}
+
+	client, err := NewWorkspaceClient(WorkspaceClientOptions{
+		Host: split[0],
+		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
+	})
+	if err != nil {
+		pterm.Error.Printf("failed to create client: %v\n", err)
+		return
+	}
+
+	pterm.Debug.Printf("Restore Statefile Request: %d %s\n", wsId, args[2])
+
+	err = client.RestoreStatefile(context.TODO(), wsId, args[2])
+	if err != nil {
+		pterm.Error.Printf("RESTORE STATEFILE FAILED\n%v\n", err)
+		return
+	}
+
+	pterm.Info.Printf("STATEFILE RESTORED\nWORKSPACE: %d\nVERSION: %s\n", wsId, args[2])
+}
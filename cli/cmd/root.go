@@ -9,6 +9,12 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// clientAuth credentials used by every command to authenticate with the server
+var clientAuth ClientAuthOptions
+
+// clientTLS tls configuration used by every command to connect to the server
+var clientTLS ClientTLSOptions
+
 var rootCmd = &cobra.Command{
 	Use:     "gigo-ws-cli",
 	Short:   "CLI utility to superficially interact with the gigo-ws system",
@@ -46,6 +52,18 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&pterm.RawOutput, "raw", "", false, "print unstyled raw output (set it if output is written to a file)")
 	rootCmd.PersistentFlags().BoolVarP(&pcli.DisableUpdateChecking, "disable-update-checks", "", false, "disables update checks")
 
+	// Adds global flags for authenticating with the server.
+	rootCmd.PersistentFlags().StringVarP(&clientAuth.Token, "token", "", "", "bearer token used to authenticate with the server")
+	rootCmd.PersistentFlags().StringVarP(&clientAuth.ClientID, "client-id", "", "", "client id used to sign requests with hmac")
+	rootCmd.PersistentFlags().StringVarP(&clientAuth.HmacSecret, "hmac-secret", "", "", "secret used to sign requests with hmac")
+
+	// Adds global flags for connecting to the server over tls.
+	rootCmd.PersistentFlags().BoolVarP(&clientTLS.Enabled, "tls", "", false, "connect to the server over tls")
+	rootCmd.PersistentFlags().StringVarP(&clientTLS.CAFile, "tls-ca", "", "", "ca used to verify the server certificate")
+	rootCmd.PersistentFlags().StringVarP(&clientTLS.CertFile, "tls-cert", "", "", "client certificate for mutual tls")
+	rootCmd.PersistentFlags().StringVarP(&clientTLS.KeyFile, "tls-key", "", "", "client key for mutual tls")
+	rootCmd.PersistentFlags().StringVarP(&clientTLS.ServerName, "tls-server-name", "", "", "server name used to verify the server certificate")
+
 	// Use https://github.com/pterm/pcli to style the output of cobra.
 	_ = pcli.SetRepo("gage-technologies/dragonfly-cli")
 	pcli.SetRootCmd(rootCmd)
@@ -10,6 +10,12 @@ import (
 
 func init() {
 	rootCmd.AddCommand(startCmd)
+
+	// optional streaming of the terraform events
+	startCmd.Flags().BoolP("stream", "s", false, "stream terraform events during start")
+
+	// optional asynchronous submission returning the job id
+	startCmd.Flags().BoolP("async", "a", false, "submit the start as an asynchronous job")
 }
 
 var startCmd = &cobra.Command{
@@ -49,14 +55,50 @@ func startWorkspace(cmd *cobra.Command, args []string) {
 	client, err := NewWorkspaceClient(WorkspaceClientOptions{
 		Host: split[0],
 		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
 	})
 	if err != nil {
 		pterm.Error.Printf("failed to create client: %v\n", err)
 		return
 	}
 
+	stream, err := cmd.Flags().GetBool("stream")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve stream flag: %v\n", err)
+		return
+	}
+
 	pterm.Debug.Printf("Start Workspace Request: %+v\n", wsId)
 
+	async, err := cmd.Flags().GetBool("async")
+	if err != nil {
+		pterm.Error.Printf("failed to retrieve async flag: %v\n", err)
+		return
+	}
+
+	if async {
+		jobId, err := client.SubmitStartWorkspace(context.TODO(), wsId)
+		if err != nil {
+			pterm.Error.Printf("WORKSPACE START FAILED\n%v\n", err)
+			return
+		}
+
+		pterm.Info.Printf("JOB SUBMITTED\nJOB ID: %d\n", jobId)
+		return
+	}
+
+	if stream {
+		agent, err := client.StartWorkspaceStream(context.TODO(), wsId, printTerraformEvent)
+		if err != nil {
+			pterm.Error.Printf("WORKSPACE START FAILED\n%v\n", err)
+			return
+		}
+
+		pterm.Info.Printf("WORKSPACE STARTED\nAGENT ID: %d\nTOKEN   : %s\n", agent.ID, agent.Token)
+		return
+	}
+
 	spinner, err := pterm.DefaultSpinner.Start("Starting Workspace")
 	if err != nil {
 		pterm.Error.Printf("failed to start spinner: %v\n", err)
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	rootCmd.AddCommand(stateCmd)
+}
+
+var stateCmd = &cobra.Command{
+	Use:   "state <host>:<port> workspace_id [version_id]",
+	Short: "Lists the statefile versions of a workspace",
+	Long: `Lists the versions of the terraform statefile of a workspace that can be restored
+and prints the contents of the requested version if a version id is passed.`,
+	Run:  getState,
+	Args: cobra.RangeArgs(2, 3),
+}
+
+func getState(cmd *cobra.Command, args []string) {
+	// ensure our server is passed
+	if len(args) < 2 || len(args) > 3 {
+		pterm.Error.Printf("invalid arguments passed - should be 2 or 3\n")
+		return
+	}
+
+	// split the target
+	split := strings.Split(args[0], ":")
+	if len(split) != 2 {
+		pterm.Error.Printf("invalid server - should be <host>:<port>\n")
+		return
+	}
+
+	port, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		pterm.Error.Printf("invalid port for server\n")
+		return
+	}
+
+	wsId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		pterm.Error.Printf("invalid workspace id\n")
+		return
+	}
+
+	client, err := NewWorkspaceClient(WorkspaceClientOptions{
+		Host: split[0],
+		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
+	})
+	if err != nil {
+		pterm.Error.Printf("failed to create client: %v\n", err)
+		return
+	}
+
+	// print the contents of the requested version
+	if len(args) == 3 {
+		pterm.Debug.Printf("Get Statefile Version Request: %d %s\n", wsId, args[2])
+
+		state, err := client.GetStatefileVersion(context.TODO(), wsId, args[2])
+		if err != nil {
+			pterm.Error.Printf("GET STATEFILE VERSION FAILED\n%v\n", err)
+			return
+		}
+
+		fmt.Print(string(state))
+		return
+	}
+
+	pterm.Debug.Printf("List Statefile Versions Request: %d\n", wsId)
+
+	versions, err := client.ListStatefileVersions(context.TODO(), wsId)
+	if err != nil {
+		pterm.Error.Printf("LIST STATEFILE VERSIONS FAILED\n%v\n", err)
+		return
+	}
+
+	if len(versions) == 0 {
+		pterm.Info.Printf("NO STATEFILE VERSIONS\n")
+		return
+	}
+
+	table := pterm.TableData{{"VERSION ID", "LAST MODIFIED", "SIZE"}}
+	for _, v := range versions {
+		table = append(table, []string{v.ID, v.LastModified.Format(time.RFC3339), strconv.FormatInt(v.Size, 10)})
+	}
+
+	_ = pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+}
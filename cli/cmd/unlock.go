@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	rootCmd.AddCommand(unlockCmd)
+}
+
+var unlockCmd = &cobra.Command{
+	Use:   "unlock <host>:<port> workspace_id [lock_id]",
+	Short: "Force unlocks the terraform state of a workspace",
+	Long: `Force unlocks the terraform state of a workspace that was left locked by an operation that died.
+The unlock is refused while any node of the cluster is executing an operation for the workspace.
+If a lock id is passed the state is only unlocked if it is the current lock.`,
+	Run:  forceUnlock,
+	Args: cobra.RangeArgs(2, 3),
+}
+
+func forceUnlock(cmd *cobra.Command, args []string) {
+	// ensure our server is passed
+	if len(args) < 2 {
+		pterm.Error.Printf("invalid arguments passed - should be 2 or 3\n")
+		return
+	}
+
+	// split the target
+	split := strings.Split(args[0], ":")
+	if len(split) != 2 {
+		pterm.Error.Printf("invalid server - should be <host>:<port>\n")
+		return
+	}
+
+	port, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		pterm.Error.Printf("invalid port for server\n")
+		return
+	}
+
+	wsId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		pterm.Error.Printf("invalid workspace id\n")
+		return
+	}
+
+	lockId := ""
+	if len(args) == 3 {
+		lockId = args[2]
+	}
+
+	client, err := NewWorkspaceClient(WorkspaceClientOptions{
+		Host: split[0],
+		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
+	})
+	if err != nil {
+		pterm.Error.Printf("failed to create client: %v\n", err)
+		return
+	}
+
+	pterm.Debug.Printf("Force Unlock Request: %d %s\n", wsId, lockId)
+
+	lock, err := client.ForceUnlock(context.TODO(), wsId, lockId)
+	if err != nil {
+		pterm.Error.Printf("FORCE UNLOCK FAILED\n%v\n", err)
+		return
+	}
+
+	pterm.Info.Printf(
+		"STATE UNLOCKED\nLOCK: %s\nOPERATION: %s\nWHO: %s\nCREATED: %s\n",
+		lock.ID, lock.Operation, lock.Who, lock.Created.Format(time.RFC3339),
+	)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade <host>:<port> workspace_id terraform_version",
+	Short: "Upgrades the terraform version of a workspace",
+	Long: `Upgrades the terraform version that a workspace is operated with.
+Workspaces remain pinned to the version they were created with until they are upgraded.
+The statefile is refreshed with the new version and the workspace is left on its
+previous version if the refresh fails. Workspaces cannot be downgraded.`,
+	Run:  upgradeWorkspace,
+	Args: cobra.ExactArgs(3),
+}
+
+func upgradeWorkspace(cmd *cobra.Command, args []string) {
+	// ensure our server is passed
+	if len(args) != 3 {
+		pterm.Error.Printf("invalid arguments passed - should be 3\n")
+		return
+	}
+
+	// split the target
+	split := strings.Split(args[0], ":")
+	if len(split) != 2 {
+		pterm.Error.Printf("invalid server - should be <host>:<port>\n")
+		return
+	}
+
+	port, err := strconv.ParseInt(split[1], 10, 32)
+	if err != nil {
+		pterm.Error.Printf("invalid port for server\n")
+		return
+	}
+
+	wsId, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		pterm.Error.Printf("invalid workspace id\n")
+		return
+	}
+
+	client, err := NewWorkspaceClient(WorkspaceClientOptions{
+		Host: split[0],
+		Port: int(port),
+		Auth: clientAuth,
+		TLS:  clientTLS,
+	})
+	if err != nil {
+		pterm.Error.Printf("failed to create client: %v\n", err)
+		return
+	}
+
+	pterm.Debug.Printf("Upgrade Workspace Request: %d %s\n", wsId, args[2])
+
+	previous, err := client.UpgradeWorkspace(context.TODO(), wsId, args[2])
+	if err != nil {
+		pterm.Error.Printf("WORKSPACE UPGRADE FAILED\n%v\n", err)
+		return
+	}
+
+	pterm.Info.Printf("WORKSPACE UPGRADED\nPREVIOUS VERSION: %s\nTERRAFORM VERSION: %s\n", previous, args[2])
+}
@@ -30,6 +30,9 @@ type Config struct {
 	Logger           LoggerConfig          `yaml:"logger"`
 	WsHostOverrides  map[string]string     `yaml:"ws_host_overrides"`
 	VolumePoolConfig VolumePoolConfig      `yaml:"volume_pool"`
+	Metrics          MetricsConfig         `yaml:"metrics"`
+	DriftDetection   DriftDetectionConfig  `yaml:"drift_detection"`
+	Encryption       EncryptionConfig      `yaml:"encryption"`
 }
 
 func LoadConfig(path string) (*Config, error) {
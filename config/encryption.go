@@ -0,0 +1,22 @@
+package config
+
+type EncryptionKeyConfig struct {
+	// ID identifier of the key that is recorded with every object it encrypts
+	ID string `yaml:"id"`
+	// Key base64 encoded 256 bit key
+	Key string `yaml:"key"`
+	// KeyFile file holding the base64 encoded or raw 256 bit key such as a
+	// plaintext data key that was generated by a kms and written by its agent
+	KeyFile string `yaml:"key_file"`
+}
+
+type EncryptionConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// PrimaryKey id of the key that new objects are encrypted with - defaults
+	// to the first key. The remaining keys are only used for decryption.
+	PrimaryKey string                `yaml:"primary_key"`
+	Keys       []EncryptionKeyConfig `yaml:"keys"`
+	// Rewrap re-encrypts the data keys of objects that were encrypted with a
+	// key other than the primary key on startup so that the key can be retired
+	Rewrap bool `yaml:"rewrap"`
+}
@@ -6,16 +6,89 @@ import (
 	"github.com/gage-technologies/gigo-lib/config"
 )
 
+type KubernetesBackendConfig struct {
+	// Namespace namespace of the state secrets and lock leases
+	Namespace string `yaml:"namespace"`
+	// InCluster authenticate with the service account of the pod
+	InCluster bool `yaml:"in_cluster"`
+	// ConfigPath kubeconfig used when not running in cluster
+	ConfigPath string `yaml:"config_path"`
+}
+
+type PGBackendConfig struct {
+	// ConnStr postgres connection string of the state database
+	ConnStr string `yaml:"conn_str"`
+	// SchemaName schema of the state table - use a schema per environment
+	// to share a database between environments
+	SchemaName string `yaml:"schema_name"`
+}
+
+type HTTPBackendConfig struct {
+	// Port loopback port of the state server - the port is rendered into
+	// the stored modules so it must not change between deployments
+	Port int `yaml:"port"`
+	// CredentialTTL seconds that the state credentials of an operation are valid
+	CredentialTTL int `yaml:"credential_ttl"`
+	// Etcd cluster holding the state locks - set from the etcd
+	// config in cluster mode and locks are held in memory otherwise
+	Etcd config.EtcdConfig `yaml:"-"`
+}
+
+type StatefileHistoryConfig struct {
+	// Retention number of statefile versions kept per workspace by backends
+	// that do not version statefiles themselves - s3 relies on bucket versioning
+	Retention int `yaml:"retention"`
+}
+
 type ProvisionerBackendConfig struct {
 	Type       models.ProvisionerBackendType `yaml:"provisioner_backend_type"`
 	FS         config.StorageFSConfig        `yaml:"fs"`
 	S3         config.StorageS3Config        `yaml:"s3"`
 	InsecureS3 bool                          `yaml:"insecure_s3"`
+	S3Lockfile bool                          `yaml:"s3_lockfile"`
+	Kubernetes KubernetesBackendConfig       `yaml:"kubernetes"`
+	PG         PGBackendConfig               `yaml:"pg"`
+	HTTP       HTTPBackendConfig             `yaml:"http"`
+	History    StatefileHistoryConfig        `yaml:"history"`
+	// Encryption keys of the statefiles written by the fs backend - set
+	// from the encryption config so that it matches module storage
+	Encryption EncryptionConfig `yaml:"-"`
+}
+
+type OperationLogConfig struct {
+	// TerraformLogLevel value of TF_LOG for terraform operations - the
+	// internal terraform logs are written to the operation log
+	TerraformLogLevel string `yaml:"terraform_log_level"`
+	// TailSize bytes of stderr retained in memory for error summaries
+	TailSize int `yaml:"tail_size"`
+	// Retention number of operation logs kept per workspace
+	Retention int `yaml:"retention"`
+}
+
+type ConcurrencyConfig struct {
+	// MaxProcesses number of terraform operations that run concurrently on the node
+	MaxProcesses int `yaml:"max_processes"`
+	// MaxQueued number of terraform operations that wait for a slot before
+	// new operations are rejected
+	MaxQueued int `yaml:"max_queued"`
 }
 
 type ProvisionerConfig struct {
-	TerraformDir     string                   `yaml:"terraform_dir"`
-	TerraformVersion string                   `yaml:"terraform_version"`
-	Overwrite        bool                     `yaml:"overwrite"`
-	Backend          ProvisionerBackendConfig `yaml:"backend"`
+	Engine           models.IaCEngineType `yaml:"engine"`
+	TerraformDir     string               `yaml:"terraform_dir"`
+	TerraformVersion string               `yaml:"terraform_version"`
+	// TerraformVersions additional versions installed alongside the default
+	TerraformVersions []string `yaml:"terraform_versions"`
+	// LegacyTerraformVersion version used for modules stored before versions
+	// were pinned to each module - defaults to the terraform version
+	LegacyTerraformVersion string                   `yaml:"legacy_terraform_version"`
+	Overwrite              bool                     `yaml:"overwrite"`
+	InstallArchive         string                   `yaml:"install_archive"`
+	InstallArchiveSHA256   string                   `yaml:"install_archive_sha256"`
+	PluginCacheDir         string                   `yaml:"plugin_cache_dir"`
+	ProviderMirrorDir      string                   `yaml:"provider_mirror_dir"`
+	InitCacheDir           string                   `yaml:"init_cache_dir"`
+	Backend                ProvisionerBackendConfig `yaml:"backend"`
+	OperationLogs          OperationLogConfig       `yaml:"operation_logs"`
+	Concurrency            ConcurrencyConfig        `yaml:"concurrency"`
 }
@@ -1,6 +1,52 @@
 package config
 
+type AuthClientConfig struct {
+	// ID unique identifier of the client used for hmac authentication
+	ID string `yaml:"id"`
+	// Token optional bearer token used to authenticate the client
+	Token string `yaml:"token"`
+	// HmacSecret optional secret used to verify hmac request signatures
+	HmacSecret string `yaml:"hmac_secret"`
+	// CertCommonName optional common name of a verified mTLS client certificate
+	// that authenticates the client without a token or signature
+	CertCommonName string `yaml:"cert_common_name"`
+	// AllowedRPCs names of the rpcs the client is permitted to call - "*" permits all rpcs
+	AllowedRPCs []string `yaml:"allowed_rpcs"`
+}
+
+type AuthConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// HmacMaxSkew maximum age in seconds of a signed hmac timestamp - defaults to 300
+	HmacMaxSkew int                `yaml:"hmac_max_skew"`
+	Clients     []AuthClientConfig `yaml:"clients"`
+}
+
+type TLSConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// ClientCAFile optional CA bundle used to verify client certificates
+	ClientCAFile string `yaml:"client_ca_file"`
+	// RequireClientCert rejects connections that do not present a client
+	// certificate signed by the client CA
+	RequireClientCert bool `yaml:"require_client_cert"`
+}
+
 type ServerConfig struct {
-	Host string `yaml:"host"`
-	Port int    `yaml:"port"`
+	Host string     `yaml:"host"`
+	Port int        `yaml:"port"`
+	Auth AuthConfig `yaml:"auth"`
+	TLS  TLSConfig  `yaml:"tls"`
+}
+
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Host    string `yaml:"host"`
+	Port    int    `yaml:"port"`
+}
+
+type DriftDetectionConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Interval seconds between the start of two drift scans - defaults to 3600
+	Interval int `yaml:"interval"`
 }
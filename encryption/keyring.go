@@ -0,0 +1,308 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+
+	"gigo-ws/config"
+)
+
+// keySize size in bytes of the key encryption keys and the data keys
+const keySize = 32
+
+// magic prefix of every encrypted object - objects without the prefix
+// were written before encryption was enabled and are returned as is
+var magic = []byte("\x00gigo-ws-enc\x01")
+
+// ErrUnknownKey is returned when an object is encrypted with a key that is not in the keyring
+var ErrUnknownKey = errors.New("object is encrypted with an unknown key")
+
+// Keyring
+//
+//	Envelope encryption of stored objects. Each object is encrypted with
+//	its own random data key using AES-GCM and the data key is encrypted
+//	with a key of the keyring. The id of that key is stored in the header
+//	of the object so that keys can be rotated without re-encrypting data.
+//
+//	Object layout:
+//	  magic | key id length (1) | key id | sealed data key | nonce | ciphertext
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring
+//
+//	Creates a new Keyring from the encryption configuration. New objects
+//	are encrypted with the primary key and every key can decrypt.
+func NewKeyring(cfg config.EncryptionConfig) (*Keyring, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, fmt.Errorf("no encryption keys configured")
+	}
+
+	k := &Keyring{
+		primary: cfg.PrimaryKey,
+		keys:    make(map[string]cipher.AEAD, len(cfg.Keys)),
+	}
+	if k.primary == "" {
+		k.primary = cfg.Keys[0].ID
+	}
+
+	for _, c := range cfg.Keys {
+		if c.ID == "" || len(c.ID) > 255 {
+			return nil, fmt.Errorf("invalid encryption key id: %q", c.ID)
+		}
+		if _, ok := k.keys[c.ID]; ok {
+			return nil, fmt.Errorf("duplicate encryption key id: %q", c.ID)
+		}
+
+		key, err := loadKey(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load encryption key %q: %v", c.ID, err)
+		}
+
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher for encryption key %q: %v", c.ID, err)
+		}
+		k.keys[c.ID] = aead
+	}
+
+	if _, ok := k.keys[k.primary]; !ok {
+		return nil, fmt.Errorf("primary encryption key %q is not configured", k.primary)
+	}
+
+	return k, nil
+}
+
+// loadKey
+//
+//	Loads the key material of a configured key from the
+//	configuration or the key file
+func loadKey(c config.EncryptionKeyConfig) ([]byte, error) {
+	if c.Key != "" && c.KeyFile != "" {
+		return nil, fmt.Errorf("only one of key and key_file may be set")
+	}
+
+	if c.KeyFile != "" {
+		buf, err := os.ReadFile(c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file: %v", err)
+		}
+		return parseKey(buf)
+	}
+
+	return parseKey([]byte(c.Key))
+}
+
+// parseKey
+//
+//	Parses a raw or base64 encoded 256 bit key. Kms data keys are
+//	written either way depending on the agent that writes them.
+func parseKey(buf []byte) ([]byte, error) {
+	if len(buf) == keySize {
+		return buf, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(buf)))
+	if err != nil {
+		return nil, fmt.Errorf("key must be 32 raw bytes or base64 encoded: %v", err)
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", keySize, len(key))
+	}
+
+	return key, nil
+}
+
+// newAEAD
+//
+//	Creates an AES-GCM cipher from a 256 bit key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal
+//
+//	Encrypts the plaintext with a random nonce that is prepended to the ciphertext
+func seal(aead cipher.AEAD, plaintext []byte, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open
+//
+//	Decrypts a ciphertext created by seal
+func open(aead cipher.AEAD, ciphertext []byte, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize()+aead.Overhead() {
+		return nil, fmt.Errorf("ciphertext is truncated")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+// sealedKeySize size of a data key sealed by a key of the keyring
+func sealedKeySize(aead cipher.AEAD) int {
+	return aead.NonceSize() + keySize + aead.Overhead()
+}
+
+// IsEncrypted
+//
+//	Returns true if the object was encrypted by a Keyring
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// header
+//
+//	Parsed header of an encrypted object
+type header struct {
+	keyID     string
+	sealedKey []byte
+	body      []byte
+}
+
+// parseHeader
+//
+//	Splits an encrypted object into its header and the encrypted data
+func (k *Keyring) parseHeader(data []byte) (*header, cipher.AEAD, error) {
+	rest := data[len(magic):]
+	if len(rest) < 1 || len(rest) < 1+int(rest[0]) {
+		return nil, nil, fmt.Errorf("encrypted object is truncated")
+	}
+	keyID := string(rest[1 : 1+int(rest[0])])
+	rest = rest[1+int(rest[0]):]
+
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+
+	if len(rest) < sealedKeySize(aead) {
+		return nil, nil, fmt.Errorf("encrypted object is truncated")
+	}
+
+	return &header{
+		keyID:     keyID,
+		sealedKey: rest[:sealedKeySize(aead)],
+		body:      rest[sealedKeySize(aead):],
+	}, aead, nil
+}
+
+// marshalHeader
+//
+//	Writes the header of an encrypted object with the sealed data key
+func marshalHeader(keyID string, sealedKey []byte) []byte {
+	buf := make([]byte, 0, len(magic)+1+len(keyID)+len(sealedKey))
+	buf = append(buf, magic...)
+	buf = append(buf, byte(len(keyID)))
+	buf = append(buf, keyID...)
+	return append(buf, sealedKey...)
+}
+
+// Encrypt
+//
+//	Encrypts the plaintext with a new data key that is sealed by the primary key
+func (k *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	dataKey := make([]byte, keySize)
+	_, err := rand.Read(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %v", err)
+	}
+
+	// the data key is bound to the id of the key that seals it
+	sealedKey, err := seal(k.keys[k.primary], dataKey, []byte(k.primary))
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal data key: %v", err)
+	}
+
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data cipher: %v", err)
+	}
+
+	body, err := seal(dataAEAD, plaintext, magic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt object: %v", err)
+	}
+
+	return append(marshalHeader(k.primary, sealedKey), body...), nil
+}
+
+// Decrypt
+//
+//	Decrypts an object created by Encrypt. Objects that are not
+//	encrypted are returned as is so that objects written before
+//	encryption was enabled remain readable.
+func (k *Keyring) Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+
+	h, aead, err := k.parseHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := open(aead, h.sealedKey, []byte(h.keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data key: %v", err)
+	}
+
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data cipher: %v", err)
+	}
+
+	plaintext, err := open(dataAEAD, h.body, magic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt object: %v", err)
+	}
+
+	return plaintext, nil
+}
+
+// Rewrap
+//
+//	Seals the data key of an object with the primary key if it was sealed
+//	by another key. The encrypted data is left untouched. Returns false
+//	if the object is not encrypted or already uses the primary key.
+func (k *Keyring) Rewrap(data []byte) ([]byte, bool, error) {
+	if !IsEncrypted(data) {
+		return nil, false, nil
+	}
+
+	h, aead, err := k.parseHeader(data)
+	if err != nil {
+		return nil, false, err
+	}
+	if h.keyID == k.primary {
+		return nil, false, nil
+	}
+
+	dataKey, err := open(aead, h.sealedKey, []byte(h.keyID))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open data key: %v", err)
+	}
+
+	sealedKey, err := seal(k.keys[k.primary], dataKey, []byte(k.primary))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to seal data key: %v", err)
+	}
+
+	return append(marshalHeader(k.primary, sealedKey), h.body...), true, nil
+}
@@ -0,0 +1,158 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gigo-ws/config"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, keySize))
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	keyring, err := NewKeyring(config.EncryptionConfig{
+		Keys: []config.EncryptionKeyConfig{{ID: "key-1", Key: testKey(1)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte(`{"token":"agent-token"}`)
+	ciphertext, err := keyring.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsEncrypted(ciphertext) {
+		t.Fatal("expected ciphertext to be encrypted")
+	}
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Fatal("expected plaintext to be absent from ciphertext")
+	}
+
+	out, err := keyring.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, plaintext) {
+		t.Fatalf("expected %q, got %q", plaintext, out)
+	}
+
+	// plaintext written before encryption was enabled is returned as is
+	out, err = keyring.Decrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, plaintext) {
+		t.Fatalf("expected %q, got %q", plaintext, out)
+	}
+
+	// tampering with the ciphertext is detected
+	ciphertext[len(ciphertext)-1] ^= 0xff
+	_, err = keyring.Decrypt(ciphertext)
+	if err == nil {
+		t.Fatal("expected tampered ciphertext to fail")
+	}
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	oldKeyring, err := NewKeyring(config.EncryptionConfig{
+		Keys: []config.EncryptionKeyConfig{{ID: "key-1", Key: testKey(1)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := oldKeyring.Encrypt([]byte("state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyring, err := NewKeyring(config.EncryptionConfig{
+		PrimaryKey: "key-2",
+		Keys: []config.EncryptionKeyConfig{
+			{ID: "key-1", Key: testKey(1)},
+			{ID: "key-2", Key: testKey(2)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rewrapped, ok, err := keyring.Rewrap(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected object to be rewrapped")
+	}
+
+	// objects already using the primary key are left untouched
+	_, ok, err = keyring.Rewrap(rewrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected rewrapped object to be skipped")
+	}
+
+	// the rewrapped object no longer requires the previous key
+	newKeyring, err := NewKeyring(config.EncryptionConfig{
+		Keys: []config.EncryptionKeyConfig{{ID: "key-2", Key: testKey(2)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := newKeyring.Decrypt(rewrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "state" {
+		t.Fatalf("expected state, got %q", out)
+	}
+
+	_, err = newKeyring.Decrypt(ciphertext)
+	if !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestNewKeyring(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	err := os.WriteFile(keyFile, bytes.Repeat([]byte{3}, keySize), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewKeyring(config.EncryptionConfig{
+		Keys: []config.EncryptionKeyConfig{{ID: "kms", KeyFile: keyFile}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.EncryptionConfig
+	}{
+		{"no keys", config.EncryptionConfig{}},
+		{"short key", config.EncryptionConfig{Keys: []config.EncryptionKeyConfig{{ID: "key-1", Key: base64.StdEncoding.EncodeToString([]byte("short"))}}}},
+		{"missing id", config.EncryptionConfig{Keys: []config.EncryptionKeyConfig{{Key: testKey(1)}}}},
+		{"duplicate id", config.EncryptionConfig{Keys: []config.EncryptionKeyConfig{{ID: "key-1", Key: testKey(1)}, {ID: "key-1", Key: testKey(2)}}}},
+		{"unknown primary", config.EncryptionConfig{PrimaryKey: "key-2", Keys: []config.EncryptionKeyConfig{{ID: "key-1", Key: testKey(1)}}}},
+		{"key and key file", config.EncryptionConfig{Keys: []config.EncryptionKeyConfig{{ID: "key-1", Key: testKey(1), KeyFile: keyFile}}}},
+	}
+	for _, tt := range tests {
+		_, err := NewKeyring(tt.cfg)
+		if err == nil {
+			t.Fatalf("%s: expected error", tt.name)
+		}
+	}
+}
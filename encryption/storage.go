@@ -0,0 +1,198 @@
+package encryption
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gage-technologies/gigo-lib/storage"
+)
+
+// Storage
+//
+//	Storage engine that encrypts every file written to the wrapped
+//	storage engine and transparently decrypts the files it reads.
+//	Files are encrypted as a whole so they are buffered in memory.
+type Storage struct {
+	storage.Storage
+	keyring *Keyring
+}
+
+// NewStorage
+//
+//	Creates a new Storage that encrypts the files of the passed
+//	storage engine with the keyring
+func NewStorage(storageEngine storage.Storage, keyring *Keyring) *Storage {
+	return &Storage{
+		Storage: storageEngine,
+		keyring: keyring,
+	}
+}
+
+// readFile
+//
+//	Reads a file from the wrapped storage engine without
+//	decrypting it returning nil if it does not exist
+func (s *Storage) readFile(path string) ([]byte, error) {
+	f, err := s.Storage.GetFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, nil
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// GetFile
+//
+//	Returns the decrypted contents of a file or nil if the file does not exist
+func (s *Storage) GetFile(path string) (io.ReadCloser, error) {
+	buf, err := s.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if buf == nil {
+		return nil, nil
+	}
+
+	plaintext, err := s.keyring.Decrypt(buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %v", path, err)
+	}
+
+	return io.NopCloser(bytes.NewReader(plaintext)), nil
+}
+
+// CreateFile
+//
+//	Encrypts the contents and writes them to a new file
+func (s *Storage) CreateFile(path string, contents []byte) error {
+	ciphertext, err := s.keyring.Encrypt(contents)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt %s: %v", path, err)
+	}
+	return s.Storage.CreateFile(path, ciphertext)
+}
+
+// CreateFileStreamed
+//
+//	Encrypts the contents read from the reader and writes them to a new file
+func (s *Storage) CreateFileStreamed(path string, length int64, contents io.ReadCloser) error {
+	buf, err := io.ReadAll(contents)
+	_ = contents.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read contents of %s: %v", path, err)
+	}
+
+	ciphertext, err := s.keyring.Encrypt(buf)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt %s: %v", path, err)
+	}
+
+	return s.Storage.CreateFileStreamed(path, int64(len(ciphertext)), io.NopCloser(bytes.NewReader(ciphertext)))
+}
+
+// MergeFiles
+//
+//	Merges the decrypted contents of the files into a new encrypted file.
+//	Encrypted files cannot be concatenated so the merge is always local.
+func (s *Storage) MergeFiles(dst string, paths []string, smallFiles bool) error {
+	merged := new(bytes.Buffer)
+	for _, path := range paths {
+		f, err := s.GetFile(path)
+		if err != nil {
+			return err
+		}
+		if f == nil {
+			return fmt.Errorf("failed to merge %s: file does not exist", path)
+		}
+		_, err = io.Copy(merged, f)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", path, err)
+		}
+	}
+	return s.CreateFile(dst, merged.Bytes())
+}
+
+// Rewrap
+//
+//	Seals the data keys of the files under the passed prefix with the
+//	primary key if they were sealed by another key. Returns the number
+//	of files that were rewrapped. Once it completes for every file the
+//	previous keys can be removed from the keyring.
+func (s *Storage) Rewrap(prefix string) (int, error) {
+	files, err := s.Storage.ListDir(prefix, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list files: %v", err)
+	}
+
+	rewrapped := 0
+	for _, f := range files {
+		if strings.HasSuffix(f, "/") {
+			continue
+		}
+
+		buf, err := s.readFile(f)
+		if err != nil {
+			return rewrapped, fmt.Errorf("failed to read %s: %v", f, err)
+		}
+		if buf == nil {
+			continue
+		}
+
+		data, ok, err := s.keyring.Rewrap(buf)
+		if err != nil {
+			return rewrapped, fmt.Errorf("failed to rewrap %s: %v", f, err)
+		}
+		if !ok {
+			continue
+		}
+
+		err = s.Storage.CreateFile(f, data)
+		if err != nil {
+			return rewrapped, fmt.Errorf("failed to write %s: %v", f, err)
+		}
+		rewrapped++
+	}
+
+	return rewrapped, nil
+}
+
+// Seal
+//
+//	Encrypts the files under the passed prefix that were written before
+//	encryption was enabled. Returns the number of files that were sealed.
+//	Files are sealed in place so no writer may modify them concurrently.
+func (s *Storage) Seal(prefix string) (int, error) {
+	files, err := s.Storage.ListDir(prefix, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list files: %v", err)
+	}
+
+	sealed := 0
+	for _, f := range files {
+		if strings.HasSuffix(f, "/") {
+			continue
+		}
+
+		buf, err := s.readFile(f)
+		if err != nil {
+			return sealed, fmt.Errorf("failed to read %s: %v", f, err)
+		}
+		if buf == nil || IsEncrypted(buf) {
+			continue
+		}
+
+		err = s.CreateFile(f, buf)
+		if err != nil {
+			return sealed, err
+		}
+		sealed++
+	}
+
+	return sealed, nil
+}
@@ -0,0 +1,121 @@
+package encryption
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gigo-ws/config"
+
+	"github.com/gage-technologies/gigo-lib/storage"
+)
+
+func TestStorage(t *testing.T) {
+	root := t.TempDir()
+	storageEngine, err := storage.CreateFileSystemStorage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldKeyring, err := NewKeyring(config.EncryptionConfig{
+		Keys: []config.EncryptionKeyConfig{{ID: "key-1", Key: testKey(1)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents := []byte("AWS_SECRET_ACCESS_KEY=secret")
+	err = NewStorage(storageEngine, oldKeyring).CreateFile("modules/1", contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// files written before encryption was enabled remain readable
+	err = storageEngine.CreateFile("modules/2", contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(root, "modules/1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(raw) || bytes.Contains(raw, contents) {
+		t.Fatal("expected file to be encrypted on disk")
+	}
+
+	keyring, err := NewKeyring(config.EncryptionConfig{
+		PrimaryKey: "key-2",
+		Keys: []config.EncryptionKeyConfig{
+			{ID: "key-1", Key: testKey(1)},
+			{ID: "key-2", Key: testKey(2)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryptedStorage := NewStorage(storageEngine, keyring)
+
+	for _, path := range []string{"modules/1", "modules/2"} {
+		f, err := encryptedStorage.GetFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf, err := io.ReadAll(f)
+		_ = f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf, contents) {
+			t.Fatalf("%s: expected %q, got %q", path, contents, buf)
+		}
+	}
+
+	f, err := encryptedStorage.GetFile("modules/3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f != nil {
+		t.Fatal("expected missing file to return nil")
+	}
+
+	// only the file sealed by the previous key is rewrapped
+	count, err := encryptedStorage.Rewrap("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 rewrapped file, got %d", count)
+	}
+
+	raw, err = os.ReadFile(filepath.Join(root, "modules/1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ok, err := keyring.Rewrap(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected file to use the primary key")
+	}
+
+	// only the file written before encryption was enabled is sealed
+	count, err = encryptedStorage.Seal("modules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 sealed file, got %d", count)
+	}
+
+	raw, err = os.ReadFile(filepath.Join(root, "modules/2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(raw) || bytes.Contains(raw, contents) {
+		t.Fatal("expected sealed file to be encrypted on disk")
+	}
+}
@@ -0,0 +1,411 @@
+package joblock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gage-technologies/gigo-lib/logging"
+	etcd "go.etcd.io/etcd/client/v3"
+)
+
+type EtcdLockerOptions struct {
+	NodeID int64
+	// Prefix etcd key prefix that all locks are stored under
+	Prefix string
+	// TTL of the lease that the locks are bound to - locks held by a
+	// node that stops renewing its lease expire after the ttl
+	TTL        time.Duration
+	EtcdConfig etcd.Config
+	Logger     logging.Logger
+}
+
+// EtcdLocker
+//
+//	Cluster wide implementation of Locker using etcd transactions. Locks
+//	are acquired atomically by comparing the create revision of the key
+//	and are bound to a lease owned by this node so that the locks of a
+//	crashed node expire.
+type EtcdLocker struct {
+	EtcdLockerOptions
+	ctx    context.Context
+	cancel context.CancelFunc
+	client *etcd.Client
+	mu     *sync.RWMutex
+	lease  etcd.LeaseID
+	// held locks acquired by this node that are bound to the current lease
+	held map[*Lock]struct{}
+	wg   *sync.WaitGroup
+}
+
+// NewEtcdLocker
+//
+//	Creates a new EtcdLocker and begins renewing the lease of the node
+func NewEtcdLocker(opts EtcdLockerOptions) (*EtcdLocker, error) {
+	if opts.TTL < time.Second {
+		return nil, fmt.Errorf("ttl must be at least 1 second")
+	}
+	if !strings.HasSuffix(opts.Prefix, "/") {
+		opts.Prefix += "/"
+	}
+
+	client, err := etcd.New(opts.EtcdConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create etcd client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &EtcdLocker{
+		EtcdLockerOptions: opts,
+		ctx:               ctx,
+		cancel:            cancel,
+		client:            client,
+		mu:                &sync.RWMutex{},
+		held:              make(map[*Lock]struct{}),
+		wg:                &sync.WaitGroup{},
+	}
+
+	// acquire the initial lease
+	keepAlive, err := l.grantLease()
+	if err != nil {
+		cancel()
+		_ = client.Close()
+		return nil, err
+	}
+
+	l.wg.Add(1)
+	go l.renewLease(keepAlive)
+
+	return l, nil
+}
+
+// grantLease
+//
+//	Grants a new lease for the locker and begins keeping it alive
+func (l *EtcdLocker) grantLease() (<-chan *etcd.LeaseKeepAliveResponse, error) {
+	grantCtx, cancel := context.WithTimeout(l.ctx, l.TTL)
+	defer cancel()
+
+	lease, err := l.client.Grant(grantCtx, int64(l.TTL.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant lease: %v", err)
+	}
+
+	keepAlive, err := l.client.KeepAlive(l.ctx, lease.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to keep lease alive: %v", err)
+	}
+
+	l.mu.Lock()
+	l.lease = lease.ID
+	l.mu.Unlock()
+
+	return keepAlive, nil
+}
+
+// renewLease
+//
+//	Drains the keep alive channel of the lease and grants a new lease if the
+//	current lease is lost. Locks bound to a lost lease are released by etcd.
+func (l *EtcdLocker) renewLease(keepAlive <-chan *etcd.LeaseKeepAliveResponse) {
+	defer l.wg.Done()
+
+	for {
+		// drain the keep alive responses until the lease is lost
+		for range keepAlive {
+		}
+
+		// exit if the locker was closed
+		if l.ctx.Err() != nil {
+			return
+		}
+
+		l.Logger.Warnf("job lock lease lost for node %d - cancelling the operations of the locks held by this node", l.NodeID)
+
+		// clear the lease so that no new locks are bound to the lost lease and
+		// cancel the operations of the held locks since etcd released them and
+		// another node may acquire them at any time
+		l.mu.Lock()
+		l.lease = 0
+		held := l.held
+		l.held = make(map[*Lock]struct{})
+		l.mu.Unlock()
+
+		for lock := range held {
+			lock.cancel()
+		}
+
+		// retry the lease grant until it succeeds or the locker is closed
+		for {
+			var err error
+			keepAlive, err = l.grantLease()
+			if err == nil {
+				break
+			}
+			l.Logger.Errorf("failed to renew job lock lease for node %d: %v", l.NodeID, err)
+
+			select {
+			case <-l.ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// TryLock
+//
+//	Attempts to acquire the lock for the key without blocking. If the
+//	lock is held by another owner ErrLocked is returned along with the
+//	ownership details of the current holder.
+func (l *EtcdLocker) TryLock(ctx context.Context, key string) (*Lock, *LockInfo, error) {
+	l.mu.RLock()
+	lease := l.lease
+	l.mu.RUnlock()
+
+	// fail if we don't have a valid lease
+	if lease == 0 {
+		return nil, nil, fmt.Errorf("no active lease for job locks")
+	}
+
+	info := LockInfo{
+		Key:       key,
+		NodeID:    l.NodeID,
+		StartTime: time.Now(),
+	}
+	buf, err := json.Marshal(info)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal lock info: %v", err)
+	}
+
+	// atomically create the key only if it does not exist
+	etcdKey := l.Prefix + key
+	res, err := l.client.Txn(ctx).
+		If(etcd.Compare(etcd.CreateRevision(etcdKey), "=", 0)).
+		Then(etcd.OpPut(etcdKey, string(buf), etcd.WithLease(lease))).
+		Else(etcd.OpGet(etcdKey)).
+		Commit()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to execute lock transaction: %v", err)
+	}
+
+	// return the current holder if we lost
+	if !res.Succeeded {
+		var holder *LockInfo
+		if kvs := res.Responses[0].GetResponseRange().GetKvs(); len(kvs) > 0 {
+			holder, err = decodeLockInfo(kvs[0].Value)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, holder, ErrLocked
+	}
+
+	// the create revision of our key is the revision of the transaction
+	revision := res.Header.Revision
+
+	lock := newLock(info)
+
+	// track the lock so that it can be cancelled if the lease is lost
+	l.mu.Lock()
+	if l.lease == lease {
+		l.held[lock] = struct{}{}
+	} else {
+		// the lease was lost while the lock was acquired
+		lock.cancel()
+	}
+	l.mu.Unlock()
+
+	// released is set before the key is deleted so that the watch can
+	// tell our own release apart from the loss of the lock
+	released := &atomic.Bool{}
+
+	// watch the key for cancellation requests until the lock is released
+	watchCtx, stopWatch := context.WithCancel(l.ctx)
+	l.wg.Add(1)
+	go l.watchCancel(watchCtx, etcdKey, revision, lock, released)
+
+	lock.release = func() error {
+		released.Store(true)
+		defer stopWatch()
+
+		l.mu.Lock()
+		delete(l.held, lock)
+		l.mu.Unlock()
+
+		// use a fresh context so that a cancelled operation still releases the lock
+		releaseCtx, cancel := context.WithTimeout(context.Background(), l.TTL)
+		defer cancel()
+
+		// only delete the key if it is still the one we created
+		_, err := l.client.Txn(releaseCtx).
+			If(etcd.Compare(etcd.CreateRevision(etcdKey), "=", revision)).
+			Then(etcd.OpDelete(etcdKey)).
+			Commit()
+		if err != nil {
+			return fmt.Errorf("failed to release lock: %v", err)
+		}
+		return nil
+	}
+
+	return lock, nil, nil
+}
+
+// watchCancel
+//
+//	Watches the key of a held lock and notifies the holder once a
+//	cancellation request is written to the key or the key is deleted
+//	by anything other than the release of the lock
+func (l *EtcdLocker) watchCancel(ctx context.Context, etcdKey string, revision int64, lock *Lock, released *atomic.Bool) {
+	defer l.wg.Done()
+
+	// start the watch after the creation of the lock so that no update is missed
+	for res := range l.client.Watch(ctx, etcdKey, etcd.WithRev(revision+1)) {
+		for _, event := range res.Events {
+			// the lock was released or lost - an operation must not
+			// continue without its lock since another node can take it
+			if event.Type == etcd.EventTypeDelete {
+				if !released.Load() {
+					l.Logger.Warnf("job lock %s held by node %d was lost - cancelling the operation", etcdKey, l.NodeID)
+					lock.cancel()
+				}
+				return
+			}
+
+			info, err := decodeLockInfo(event.Kv.Value)
+			if err != nil {
+				l.Logger.Errorf("failed to decode job lock update %s: %v", etcdKey, err)
+				continue
+			}
+			if info.CancelRequested {
+				lock.cancel()
+				return
+			}
+		}
+	}
+}
+
+// Get
+//
+//	Returns the ownership details of the lock for the key
+//	or nil if the lock is not held
+func (l *EtcdLocker) Get(ctx context.Context, key string) (*LockInfo, error) {
+	res, err := l.client.Get(ctx, l.Prefix+key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lock: %v", err)
+	}
+
+	if len(res.Kvs) == 0 {
+		return nil, nil
+	}
+
+	return decodeLockInfo(res.Kvs[0].Value)
+}
+
+// List
+//
+//	Returns the ownership details of every held lock with the key prefix
+func (l *EtcdLocker) List(ctx context.Context, prefix string) ([]LockInfo, error) {
+	res, err := l.client.Get(ctx, l.Prefix+prefix, etcd.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list locks: %v", err)
+	}
+
+	infos := make([]LockInfo, 0, len(res.Kvs))
+	for _, kv := range res.Kvs {
+		info, err := decodeLockInfo(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, *info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Key < infos[j].Key
+	})
+	return infos, nil
+}
+
+// Cancel
+//
+//	Requests cancellation of the operation holding the lock for the key
+//	by marking the lock as cancelled. The node holding the lock observes
+//	the update via its watch on the key. Returns the ownership details of
+//	the cancelled lock or nil if the lock is not held.
+func (l *EtcdLocker) Cancel(ctx context.Context, key string) (*LockInfo, error) {
+	etcdKey := l.Prefix + key
+
+	res, err := l.client.Get(ctx, etcdKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lock: %v", err)
+	}
+
+	if len(res.Kvs) == 0 {
+		return nil, nil
+	}
+
+	info, err := decodeLockInfo(res.Kvs[0].Value)
+	if err != nil {
+		return nil, err
+	}
+	info.CancelRequested = true
+	buf, err := json.Marshal(info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal lock info: %v", err)
+	}
+
+	// only update the lock if it is still held by the same owner and keep
+	// the lease of the owner so that the lock still expires with the owner
+	txn, err := l.client.Txn(ctx).
+		If(etcd.Compare(etcd.CreateRevision(etcdKey), "=", res.Kvs[0].CreateRevision)).
+		Then(etcd.OpPut(etcdKey, string(buf), etcd.WithIgnoreLease())).
+		Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute cancel transaction: %v", err)
+	}
+
+	// the lock was released between the read and the update
+	if !txn.Succeeded {
+		return nil, nil
+	}
+
+	return info, nil
+}
+
+// Close
+//
+//	Revokes the lease of the locker releasing all locks
+//	held by this node and closes the etcd client
+func (l *EtcdLocker) Close() error {
+	l.cancel()
+	l.wg.Wait()
+
+	l.mu.Lock()
+	lease := l.lease
+	l.lease = 0
+	l.mu.Unlock()
+
+	if lease != 0 {
+		revokeCtx, cancel := context.WithTimeout(context.Background(), l.TTL)
+		defer cancel()
+		_, err := l.client.Revoke(revokeCtx, lease)
+		if err != nil {
+			l.Logger.Errorf("failed to revoke job lock lease for node %d: %v", l.NodeID, err)
+		}
+	}
+
+	return l.client.Close()
+}
+
+func decodeLockInfo(buf []byte) (*LockInfo, error) {
+	var info LockInfo
+	err := json.Unmarshal(buf, &info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode lock info: %v", err)
+	}
+	return &info, nil
+}
@@ -0,0 +1,130 @@
+package joblock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrLocked is returned when a lock is already held by another owner
+	ErrLocked = errors.New("lock is held by another owner")
+)
+
+// LockInfo
+//
+//	Ownership details of a held lock
+type LockInfo struct {
+	Key       string    `json:"key"`
+	NodeID    int64     `json:"node_id"`
+	StartTime time.Time `json:"start_time"`
+	// CancelRequested is set once cancellation of the operation holding the lock is requested
+	CancelRequested bool `json:"cancel_requested,omitempty"`
+}
+
+// Lock
+//
+//	Handle to a lock acquired by this node. Only the
+//	holder of the handle can release the lock.
+type Lock struct {
+	LockInfo
+	release    func() error
+	cancelled  chan struct{}
+	cancelOnce *sync.Once
+}
+
+// newLock
+//
+//	Creates a new lock handle for the passed ownership details
+func newLock(info LockInfo) *Lock {
+	return &Lock{
+		LockInfo:   info,
+		cancelled:  make(chan struct{}),
+		cancelOnce: &sync.Once{},
+	}
+}
+
+// Cancelled
+//
+//	Returns a channel that is closed once cancellation of the operation
+//	holding the lock has been requested via Locker.Cancel or the lock was
+//	lost without being released e.g. because the lease of the node expired
+func (l *Lock) Cancelled() <-chan struct{} {
+	if l == nil {
+		return nil
+	}
+	return l.cancelled
+}
+
+// IsCancelled
+//
+//	Returns true if cancellation of the operation holding the lock has been requested
+func (l *Lock) IsCancelled() bool {
+	if l == nil {
+		return false
+	}
+	select {
+	case <-l.cancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// cancel
+//
+//	Marks the lock as cancelled notifying the holder
+func (l *Lock) cancel() {
+	l.cancelOnce.Do(func() {
+		close(l.cancelled)
+	})
+}
+
+// Release
+//
+//	Releases the lock. Releasing a lock that was already
+//	released or lost is a no-op.
+func (l *Lock) Release() error {
+	if l == nil || l.release == nil {
+		return nil
+	}
+	return l.release()
+}
+
+// Locker
+//
+//	Provides mutually exclusive locks keyed by arbitrary strings
+//	across every node of the provisioner
+type Locker interface {
+	// TryLock
+	//
+	//	Attempts to acquire the lock for the key without blocking. If the
+	//	lock is held by another owner ErrLocked is returned along with the
+	//	ownership details of the current holder.
+	TryLock(ctx context.Context, key string) (*Lock, *LockInfo, error)
+
+	// Get
+	//
+	//	Returns the ownership details of the lock for the key
+	//	or nil if the lock is not held
+	Get(ctx context.Context, key string) (*LockInfo, error)
+
+	// List
+	//
+	//	Returns the ownership details of every held lock with the key prefix
+	List(ctx context.Context, prefix string) ([]LockInfo, error)
+
+	// Cancel
+	//
+	//	Requests cancellation of the operation holding the lock for the key
+	//	on whichever node holds it. The holder is notified via Lock.Cancelled.
+	//	Returns the ownership details of the cancelled lock or nil if the lock
+	//	is not held.
+	Cancel(ctx context.Context, key string) (*LockInfo, error)
+
+	// Close
+	//
+	//	Releases all resources held by the locker
+	Close() error
+}
@@ -0,0 +1,126 @@
+package joblock
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryLocker
+//
+//	In-process implementation of Locker used when the
+//	provisioner is operated as a standalone node
+type MemoryLocker struct {
+	nodeId int64
+	mu     *sync.Mutex
+	locks  map[string]*Lock
+}
+
+// NewMemoryLocker
+//
+//	Creates a new MemoryLocker owned by the passed node
+func NewMemoryLocker(nodeId int64) *MemoryLocker {
+	return &MemoryLocker{
+		nodeId: nodeId,
+		mu:     &sync.Mutex{},
+		locks:  make(map[string]*Lock),
+	}
+}
+
+// TryLock
+//
+//	Attempts to acquire the lock for the key without blocking. If the
+//	lock is held by another owner ErrLocked is returned along with the
+//	ownership details of the current holder.
+func (l *MemoryLocker) TryLock(ctx context.Context, key string) (*Lock, *LockInfo, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if held, ok := l.locks[key]; ok {
+		info := held.LockInfo
+		return nil, &info, ErrLocked
+	}
+
+	lock := newLock(LockInfo{
+		Key:       key,
+		NodeID:    l.nodeId,
+		StartTime: time.Now(),
+	})
+	lock.release = func() error {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		// only remove the lock if it is still ours
+		if l.locks[key] == lock {
+			delete(l.locks, key)
+		}
+		return nil
+	}
+	l.locks[key] = lock
+
+	return lock, nil, nil
+}
+
+// Get
+//
+//	Returns the ownership details of the lock for the key
+//	or nil if the lock is not held
+func (l *MemoryLocker) Get(ctx context.Context, key string) (*LockInfo, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	held, ok := l.locks[key]
+	if !ok {
+		return nil, nil
+	}
+	info := held.LockInfo
+	return &info, nil
+}
+
+// List
+//
+//	Returns the ownership details of every held lock with the key prefix
+func (l *MemoryLocker) List(ctx context.Context, prefix string) ([]LockInfo, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	infos := make([]LockInfo, 0)
+	for key, held := range l.locks {
+		if strings.HasPrefix(key, prefix) {
+			infos = append(infos, held.LockInfo)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Key < infos[j].Key
+	})
+	return infos, nil
+}
+
+// Cancel
+//
+//	Requests cancellation of the operation holding the lock for the key.
+//	Returns the ownership details of the cancelled lock or nil if the lock
+//	is not held.
+func (l *MemoryLocker) Cancel(ctx context.Context, key string) (*LockInfo, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	held, ok := l.locks[key]
+	if !ok {
+		return nil, nil
+	}
+	held.CancelRequested = true
+	held.cancel()
+
+	info := held.LockInfo
+	return &info, nil
+}
+
+// Close
+//
+//	No-op for the in-process locker
+func (l *MemoryLocker) Close() error {
+	return nil
+}
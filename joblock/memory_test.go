@@ -0,0 +1,157 @@
+package joblock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMemoryLocker_TryLock(t *testing.T) {
+	locker := NewMemoryLocker(69)
+
+	lock, holder, err := locker.TryLock(context.TODO(), "provisioner/job/active/420")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if holder != nil {
+		t.Fatalf("expected no holder, got %+v", holder)
+	}
+
+	if lock.NodeID != 69 || lock.Key != "provisioner/job/active/420" || lock.StartTime.IsZero() {
+		t.Fatalf("unexpected lock info: %+v", lock.LockInfo)
+	}
+
+	// ensure the lock cannot be acquired twice
+	_, holder, err = locker.TryLock(context.TODO(), "provisioner/job/active/420")
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked, got %v", err)
+	}
+
+	if holder == nil || holder.NodeID != 69 {
+		t.Fatalf("expected holder to be node 69, got %+v", holder)
+	}
+
+	info, err := locker.Get(context.TODO(), "provisioner/job/active/420")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info == nil || *info != lock.LockInfo {
+		t.Fatalf("expected %+v, got %+v", lock.LockInfo, info)
+	}
+
+	infos, err := locker.List(context.TODO(), "provisioner/job/active/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(infos) != 1 {
+		t.Fatalf("expected 1 lock, got %d", len(infos))
+	}
+
+	err = lock.Release()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err = locker.Get(context.TODO(), "provisioner/job/active/420")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info != nil {
+		t.Fatalf("expected lock to be released, got %+v", info)
+	}
+
+	// ensure a stale handle does not release a lock acquired by someone else
+	second, _, err := locker.TryLock(context.TODO(), "provisioner/job/active/420")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lock.Release()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err = locker.Get(context.TODO(), "provisioner/job/active/420")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info == nil || *info != second.LockInfo {
+		t.Fatalf("expected second lock to be held, got %+v", info)
+	}
+}
+
+func TestMemoryLocker_Concurrent(t *testing.T) {
+	locker := NewMemoryLocker(69)
+
+	var acquired int64
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := locker.TryLock(context.TODO(), "provisioner/job/active/420")
+			if err == nil {
+				atomic.AddInt64(&acquired, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if acquired != 1 {
+		t.Fatalf("expected exactly 1 acquisition, got %d", acquired)
+	}
+}
+
+func TestMemoryLocker_Cancel(t *testing.T) {
+	locker := NewMemoryLocker(69)
+
+	// cancelling a lock that is not held is a no-op
+	info, err := locker.Cancel(context.TODO(), "provisioner/job/active/420")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info != nil {
+		t.Fatalf("expected no lock, got %+v", info)
+	}
+
+	lock, _, err := locker.TryLock(context.TODO(), "provisioner/job/active/420")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lock.IsCancelled() {
+		t.Fatal("expected lock to not be cancelled")
+	}
+
+	info, err = locker.Cancel(context.TODO(), "provisioner/job/active/420")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info == nil || info.NodeID != 69 || !info.CancelRequested {
+		t.Fatalf("unexpected cancelled lock info: %+v", info)
+	}
+
+	select {
+	case <-lock.Cancelled():
+	default:
+		t.Fatal("expected cancelled channel to be closed")
+	}
+
+	// cancelling twice must not panic
+	_, err = locker.Cancel(context.TODO(), "provisioner/job/active/420")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lock.Release()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"math"
+	"net/http"
 	"os"
 	"sync"
 	"syscall"
@@ -13,6 +13,10 @@ import (
 
 	"gigo-ws/api"
 	"gigo-ws/config"
+	"gigo-ws/encryption"
+	"gigo-ws/joblock"
+	"gigo-ws/metrics"
+	"gigo-ws/models"
 	"gigo-ws/provisioner"
 	"gigo-ws/volpool"
 
@@ -33,8 +37,8 @@ var (
 	interrupted = false
 )
 
-func shutdown(server *api.ProvisionerApiServer, clusterNode cluster.Node, clusterCancel context.CancelFunc,
-	logger logging.Logger) {
+func shutdown(server *api.ProvisionerApiServer, metricsServer *http.Server, clusterNode cluster.Node,
+	jobLocker joblock.Locker, clusterCancel context.CancelFunc, logger logging.Logger) {
 	// we lock here so we can prevent the main thread from exiting
 	// before we finish the graceful shutdown
 	lock.Lock()
@@ -53,6 +57,22 @@ func shutdown(server *api.ProvisionerApiServer, clusterNode cluster.Node, cluste
 		logger.Errorf("failed to close server gracefully: %v", err)
 	}
 
+	// close metrics server
+	if metricsServer != nil {
+		logger.Info("closing metrics server")
+		err = metricsServer.Close()
+		if err != nil {
+			logger.Errorf("failed to close metrics server gracefully: %v", err)
+		}
+	}
+
+	// close job locker releasing any jobs held by this node
+	logger.Info("closing job locker")
+	err = jobLocker.Close()
+	if err != nil {
+		logger.Errorf("failed to close job locker gracefully: %v", err)
+	}
+
 	// close cluster node
 	logger.Info("closing cluster node")
 	clusterNode.Stop()
@@ -139,6 +159,41 @@ func main() {
 		log.Fatalf("invalid storage engine: %s", cfg.ModuleStorage.Engine)
 	}
 
+	// encrypt the modules, logs and states written to module storage
+	if cfg.Encryption.Enabled {
+		keyring, err := encryption.NewKeyring(cfg.Encryption)
+		if err != nil {
+			log.Fatalf("failed to create encryption keyring: %v", err)
+		}
+		encryptedStorage := encryption.NewStorage(storageEngine, keyring)
+		storageEngine = encryptedStorage
+
+		// rewrap before any request is served so that a concurrent
+		// write cannot be overwritten by the rewrapped copy
+		if cfg.Encryption.Rewrap {
+			logger.Info("rewrapping module storage with the primary encryption key")
+			count, err := encryptedStorage.Rewrap("")
+			if err != nil {
+				log.Fatalf("failed to rewrap module storage: %v", err)
+			}
+			logger.Infof("rewrapped %d objects in module storage", count)
+		}
+
+		// the fs backend encrypts its statefiles with the same keys
+		cfg.Provisioner.Backend.Encryption = cfg.Encryption
+
+		// the statefiles hold the agent tokens so encryption is refused with a
+		// backend that would leave them unencrypted
+		switch cfg.Provisioner.Backend.Type {
+		case models.ProvisionerBackendFS, models.ProvisionerBackendHTTP:
+		default:
+			log.Fatalf(
+				"encryption is enabled but the statefiles of the %s backend cannot be encrypted - use the fs or http backend",
+				cfg.Provisioner.Backend.Type.String(),
+			)
+		}
+	}
+
 	fmt.Println("Creating ti database")
 
 	tiDB, err := ti.CreateDatabase(cfg.TitaniumConfig.TitaniumHost, cfg.TitaniumConfig.TitaniumPort, "mysql", cfg.TitaniumConfig.TitaniumUser,
@@ -147,8 +202,13 @@ func main() {
 		log.Fatal("failed to create titanium database: ", err)
 	}
 
+	// the state locks of the built in state server are shared by the cluster
+	if cfg.Cluster {
+		cfg.Provisioner.Backend.HTTP.Etcd = cfg.EtcdConfig
+	}
+
 	// initialize provisioner
-	prov, err := provisioner.NewProvisioner(cfg.Provisioner, logger)
+	prov, err := provisioner.NewProvisioner(cfg.Provisioner, storageEngine, logger)
 	if err != nil {
 		log.Fatalf("failed to create provisioner: %v", err)
 	}
@@ -165,6 +225,49 @@ func main() {
 		Config:        cfg.VolumePoolConfig,
 	})
 
+	// export the volume counts of the pool with the metrics
+	err = metrics.RegisterVolumePool(vpool.VolumeCounts)
+	if err != nil {
+		log.Fatalf("failed to register volume pool metrics: %v", err)
+	}
+
+	// create job locker to ensure that only one provisioner job
+	// runs for a workspace at a time
+	var jobLocker joblock.Locker
+	if !cfg.Cluster {
+		jobLocker = joblock.NewMemoryLocker(nodeId.Int64())
+	} else {
+		jobLocker, err = joblock.NewEtcdLocker(joblock.EtcdLockerOptions{
+			NodeID: nodeId.Int64(),
+			Prefix: "/gigo-ws/locks",
+			// locks held by a node that dies or hangs for more than 10
+			// seconds expire so that the workspace can be reclaimed
+			TTL: time.Second * 10,
+			EtcdConfig: etcd.Config{
+				Endpoints: cfg.EtcdConfig.Hosts,
+				Username:  cfg.EtcdConfig.Username,
+				Password:  cfg.EtcdConfig.Password,
+			},
+			Logger: clusterLogger,
+		})
+		if err != nil {
+			log.Fatalf("failed to create job locker: %v", err)
+		}
+	}
+
+	// create the drift detector that is driven by the cluster leader
+	var driftDetector *api.DriftDetector
+	if cfg.DriftDetection.Enabled {
+		driftDetector = api.NewDriftDetector(api.DriftDetectorOptions{
+			NodeID:        nodeId.Int64(),
+			JobLocker:     jobLocker,
+			Provisioner:   prov,
+			StorageEngine: storageEngine,
+			Interval:      time.Second * time.Duration(cfg.DriftDetection.Interval),
+			Logger:        logger,
+		})
+	}
+
 	// create context for cluster
 	clusterCtx, clusterCancel := context.WithCancel(context.Background())
 
@@ -178,16 +281,17 @@ func main() {
 			// env var - this is really designed to be operated on k8s
 			// but could theoretically be set manually if deployed by hand
 			os.Getenv("GIGO_POD_IP"),
-			// we don't utilize any coordination amongst the cluster nodes other
-			// that ephemeral storage bound to the nodes lease
+			// the standalone node is always the leader so it owns the drift scans
 			func(ctx context.Context) error {
+				if driftDetector != nil {
+					driftDetector.Trigger(ctx)
+				}
 				return nil
 			},
 			func(ctx context.Context) error {
 				return nil
 			},
-			// we never tick so set to a ridiculous value
-			math.MaxInt64,
+			time.Second*5,
 			clusterLogger,
 		)
 	} else {
@@ -213,6 +317,9 @@ func main() {
 				go func() {
 					vpool.ResolveStateDeltas()
 				}()
+				if driftDetector != nil {
+					driftDetector.Trigger(ctx)
+				}
 				return nil
 			},
 			func(ctx context.Context) error {
@@ -233,6 +340,7 @@ func main() {
 	server, err := api.NewProvisionerApiServer(api.ProvisionerApiServerOptions{
 		ID:              nodeId.Int64(),
 		ClusterNode:     clusterNode,
+		JobLocker:       jobLocker,
 		Provisioner:     prov,
 		Volpool:         vpool,
 		StorageEngine:   storageEngine,
@@ -241,27 +349,47 @@ func main() {
 		Port:            cfg.Server.Port,
 		RegistryCaches:  cfg.RegistryCaches,
 		WsHostOverrides: cfg.WsHostOverrides,
+		Auth:            cfg.Server.Auth,
+		TLS:             cfg.Server.TLS,
 		Logger:          logger,
 	})
 	if err != nil {
 		log.Fatalf("failed to create server: %v", err)
 	}
 
+	// serve metrics over http so they can be scraped by prometheus
+	var metricsServer *http.Server
+	if cfg.Metrics.Enabled {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer = &http.Server{
+			Addr:    fmt.Sprintf("%s:%d", cfg.Metrics.Host, cfg.Metrics.Port),
+			Handler: mux,
+		}
+		go func() {
+			logger.Infof("serving metrics on %s", metricsServer.Addr)
+			err := metricsServer.ListenAndServe()
+			if err != nil && err != http.ErrServerClosed {
+				logger.Errorf("metrics server failed: %v", err)
+			}
+		}()
+	}
+
 	// register shutdown handler for all potential interrupt signals
 	interrupt := tebata.New(syscall.SIGINT)
-	err = interrupt.Reserve(shutdown, server, clusterNode, clusterCancel, logger)
+	err = interrupt.Reserve(shutdown, server, metricsServer, clusterNode, jobLocker, clusterCancel, logger)
 	if err != nil {
 		log.Fatal("failed to created interrupt handler: ", err)
 	}
 
 	term := tebata.New(syscall.SIGTERM)
-	err = term.Reserve(shutdown, server, clusterNode, clusterCancel, logger)
+	err = term.Reserve(shutdown, server, metricsServer, clusterNode, jobLocker, clusterCancel, logger)
 	if err != nil {
 		log.Fatal("failed to created term handler: ", err)
 	}
 
 	kill := tebata.New(syscall.SIGKILL)
-	err = kill.Reserve(shutdown, server, clusterNode, clusterCancel, logger)
+	err = kill.Reserve(shutdown, server, metricsServer, clusterNode, jobLocker, clusterCancel, logger)
 	if err != nil {
 		log.Fatal("failed to created kill handler: ", err)
 	}
@@ -277,6 +405,6 @@ func main() {
 	if err != nil && !interrupted {
 		logger.Errorf("server failed unexpectedly: %v", err)
 		// gracefully shutdown
-		shutdown(server, clusterNode, clusterCancel, logger)
+		shutdown(server, metricsServer, clusterNode, jobLocker, clusterCancel, logger)
 	}
 }
@@ -0,0 +1,226 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "gigo_ws"
+
+// Registry
+//
+//	Prometheus registry that holds all of the metrics
+//	exported by the provisioner
+var Registry = prometheus.NewRegistry()
+
+var (
+	rpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_requests_total",
+		Help:      "Total number of rpcs handled by the api server partitioned by rpc and response code.",
+	}, []string{"rpc", "code"})
+
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rpc_duration_seconds",
+		Help:      "Latency of the rpcs handled by the api server partitioned by rpc and response code.",
+		// provisioner rpcs range from sub-millisecond reads to
+		// terraform applies that take several minutes
+		Buckets: []float64{.005, .025, .1, .5, 1, 5, 15, 30, 60, 120, 300, 600},
+	}, []string{"rpc", "code"})
+
+	terraformDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "terraform_duration_seconds",
+		Help:      "Duration of terraform commands partitioned by operation and result.",
+		Buckets:   []float64{.5, 1, 5, 15, 30, 60, 120, 300, 600, 1200},
+	}, []string{"operation", "result"})
+
+	activeJobs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_jobs",
+		Help:      "Number of provisioner jobs currently held by this node.",
+	})
+
+	driftChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "drift_checks_total",
+		Help:      "Total number of workspace drift checks partitioned by the status of the check.",
+	}, []string{"status"})
+
+	driftedWorkspaces = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "drifted_workspaces",
+		Help:      "Number of workspaces that drifted from their statefile in the last drift scan performed by this node.",
+	})
+
+	driftScanTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "drift_scan_timestamp_seconds",
+		Help:      "Unix timestamp of the completion of the last drift scan performed by this node.",
+	})
+
+	terraformRunning = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "terraform_operations_running",
+		Help:      "Number of terraform operations currently running on this node.",
+	})
+
+	terraformQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "terraform_queue_depth",
+		Help:      "Number of terraform operations waiting for a slot on this node partitioned by priority.",
+	}, []string{"priority"})
+
+	terraformQueueRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "terraform_queue_rejections_total",
+		Help:      "Total number of terraform operations rejected because the queue was full partitioned by priority.",
+	}, []string{"priority"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		rpcRequests,
+		rpcDuration,
+		terraformDuration,
+		activeJobs,
+		driftChecks,
+		driftedWorkspaces,
+		driftScanTimestamp,
+		terraformRunning,
+		terraformQueueDepth,
+		terraformQueueRejections,
+	)
+}
+
+// Handler
+//
+//	Returns the http handler that serves the metrics of the registry
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRPC
+//
+//	Records the completion of an rpc with the response code that
+//	was returned to the caller
+func ObserveRPC(rpc string, code string, duration time.Duration) {
+	rpcRequests.WithLabelValues(rpc, code).Inc()
+	rpcDuration.WithLabelValues(rpc, code).Observe(duration.Seconds())
+}
+
+// ObserveTerraform
+//
+//	Records the duration of a terraform command. The result is
+//	derived from the exit code of the command.
+func ObserveTerraform(operation string, exitCode int, duration time.Duration) {
+	result := "success"
+	if exitCode != 0 {
+		result = "failure"
+	}
+	terraformDuration.WithLabelValues(operation, result).Observe(duration.Seconds())
+}
+
+// JobStarted
+//
+//	Increments the number of active provisioner jobs
+func JobStarted() {
+	activeJobs.Inc()
+}
+
+// JobFinished
+//
+//	Decrements the number of active provisioner jobs
+func JobFinished() {
+	activeJobs.Dec()
+}
+
+// ObserveDriftCheck
+//
+//	Records the completion of the drift check of a workspace
+func ObserveDriftCheck(status string) {
+	driftChecks.WithLabelValues(status).Inc()
+}
+
+// ObserveDriftScan
+//
+//	Records the completion of a drift scan across all workspaces
+func ObserveDriftScan(drifted int, completed time.Time) {
+	driftedWorkspaces.Set(float64(drifted))
+	driftScanTimestamp.Set(float64(completed.Unix()))
+}
+
+// ObserveTerraformQueue
+//
+//	Records the number of running terraform operations and the
+//	number of operations waiting for a slot by priority
+func ObserveTerraformQueue(running int, depth map[string]int) {
+	terraformRunning.Set(float64(running))
+	for priority, d := range depth {
+		terraformQueueDepth.WithLabelValues(priority).Set(float64(d))
+	}
+}
+
+// ObserveTerraformQueueRejection
+//
+//	Records a terraform operation that was rejected by a full queue
+func ObserveTerraformQueueRejection(priority string) {
+	terraformQueueRejections.WithLabelValues(priority).Inc()
+}
+
+// VolumePoolCount
+//
+//	Count of the volumes in a volume pool subpool
+type VolumePoolCount struct {
+	Size      int
+	Available int
+	InUse     int
+}
+
+var volumePoolDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "volpool", "volumes"),
+	"Number of volumes in the volume pool partitioned by subpool size and state.",
+	[]string{"size", "state"}, nil,
+)
+
+// volumePoolCollector
+//
+//	Collector that loads the volume counts of the volume pool
+//	each time the metrics are scraped
+type volumePoolCollector struct {
+	counts func() ([]VolumePoolCount, error)
+}
+
+func (c *volumePoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- volumePoolDesc
+}
+
+func (c *volumePoolCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.counts()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(volumePoolDesc, err)
+		return
+	}
+
+	for _, count := range counts {
+		size := strconv.Itoa(count.Size)
+		ch <- prometheus.MustNewConstMetric(volumePoolDesc, prometheus.GaugeValue, float64(count.Available), size, "available")
+		ch <- prometheus.MustNewConstMetric(volumePoolDesc, prometheus.GaugeValue, float64(count.InUse), size, "in_use")
+	}
+}
+
+// RegisterVolumePool
+//
+//	Registers a collector that exports the volume counts
+//	returned by the passed function for each subpool
+func RegisterVolumePool(counts func() ([]VolumePoolCount, error)) error {
+	return Registry.Register(&volumePoolCollector{counts: counts})
+}
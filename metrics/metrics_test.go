@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveRPC(t *testing.T) {
+	ObserveRPC("CreateWorkspace", "SUCCESS", time.Second)
+	ObserveRPC("CreateWorkspace", "SUCCESS", time.Second*2)
+	ObserveRPC("CreateWorkspace", "SERVER_EXECUTION_ERROR", time.Second)
+
+	if v := testutil.ToFloat64(rpcRequests.WithLabelValues("CreateWorkspace", "SUCCESS")); v != 2 {
+		t.Fatalf("expected 2 successful requests, got %v", v)
+	}
+
+	if v := testutil.ToFloat64(rpcRequests.WithLabelValues("CreateWorkspace", "SERVER_EXECUTION_ERROR")); v != 1 {
+		t.Fatalf("expected 1 failed request, got %v", v)
+	}
+}
+
+func TestActiveJobs(t *testing.T) {
+	JobStarted()
+	JobStarted()
+	JobFinished()
+
+	if v := testutil.ToFloat64(activeJobs); v != 1 {
+		t.Fatalf("expected 1 active job, got %v", v)
+	}
+
+	JobFinished()
+}
+
+func TestObserveDrift(t *testing.T) {
+	ObserveDriftCheck("Drifted")
+	ObserveDriftCheck("InSync")
+	ObserveDriftCheck("InSync")
+
+	if v := testutil.ToFloat64(driftChecks.WithLabelValues("InSync")); v != 2 {
+		t.Fatalf("expected 2 in sync checks, got %v", v)
+	}
+
+	ObserveDriftScan(1, time.Unix(1675188000, 0))
+
+	if v := testutil.ToFloat64(driftedWorkspaces); v != 1 {
+		t.Fatalf("expected 1 drifted workspace, got %v", v)
+	}
+	if v := testutil.ToFloat64(driftScanTimestamp); v != 1675188000 {
+		t.Fatalf("expected scan timestamp 1675188000, got %v", v)
+	}
+}
+
+func TestVolumePoolCollector(t *testing.T) {
+	c := &volumePoolCollector{counts: func() ([]VolumePoolCount, error) {
+		return []VolumePoolCount{
+			{Size: 5, Available: 3, InUse: 7},
+			{Size: 10, Available: 1, InUse: 0},
+		}, nil
+	}}
+
+	if n := testutil.CollectAndCount(c); n != 4 {
+		t.Fatalf("expected 4 volume pool metrics, got %d", n)
+	}
+
+	c.counts = func() ([]VolumePoolCount, error) {
+		return nil, fmt.Errorf("database unavailable")
+	}
+
+	_, err := testutil.CollectAndLint(c)
+	if err == nil {
+		t.Fatal("expected collection to fail when the counts cannot be loaded")
+	}
+}
+
+func TestObserveTerraformQueue(t *testing.T) {
+	ObserveTerraformQueue(3, map[string]int{"user": 2, "background": 5})
+	ObserveTerraformQueueRejection("background")
+
+	if v := testutil.ToFloat64(terraformRunning); v != 3 {
+		t.Fatalf("expected 3 running operations, got %v", v)
+	}
+
+	if v := testutil.ToFloat64(terraformQueueDepth.WithLabelValues("background")); v != 5 {
+		t.Fatalf("expected 5 queued background operations, got %v", v)
+	}
+
+	if v := testutil.ToFloat64(terraformQueueRejections.WithLabelValues("background")); v != 1 {
+		t.Fatalf("expected 1 rejected operation, got %v", v)
+	}
+}
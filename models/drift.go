@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+type DriftStatus int
+
+const (
+	DriftStatusInSync DriftStatus = iota
+	DriftStatusDrifted
+	DriftStatusFailed
+)
+
+func (s DriftStatus) String() string {
+	switch s {
+	case DriftStatusInSync:
+		return "InSync"
+	case DriftStatusDrifted:
+		return "Drifted"
+	case DriftStatusFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// ResourceDrift
+//
+//	Change made to a resource of a workspace outside of terraform
+type ResourceDrift struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	// Action is delete when the resource no longer exists
+	// and update when its attributes have changed
+	Action     string   `json:"action"`
+	Attributes []string `json:"attributes,omitempty"`
+}
+
+// DriftReport
+//
+//	Result of the last drift check performed against a workspace
+type DriftReport struct {
+	WorkspaceID int64           `json:"workspace_id"`
+	Status      DriftStatus     `json:"status"`
+	Resources   []ResourceDrift `json:"resources,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	NodeID      int64           `json:"node_id"`
+	CheckedAt   time.Time       `json:"checked_at"`
+}
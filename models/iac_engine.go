@@ -0,0 +1,20 @@
+package models
+
+type IaCEngineType int
+
+const (
+	// IaCEngineTerraform is the engine type for HashiCorp terraform
+	IaCEngineTerraform IaCEngineType = iota
+	// IaCEngineOpenTofu is the engine type for the OpenTofu fork of terraform
+	IaCEngineOpenTofu
+)
+
+func (t *IaCEngineType) String() string {
+	switch *t {
+	case IaCEngineTerraform:
+		return "terraform"
+	case IaCEngineOpenTofu:
+		return "opentofu"
+	}
+	return "unknown"
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+type JobStatus int
+
+const (
+	JobStatusPending JobStatus = iota
+	JobStatusRunning
+	JobStatusSucceeded
+	JobStatusFailed
+	JobStatusCancelled
+)
+
+func (s JobStatus) String() string {
+	switch s {
+	case JobStatusPending:
+		return "Pending"
+	case JobStatusRunning:
+		return "Running"
+	case JobStatusSucceeded:
+		return "Succeeded"
+	case JobStatusFailed:
+		return "Failed"
+	case JobStatusCancelled:
+		return "Cancelled"
+	default:
+		return "Unknown"
+	}
+}
+
+// Finished
+//
+//	Returns true if the job has reached a terminal status
+func (s JobStatus) Finished() bool {
+	return s == JobStatusSucceeded || s == JobStatusFailed || s == JobStatusCancelled
+}
+
+type JobOperation int
+
+const (
+	JobOperationCreate JobOperation = iota
+	JobOperationStart
+	JobOperationStop
+	JobOperationDestroy
+)
+
+func (o JobOperation) String() string {
+	switch o {
+	case JobOperationCreate:
+		return "Create"
+	case JobOperationStart:
+		return "Start"
+	case JobOperationStop:
+		return "Stop"
+	case JobOperationDestroy:
+		return "Destroy"
+	default:
+		return "Unknown"
+	}
+}
+
+// Job
+//
+//	Persistent record of an asynchronous provisioner operation
+//	executed against a workspace
+type Job struct {
+	ID          int64        `json:"id"`
+	WorkspaceID int64        `json:"workspace_id"`
+	Operation   JobOperation `json:"operation"`
+	Status      JobStatus    `json:"status"`
+	NodeID      int64        `json:"node_id"`
+	CreatedAt   time.Time    `json:"created_at"`
+	StartedAt   time.Time    `json:"started_at"`
+	FinishedAt  time.Time    `json:"finished_at"`
+	Agent       *Agent       `json:"agent,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
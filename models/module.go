@@ -6,12 +6,26 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/gage-technologies/gigo-lib/storage"
 	"github.com/gage-technologies/gigo-lib/utils"
 )
 
+// storedEnvPrefix prefix of the environment variables that are written to
+// storage with the module - everything else including the process environment
+// and the backend credentials is supplied again each time the module is run
+const storedEnvPrefix = "GIGO_"
+
+// isStoredEnv
+//
+//	Returns true if the environment variable is written to storage with
+//	the module. The workspace transition is set for each operation.
+func isStoredEnv(e string) bool {
+	return strings.HasPrefix(e, storedEnvPrefix) && !strings.HasPrefix(e, "GIGO_WORKSPACE_TRANSITION")
+}
+
 // TerraformModule
 //
 //	Simple abstraction for terraform modules. A TerraformModule
@@ -23,6 +37,9 @@ type TerraformModule struct {
 	Validated   bool
 	LocalPath   string
 	Environment []string
+	// TerraformVersion version of terraform that the module is operated
+	// with - empty for modules stored before versions were pinned
+	TerraformVersion string
 }
 
 // LoadModule
@@ -70,10 +87,10 @@ func DeleteModule(storageEngine storage.Storage, workspaceId int64) error {
 //	If there is an existing module written to the storage engine this
 //	function will overwrite it.
 func (m *TerraformModule) StoreModule(storageEngine storage.Storage) error {
-	// remove transition and sensitive variables from module environment
+	// only keep the gigo variables of the module environment
 	env := make([]string, 0)
 	for _, e := range m.Environment {
-		if strings.HasPrefix(e, "GIGO_WORKSPACE_TRANSITION") || strings.HasPrefix(e, "AWS_") {
+		if !isStoredEnv(e) {
 			continue
 		}
 		env = append(env, e)
@@ -81,10 +98,11 @@ func (m *TerraformModule) StoreModule(storageEngine storage.Storage) error {
 
 	// copy module excluding the local directory
 	c := &TerraformModule{
-		MainTF:      m.MainTF,
-		ModuleID:    m.ModuleID,
-		Validated:   m.Validated,
-		Environment: env,
+		MainTF:           m.MainTF,
+		ModuleID:         m.ModuleID,
+		Validated:        m.Validated,
+		Environment:      env,
+		TerraformVersion: m.TerraformVersion,
 	}
 
 	// gob encode the module
@@ -157,3 +175,78 @@ func (m *TerraformModule) WriteTemporaryCopy() error {
 
 	return nil
 }
+
+// GetWorkspaceResources
+//
+//	Parses the resource sizing of the workspace from the module
+//	environment. An error is returned if any of the resource
+//	variables are missing or malformed.
+func (m *TerraformModule) GetWorkspaceResources() (*WorkspaceResources, error) {
+	var cpu, mem, disk *int
+	for _, e := range m.Environment {
+		key, value, ok := strings.Cut(e, "=")
+		if !ok {
+			continue
+		}
+
+		// select the unit suffix and destination for the variable
+		var suffix string
+		var dst **int
+		switch key {
+		case "GIGO_WORKSPACE_CPU":
+			dst = &cpu
+		case "GIGO_WORKSPACE_MEM":
+			suffix = "G"
+			dst = &mem
+		case "GIGO_WORKSPACE_DISK":
+			suffix = "Gi"
+			dst = &disk
+		default:
+			continue
+		}
+
+		i, err := strconv.Atoi(strings.TrimSuffix(value, suffix))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s %q: %v", key, value, err)
+		}
+		*dst = &i
+	}
+
+	if cpu == nil || mem == nil || disk == nil {
+		return nil, fmt.Errorf("module environment is missing workspace resources")
+	}
+
+	return &WorkspaceResources{
+		CPU:    *cpu,
+		Memory: *mem,
+		Disk:   *disk,
+	}, nil
+}
+
+// SetWorkspaceResources
+//
+//	Rewrites the resource sizing variables of the module environment
+//	replacing any existing entries. Missing entries are appended.
+func (m *TerraformModule) SetWorkspaceResources(resources WorkspaceResources) {
+	values := map[string]string{
+		"GIGO_WORKSPACE_CPU":  fmt.Sprintf("%d", resources.CPU),
+		"GIGO_WORKSPACE_MEM":  fmt.Sprintf("%dG", resources.Memory),
+		"GIGO_WORKSPACE_DISK": fmt.Sprintf("%dGi", resources.Disk),
+	}
+
+	env := make([]string, 0, len(m.Environment))
+	for _, e := range m.Environment {
+		key, _, _ := strings.Cut(e, "=")
+		if _, ok := values[key]; ok {
+			continue
+		}
+		env = append(env, e)
+	}
+
+	// append in a fixed order so the environment is deterministic
+	for _, key := range []string{"GIGO_WORKSPACE_DISK", "GIGO_WORKSPACE_CPU", "GIGO_WORKSPACE_MEM"} {
+		env = append(env, fmt.Sprintf("%s=%s", key, values[key]))
+	}
+
+	m.Environment = env
+}
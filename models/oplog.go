@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OperationLog
+//
+//	Reference to the log of a single terraform operation performed
+//	on a workspace. The id is the name of the log within the
+//	workspace log directory and is formatted as <operation>-<unix nano>
+//	so that the logs of a workspace sort by the time they started.
+type OperationLog struct {
+	ID          string    `json:"id"`
+	WorkspaceID int64     `json:"workspace_id"`
+	Operation   string    `json:"operation"`
+	StartedAt   time.Time `json:"started_at"`
+}
+
+// NewOperationLog
+//
+//	Creates a reference to the log of an operation started at the passed time
+func NewOperationLog(workspaceId int64, operation string, startedAt time.Time) *OperationLog {
+	return &OperationLog{
+		ID:          fmt.Sprintf("%s-%d", operation, startedAt.UnixNano()),
+		WorkspaceID: workspaceId,
+		Operation:   operation,
+		StartedAt:   startedAt,
+	}
+}
+
+// ParseOperationLog
+//
+//	Parses the id of an operation log into a reference to the log
+func ParseOperationLog(workspaceId int64, id string) (*OperationLog, error) {
+	idx := strings.LastIndex(id, "-")
+	if idx < 1 {
+		return nil, fmt.Errorf("invalid operation log id: %q", id)
+	}
+
+	ts, err := strconv.ParseInt(id[idx+1:], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operation log id %q: %v", id, err)
+	}
+
+	return &OperationLog{
+		ID:          id,
+		WorkspaceID: workspaceId,
+		Operation:   id[:idx],
+		StartedAt:   time.Unix(0, ts),
+	}, nil
+}
+
+// OperationLogDir
+//
+//	Returns the storage directory holding the operation logs of a workspace
+func OperationLogDir(workspaceId int64) string {
+	return fmt.Sprintf("logs/%d", workspaceId)
+}
+
+// Path
+//
+//	Returns the storage path of the log
+func (l *OperationLog) Path() string {
+	return fmt.Sprintf("%s/%s", OperationLogDir(l.WorkspaceID), l.ID)
+}
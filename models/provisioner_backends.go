@@ -7,6 +7,12 @@ const (
 	ProvisionerBackendFS ProvisionerBackendType = iota
 	// ProvisionerBackendS3 is the provisioner backend type for S3 compliant storage
 	ProvisionerBackendS3
+	// ProvisionerBackendKubernetes is the provisioner backend type for kubernetes secrets
+	ProvisionerBackendKubernetes
+	// ProvisionerBackendPG is the provisioner backend type for postgres databases
+	ProvisionerBackendPG
+	// ProvisionerBackendHTTP is the provisioner backend type for the state server built into gigo-ws
+	ProvisionerBackendHTTP
 )
 
 func (t *ProvisionerBackendType) String() string {
@@ -15,6 +21,12 @@ func (t *ProvisionerBackendType) String() string {
 		return "fs"
 	case ProvisionerBackendS3:
 		return "s3"
+	case ProvisionerBackendKubernetes:
+		return "kubernetes"
+	case ProvisionerBackendPG:
+		return "pg"
+	case ProvisionerBackendHTTP:
+		return "http"
 	}
 	return "unknown"
 }
@@ -20,3 +20,13 @@ func (s WorkspaceState) String() string {
 		return "Unknown"
 	}
 }
+
+// WorkspaceResources
+//
+//	Resource sizing of a workspace as configured in the
+//	environment of the workspace's terraform module
+type WorkspaceResources struct {
+	CPU    int
+	Memory int
+	Disk   int
+}
@@ -219,6 +219,93 @@ func (x *CreateWorkspaceResponse) GetAgentToken() string {
 	return ""
 }
 
+type CreateWorkspaceStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status     ResponseCode    `protobuf:"varint,1,opt,name=status,proto3,enum=ws.ResponseCode" json:"status,omitempty"`
+	Success    *Success        `protobuf:"bytes,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error      *Error          `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Event      *TerraformEvent `protobuf:"bytes,4,opt,name=event,proto3" json:"event,omitempty"`
+	AgentId    int64           `protobuf:"varint,5,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	AgentToken string          `protobuf:"bytes,6,opt,name=agent_token,json=agentToken,proto3" json:"agent_token,omitempty"`
+}
+
+func (x *CreateWorkspaceStreamResponse) Reset() {
+	*x = CreateWorkspaceStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_create_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateWorkspaceStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWorkspaceStreamResponse) ProtoMessage() {}
+
+func (x *CreateWorkspaceStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_create_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWorkspaceStreamResponse.ProtoReflect.Descriptor instead.
+func (*CreateWorkspaceStreamResponse) Descriptor() ([]byte, []int) {
+	return file_create_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateWorkspaceStreamResponse) GetStatus() ResponseCode {
+	if x != nil {
+		return x.Status
+	}
+	return ResponseCode_SUCCESS
+}
+
+func (x *CreateWorkspaceStreamResponse) GetSuccess() *Success {
+	if x != nil {
+		return x.Success
+	}
+	return nil
+}
+
+func (x *CreateWorkspaceStreamResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *CreateWorkspaceStreamResponse) GetEvent() *TerraformEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *CreateWorkspaceStreamResponse) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *CreateWorkspaceStreamResponse) GetAgentToken() string {
+	if x != nil {
+		return x.AgentToken
+	}
+	return ""
+}
+
 var File_create_proto protoreflect.FileDescriptor
 
 var file_create_proto_rawDesc = []byte{
@@ -255,8 +342,23 @@ var file_create_proto_rawDesc = []byte{
 	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64,
 	0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18,
 	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x54, 0x6f, 0x6b, 0x65,
-	0x6e, 0x42, 0x0b, 0x5a, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x77, 0x73, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6e, 0x22, 0xf7, 0x01, 0x0a, 0x1d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x77, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x25, 0x0a,
+	0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b,
+	0x2e, 0x77, 0x73, 0x2e, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x07, 0x73, 0x75, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x12, 0x1f, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x77, 0x73, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x28, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x77, 0x73, 0x2e, 0x54, 0x65, 0x72, 0x72, 0x61, 0x66,
+	0x6f, 0x72, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x42, 0x0b, 0x5a, 0x09, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x77, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -271,23 +373,29 @@ func file_create_proto_rawDescGZIP() []byte {
 	return file_create_proto_rawDescData
 }
 
-var file_create_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_create_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_create_proto_goTypes = []interface{}{
-	(*CreateWorkspaceRequest)(nil),  // 0: ws.CreateWorkspaceRequest
-	(*CreateWorkspaceResponse)(nil), // 1: ws.CreateWorkspaceResponse
-	(ResponseCode)(0),               // 2: ws.ResponseCode
-	(*Success)(nil),                 // 3: ws.Success
-	(*Error)(nil),                   // 4: ws.Error
+	(*CreateWorkspaceRequest)(nil),        // 0: ws.CreateWorkspaceRequest
+	(*CreateWorkspaceResponse)(nil),       // 1: ws.CreateWorkspaceResponse
+	(*CreateWorkspaceStreamResponse)(nil), // 2: ws.CreateWorkspaceStreamResponse
+	(ResponseCode)(0),                     // 3: ws.ResponseCode
+	(*Success)(nil),                       // 4: ws.Success
+	(*Error)(nil),                         // 5: ws.Error
+	(*TerraformEvent)(nil),                // 6: ws.TerraformEvent
 }
 var file_create_proto_depIdxs = []int32{
-	2, // 0: ws.CreateWorkspaceResponse.status:type_name -> ws.ResponseCode
-	3, // 1: ws.CreateWorkspaceResponse.success:type_name -> ws.Success
-	4, // 2: ws.CreateWorkspaceResponse.error:type_name -> ws.Error
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	3, // 0: ws.CreateWorkspaceResponse.status:type_name -> ws.ResponseCode
+	4, // 1: ws.CreateWorkspaceResponse.success:type_name -> ws.Success
+	5, // 2: ws.CreateWorkspaceResponse.error:type_name -> ws.Error
+	3, // 3: ws.CreateWorkspaceStreamResponse.status:type_name -> ws.ResponseCode
+	4, // 4: ws.CreateWorkspaceStreamResponse.success:type_name -> ws.Success
+	5, // 5: ws.CreateWorkspaceStreamResponse.error:type_name -> ws.Error
+	6, // 6: ws.CreateWorkspaceStreamResponse.event:type_name -> ws.TerraformEvent
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_create_proto_init() }
@@ -321,6 +429,18 @@ func file_create_proto_init() {
 				return nil
 			}
 		}
+		file_create_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateWorkspaceStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -328,7 +448,7 @@ func file_create_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_create_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
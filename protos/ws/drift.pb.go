@@ -0,0 +1,527 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.15.8
+// source: drift.proto
+
+package ws
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// result of the last drift check of a workspace
+type DriftStatus int32
+
+const (
+	DriftStatus_DRIFT_IN_SYNC      DriftStatus = 0
+	DriftStatus_DRIFT_DETECTED     DriftStatus = 1
+	DriftStatus_DRIFT_CHECK_FAILED DriftStatus = 2
+)
+
+// Enum value maps for DriftStatus.
+var (
+	DriftStatus_name = map[int32]string{
+		0: "DRIFT_IN_SYNC",
+		1: "DRIFT_DETECTED",
+		2: "DRIFT_CHECK_FAILED",
+	}
+	DriftStatus_value = map[string]int32{
+		"DRIFT_IN_SYNC":      0,
+		"DRIFT_DETECTED":     1,
+		"DRIFT_CHECK_FAILED": 2,
+	}
+)
+
+func (x DriftStatus) Enum() *DriftStatus {
+	p := new(DriftStatus)
+	*p = x
+	return p
+}
+
+func (x DriftStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DriftStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_drift_proto_enumTypes[0].Descriptor()
+}
+
+func (DriftStatus) Type() protoreflect.EnumType {
+	return &file_drift_proto_enumTypes[0]
+}
+
+func (x DriftStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DriftStatus.Descriptor instead.
+func (DriftStatus) EnumDescriptor() ([]byte, []int) {
+	return file_drift_proto_rawDescGZIP(), []int{0}
+}
+
+type ResourceDrift struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// terraform address of the drifted resource
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Type    string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// delete when the resource no longer exists and update when
+	// its attributes were changed outside of terraform
+	Action string `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	// paths of the attributes that changed
+	Attributes []string `protobuf:"bytes,4,rep,name=attributes,proto3" json:"attributes,omitempty"`
+}
+
+func (x *ResourceDrift) Reset() {
+	*x = ResourceDrift{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drift_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResourceDrift) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceDrift) ProtoMessage() {}
+
+func (x *ResourceDrift) ProtoReflect() protoreflect.Message {
+	mi := &file_drift_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceDrift.ProtoReflect.Descriptor instead.
+func (*ResourceDrift) Descriptor() ([]byte, []int) {
+	return file_drift_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ResourceDrift) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *ResourceDrift) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ResourceDrift) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ResourceDrift) GetAttributes() []string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+type DriftReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WorkspaceId int64            `protobuf:"varint,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	Status      DriftStatus      `protobuf:"varint,2,opt,name=status,proto3,enum=ws.DriftStatus" json:"status,omitempty"`
+	Resources   []*ResourceDrift `protobuf:"bytes,3,rep,name=resources,proto3" json:"resources,omitempty"`
+	Error       string           `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// id of the node that performed the check
+	NodeId    int64 `protobuf:"varint,5,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	CheckedAt int64 `protobuf:"varint,6,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+}
+
+func (x *DriftReport) Reset() {
+	*x = DriftReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drift_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DriftReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DriftReport) ProtoMessage() {}
+
+func (x *DriftReport) ProtoReflect() protoreflect.Message {
+	mi := &file_drift_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DriftReport.ProtoReflect.Descriptor instead.
+func (*DriftReport) Descriptor() ([]byte, []int) {
+	return file_drift_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DriftReport) GetWorkspaceId() int64 {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return 0
+}
+
+func (x *DriftReport) GetStatus() DriftStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DriftStatus_DRIFT_IN_SYNC
+}
+
+func (x *DriftReport) GetResources() []*ResourceDrift {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *DriftReport) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DriftReport) GetNodeId() int64 {
+	if x != nil {
+		return x.NodeId
+	}
+	return 0
+}
+
+func (x *DriftReport) GetCheckedAt() int64 {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return 0
+}
+
+type GetWorkspaceDriftRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// auth is currently unused but we allocate the slot for later
+	Auth        string `protobuf:"bytes,1,opt,name=auth,proto3" json:"auth,omitempty"`
+	WorkspaceId int64  `protobuf:"varint,2,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+}
+
+func (x *GetWorkspaceDriftRequest) Reset() {
+	*x = GetWorkspaceDriftRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drift_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetWorkspaceDriftRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkspaceDriftRequest) ProtoMessage() {}
+
+func (x *GetWorkspaceDriftRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_drift_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkspaceDriftRequest.ProtoReflect.Descriptor instead.
+func (*GetWorkspaceDriftRequest) Descriptor() ([]byte, []int) {
+	return file_drift_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetWorkspaceDriftRequest) GetAuth() string {
+	if x != nil {
+		return x.Auth
+	}
+	return ""
+}
+
+func (x *GetWorkspaceDriftRequest) GetWorkspaceId() int64 {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return 0
+}
+
+type GetWorkspaceDriftResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  ResponseCode `protobuf:"varint,1,opt,name=status,proto3,enum=ws.ResponseCode" json:"status,omitempty"`
+	Success *Success     `protobuf:"bytes,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error   *Error       `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Report  *DriftReport `protobuf:"bytes,4,opt,name=report,proto3" json:"report,omitempty"`
+}
+
+func (x *GetWorkspaceDriftResponse) Reset() {
+	*x = GetWorkspaceDriftResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_drift_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetWorkspaceDriftResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkspaceDriftResponse) ProtoMessage() {}
+
+func (x *GetWorkspaceDriftResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_drift_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkspaceDriftResponse.ProtoReflect.Descriptor instead.
+func (*GetWorkspaceDriftResponse) Descriptor() ([]byte, []int) {
+	return file_drift_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetWorkspaceDriftResponse) GetStatus() ResponseCode {
+	if x != nil {
+		return x.Status
+	}
+	return ResponseCode_SUCCESS
+}
+
+func (x *GetWorkspaceDriftResponse) GetSuccess() *Success {
+	if x != nil {
+		return x.Success
+	}
+	return nil
+}
+
+func (x *GetWorkspaceDriftResponse) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *GetWorkspaceDriftResponse) GetReport() *DriftReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+var File_drift_proto protoreflect.FileDescriptor
+
+var file_drift_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x64, 0x72, 0x69, 0x66, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x77,
+	0x73, 0x1a, 0x0b, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x75,
+	0x0a, 0x0d, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x44, 0x72, 0x69, 0x66, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75,
+	0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69,
+	0x62, 0x75, 0x74, 0x65, 0x73, 0x22, 0xd8, 0x01, 0x0a, 0x0b, 0x44, 0x72, 0x69, 0x66, 0x74, 0x52,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x77, 0x6f, 0x72,
+	0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x27, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x77, 0x73, 0x2e, 0x44, 0x72,
+	0x69, 0x66, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x2f, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x44, 0x72, 0x69, 0x66, 0x74, 0x52, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49,
+	0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x41, 0x74,
+	0x22, 0x51, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x44, 0x72, 0x69, 0x66, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x61, 0x75, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x75, 0x74, 0x68,
+	0x12, 0x21, 0x0a, 0x0c, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x49, 0x64, 0x22, 0xb6, 0x01, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x44, 0x72, 0x69, 0x66, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x28, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x10, 0x2e, 0x77, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43,
+	0x6f, 0x64, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x25, 0x0a, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x77,
+	0x73, 0x2e, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x12, 0x1f, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x09, 0x2e, 0x77, 0x73, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x77, 0x73, 0x2e, 0x44, 0x72, 0x69, 0x66, 0x74, 0x52, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x52, 0x06, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x2a, 0x4c, 0x0a, 0x0b,
+	0x44, 0x72, 0x69, 0x66, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x11, 0x0a, 0x0d, 0x44,
+	0x52, 0x49, 0x46, 0x54, 0x5f, 0x49, 0x4e, 0x5f, 0x53, 0x59, 0x4e, 0x43, 0x10, 0x00, 0x12, 0x12,
+	0x0a, 0x0e, 0x44, 0x52, 0x49, 0x46, 0x54, 0x5f, 0x44, 0x45, 0x54, 0x45, 0x43, 0x54, 0x45, 0x44,
+	0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x44, 0x52, 0x49, 0x46, 0x54, 0x5f, 0x43, 0x48, 0x45, 0x43,
+	0x4b, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x02, 0x42, 0x0b, 0x5a, 0x09, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x77, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_drift_proto_rawDescOnce sync.Once
+	file_drift_proto_rawDescData = file_drift_proto_rawDesc
+)
+
+func file_drift_proto_rawDescGZIP() []byte {
+	file_drift_proto_rawDescOnce.Do(func() {
+		file_drift_proto_rawDescData = protoimpl.X.CompressGZIP(file_drift_proto_rawDescData)
+	})
+	return file_drift_proto_rawDescData
+}
+
+var file_drift_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_drift_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_drift_proto_goTypes = []interface{}{
+	(DriftStatus)(0),                  // 0: ws.DriftStatus
+	(*ResourceDrift)(nil),             // 1: ws.ResourceDrift
+	(*DriftReport)(nil),               // 2: ws.DriftReport
+	(*GetWorkspaceDriftRequest)(nil),  // 3: ws.GetWorkspaceDriftRequest
+	(*GetWorkspaceDriftResponse)(nil), // 4: ws.GetWorkspaceDriftResponse
+	(ResponseCode)(0),                 // 5: ws.ResponseCode
+	(*Success)(nil),                   // 6: ws.Success
+	(*Error)(nil),                     // 7: ws.Error
+}
+var file_drift_proto_depIdxs = []int32{
+	0, // 0: ws.DriftReport.status:type_name -> ws.DriftStatus
+	1, // 1: ws.DriftReport.resources:type_name -> ws.ResourceDrift
+	5, // 2: ws.GetWorkspaceDriftResponse.status:type_name -> ws.ResponseCode
+	6, // 3: ws.GetWorkspaceDriftResponse.success:type_name -> ws.Success
+	7, // 4: ws.GetWorkspaceDriftResponse.error:type_name -> ws.Error
+	2, // 5: ws.GetWorkspaceDriftResponse.report:type_name -> ws.DriftReport
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_drift_proto_init() }
+func file_drift_proto_init() {
+	if File_drift_proto != nil {
+		return
+	}
+	file_types_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_drift_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResourceDrift); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drift_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DriftReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drift_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetWorkspaceDriftRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_drift_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetWorkspaceDriftResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_drift_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_drift_proto_goTypes,
+		DependencyIndexes: file_drift_proto_depIdxs,
+		EnumInfos:         file_drift_proto_enumTypes,
+		MessageInfos:      file_drift_proto_msgTypes,
+	}.Build()
+	File_drift_proto = out.File
+	file_drift_proto_rawDesc = nil
+	file_drift_proto_goTypes = nil
+	file_drift_proto_depIdxs = nil
+}
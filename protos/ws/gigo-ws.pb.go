@@ -27,61 +27,239 @@ var file_gigo_ws_proto_rawDesc = []byte{
 	0x6f, 0x1a, 0x0b, 0x73, 0x74, 0x61, 0x72, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0a,
 	0x73, 0x74, 0x6f, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0d, 0x64, 0x65, 0x73, 0x74,
 	0x72, 0x6f, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0a, 0x65, 0x63, 0x68, 0x6f, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xe7, 0x02, 0x0a, 0x06, 0x47, 0x69, 0x67, 0x6f, 0x57, 0x53,
-	0x12, 0x2b, 0x0a, 0x04, 0x45, 0x63, 0x68, 0x6f, 0x12, 0x0f, 0x2e, 0x77, 0x73, 0x2e, 0x45, 0x63,
-	0x68, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x77, 0x73, 0x2e, 0x45,
-	0x63, 0x68, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4c, 0x0a,
-	0x0f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65,
-	0x12, 0x1a, 0x2e, 0x77, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b,
-	0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x77,
-	0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63,
-	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0e, 0x53,
-	0x74, 0x61, 0x72, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x19, 0x2e,
-	0x77, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x77, 0x73, 0x2e, 0x53, 0x74,
-	0x61, 0x72, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0d, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x6f,
-	0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x18, 0x2e, 0x77, 0x73, 0x2e, 0x53, 0x74, 0x6f,
-	0x70, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x19, 0x2e, 0x77, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x6f, 0x72, 0x6b, 0x73,
-	0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f,
-	0x0a, 0x10, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
-	0x63, 0x65, 0x12, 0x1b, 0x2e, 0x77, 0x73, 0x2e, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x57,
-	0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x1c, 0x2e, 0x77, 0x73, 0x2e, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x57, 0x6f, 0x72, 0x6b,
-	0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42,
-	0x0b, 0x5a, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x77, 0x73, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x09, 0x67, 0x65, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x0a, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x09, 0x6a, 0x6f,
+	0x62, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0c, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0c, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x0a, 0x70, 0x6c, 0x61, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x0a, 0x6c, 0x6f, 0x63, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0b, 0x64, 0x72, 0x69,
+	0x66, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0d, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0a, 0x6c, 0x6f, 0x67, 0x73, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x0d, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x32, 0x90, 0x0f, 0x0a, 0x06, 0x47, 0x69, 0x67, 0x6f, 0x57, 0x53, 0x12, 0x2b, 0x0a,
+	0x04, 0x45, 0x63, 0x68, 0x6f, 0x12, 0x0f, 0x2e, 0x77, 0x73, 0x2e, 0x45, 0x63, 0x68, 0x6f, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x77, 0x73, 0x2e, 0x45, 0x63, 0x68, 0x6f,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0f, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1a, 0x2e,
+	0x77, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x77, 0x73, 0x2e, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0e, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x19, 0x2e, 0x77, 0x73, 0x2e,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x77, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0d, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x6f, 0x72, 0x6b, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x12, 0x18, 0x2e, 0x77, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x6f,
+	0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x77, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x10, 0x44,
+	0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12,
+	0x1b, 0x2e, 0x77, 0x73, 0x2e, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x57, 0x6f, 0x72, 0x6b,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x77,
+	0x73, 0x2e, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5a, 0x0a, 0x15,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1a, 0x2e, 0x77, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x21, 0x2e, 0x77, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72,
+	0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x57, 0x0a, 0x14, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x12, 0x19, 0x2e, 0x77, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x77, 0x73,
+	0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30,
+	0x01, 0x12, 0x43, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x12, 0x17, 0x2e, 0x77, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x77, 0x73, 0x2e,
+	0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x6f,
+	0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x77, 0x73, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x77, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x6f, 0x72,
+	0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x4c, 0x0a, 0x15, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1a, 0x2e, 0x77, 0x73, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x77, 0x73, 0x2e, 0x53, 0x75, 0x62, 0x6d,
+	0x69, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x4a, 0x0a, 0x14, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x74, 0x61, 0x72, 0x74, 0x57, 0x6f,
+	0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x19, 0x2e, 0x77, 0x73, 0x2e, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x15, 0x2e, 0x77, 0x73, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4a, 0x6f,
+	0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x13, 0x53,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x12, 0x18, 0x2e, 0x77, 0x73, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x57, 0x6f, 0x72, 0x6b,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x77,
+	0x73, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4e, 0x0a, 0x16, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x44,
+	0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12,
+	0x1b, 0x2e, 0x77, 0x73, 0x2e, 0x44, 0x65, 0x73, 0x74, 0x72, 0x6f, 0x79, 0x57, 0x6f, 0x72, 0x6b,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x77,
+	0x73, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x06, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x12,
+	0x11, 0x2e, 0x77, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x12, 0x2e, 0x77, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x08, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x4a, 0x6f, 0x62, 0x12, 0x13, 0x2e, 0x77, 0x73, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4a,
+	0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x77, 0x73, 0x2e, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x4c, 0x0a, 0x0f, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x2e, 0x77, 0x73, 0x2e, 0x43, 0x61, 0x6e, 0x63,
+	0x65, 0x6c, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x77, 0x73, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x67, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x23, 0x2e,
+	0x77, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x24, 0x2e, 0x77, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x57, 0x6f,
+	0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0d, 0x50, 0x6c,
+	0x61, 0x6e, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x18, 0x2e, 0x77, 0x73,
+	0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x77, 0x73, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x57,
+	0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x40, 0x0a, 0x0b, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x55, 0x6e, 0x6c, 0x6f, 0x63,
+	0x6b, 0x12, 0x16, 0x2e, 0x77, 0x73, 0x2e, 0x46, 0x6f, 0x72, 0x63, 0x65, 0x55, 0x6e, 0x6c, 0x6f,
+	0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x77, 0x73, 0x2e, 0x46,
+	0x6f, 0x72, 0x63, 0x65, 0x55, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x44, 0x72, 0x69, 0x66, 0x74, 0x12, 0x1c, 0x2e, 0x77, 0x73, 0x2e, 0x47,
+	0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x44, 0x72, 0x69, 0x66, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x77, 0x73, 0x2e, 0x47, 0x65, 0x74,
+	0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x44, 0x72, 0x69, 0x66, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x10, 0x55, 0x70, 0x67, 0x72,
+	0x61, 0x64, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1b, 0x2e, 0x77,
+	0x73, 0x2e, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x77, 0x73, 0x2e, 0x55,
+	0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x10, 0x47, 0x65, 0x74,
+	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x1b, 0x2e,
+	0x77, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4c,
+	0x6f, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x77, 0x73, 0x2e,
+	0x47, 0x65, 0x74, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5e, 0x0a, 0x15, 0x4c, 0x69,
+	0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x66, 0x69, 0x6c, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x20, 0x2e, 0x77, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x66, 0x69, 0x6c, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x77, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x66, 0x69, 0x6c, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x13, 0x47, 0x65,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x66, 0x69, 0x6c, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x1e, 0x2e, 0x77, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x66,
+	0x69, 0x6c, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1f, 0x2e, 0x77, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x66,
+	0x69, 0x6c, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1b, 0x2e, 0x77, 0x73, 0x2e, 0x52, 0x65,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x77, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x0b, 0x5a, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f,
+	0x77, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_gigo_ws_proto_goTypes = []interface{}{
-	(*EchoRequest)(nil),              // 0: ws.EchoRequest
-	(*CreateWorkspaceRequest)(nil),   // 1: ws.CreateWorkspaceRequest
-	(*StartWorkspaceRequest)(nil),    // 2: ws.StartWorkspaceRequest
-	(*StopWorkspaceRequest)(nil),     // 3: ws.StopWorkspaceRequest
-	(*DestroyWorkspaceRequest)(nil),  // 4: ws.DestroyWorkspaceRequest
-	(*EchoResponse)(nil),             // 5: ws.EchoResponse
-	(*CreateWorkspaceResponse)(nil),  // 6: ws.CreateWorkspaceResponse
-	(*StartWorkspaceResponse)(nil),   // 7: ws.StartWorkspaceResponse
-	(*StopWorkspaceResponse)(nil),    // 8: ws.StopWorkspaceResponse
-	(*DestroyWorkspaceResponse)(nil), // 9: ws.DestroyWorkspaceResponse
+	(*EchoRequest)(nil),                      // 0: ws.EchoRequest
+	(*CreateWorkspaceRequest)(nil),           // 1: ws.CreateWorkspaceRequest
+	(*StartWorkspaceRequest)(nil),            // 2: ws.StartWorkspaceRequest
+	(*StopWorkspaceRequest)(nil),             // 3: ws.StopWorkspaceRequest
+	(*DestroyWorkspaceRequest)(nil),          // 4: ws.DestroyWorkspaceRequest
+	(*GetWorkspaceRequest)(nil),              // 5: ws.GetWorkspaceRequest
+	(*ListWorkspacesRequest)(nil),            // 6: ws.ListWorkspacesRequest
+	(*GetJobRequest)(nil),                    // 7: ws.GetJobRequest
+	(*WatchJobRequest)(nil),                  // 8: ws.WatchJobRequest
+	(*CancelOperationRequest)(nil),           // 9: ws.CancelOperationRequest
+	(*UpdateWorkspaceResourcesRequest)(nil),  // 10: ws.UpdateWorkspaceResourcesRequest
+	(*PlanWorkspaceRequest)(nil),             // 11: ws.PlanWorkspaceRequest
+	(*ForceUnlockRequest)(nil),               // 12: ws.ForceUnlockRequest
+	(*GetWorkspaceDriftRequest)(nil),         // 13: ws.GetWorkspaceDriftRequest
+	(*UpgradeWorkspaceRequest)(nil),          // 14: ws.UpgradeWorkspaceRequest
+	(*GetOperationLogsRequest)(nil),          // 15: ws.GetOperationLogsRequest
+	(*ListStatefileVersionsRequest)(nil),     // 16: ws.ListStatefileVersionsRequest
+	(*GetStatefileVersionRequest)(nil),       // 17: ws.GetStatefileVersionRequest
+	(*RestoreStatefileRequest)(nil),          // 18: ws.RestoreStatefileRequest
+	(*EchoResponse)(nil),                     // 19: ws.EchoResponse
+	(*CreateWorkspaceResponse)(nil),          // 20: ws.CreateWorkspaceResponse
+	(*StartWorkspaceResponse)(nil),           // 21: ws.StartWorkspaceResponse
+	(*StopWorkspaceResponse)(nil),            // 22: ws.StopWorkspaceResponse
+	(*DestroyWorkspaceResponse)(nil),         // 23: ws.DestroyWorkspaceResponse
+	(*CreateWorkspaceStreamResponse)(nil),    // 24: ws.CreateWorkspaceStreamResponse
+	(*StartWorkspaceStreamResponse)(nil),     // 25: ws.StartWorkspaceStreamResponse
+	(*GetWorkspaceResponse)(nil),             // 26: ws.GetWorkspaceResponse
+	(*ListWorkspacesResponse)(nil),           // 27: ws.ListWorkspacesResponse
+	(*SubmitJobResponse)(nil),                // 28: ws.SubmitJobResponse
+	(*GetJobResponse)(nil),                   // 29: ws.GetJobResponse
+	(*WatchJobResponse)(nil),                 // 30: ws.WatchJobResponse
+	(*CancelOperationResponse)(nil),          // 31: ws.CancelOperationResponse
+	(*UpdateWorkspaceResourcesResponse)(nil), // 32: ws.UpdateWorkspaceResourcesResponse
+	(*PlanWorkspaceResponse)(nil),            // 33: ws.PlanWorkspaceResponse
+	(*ForceUnlockResponse)(nil),              // 34: ws.ForceUnlockResponse
+	(*GetWorkspaceDriftResponse)(nil),        // 35: ws.GetWorkspaceDriftResponse
+	(*UpgradeWorkspaceResponse)(nil),         // 36: ws.UpgradeWorkspaceResponse
+	(*GetOperationLogsResponse)(nil),         // 37: ws.GetOperationLogsResponse
+	(*ListStatefileVersionsResponse)(nil),    // 38: ws.ListStatefileVersionsResponse
+	(*GetStatefileVersionResponse)(nil),      // 39: ws.GetStatefileVersionResponse
+	(*RestoreStatefileResponse)(nil),         // 40: ws.RestoreStatefileResponse
 }
 var file_gigo_ws_proto_depIdxs = []int32{
-	0, // 0: ws.GigoWS.Echo:input_type -> ws.EchoRequest
-	1, // 1: ws.GigoWS.CreateWorkspace:input_type -> ws.CreateWorkspaceRequest
-	2, // 2: ws.GigoWS.StartWorkspace:input_type -> ws.StartWorkspaceRequest
-	3, // 3: ws.GigoWS.StopWorkspace:input_type -> ws.StopWorkspaceRequest
-	4, // 4: ws.GigoWS.DestroyWorkspace:input_type -> ws.DestroyWorkspaceRequest
-	5, // 5: ws.GigoWS.Echo:output_type -> ws.EchoResponse
-	6, // 6: ws.GigoWS.CreateWorkspace:output_type -> ws.CreateWorkspaceResponse
-	7, // 7: ws.GigoWS.StartWorkspace:output_type -> ws.StartWorkspaceResponse
-	8, // 8: ws.GigoWS.StopWorkspace:output_type -> ws.StopWorkspaceResponse
-	9, // 9: ws.GigoWS.DestroyWorkspace:output_type -> ws.DestroyWorkspaceResponse
-	5, // [5:10] is the sub-list for method output_type
-	0, // [0:5] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: ws.GigoWS.Echo:input_type -> ws.EchoRequest
+	1,  // 1: ws.GigoWS.CreateWorkspace:input_type -> ws.CreateWorkspaceRequest
+	2,  // 2: ws.GigoWS.StartWorkspace:input_type -> ws.StartWorkspaceRequest
+	3,  // 3: ws.GigoWS.StopWorkspace:input_type -> ws.StopWorkspaceRequest
+	4,  // 4: ws.GigoWS.DestroyWorkspace:input_type -> ws.DestroyWorkspaceRequest
+	1,  // 5: ws.GigoWS.CreateWorkspaceStream:input_type -> ws.CreateWorkspaceRequest
+	2,  // 6: ws.GigoWS.StartWorkspaceStream:input_type -> ws.StartWorkspaceRequest
+	5,  // 7: ws.GigoWS.GetWorkspace:input_type -> ws.GetWorkspaceRequest
+	6,  // 8: ws.GigoWS.ListWorkspaces:input_type -> ws.ListWorkspacesRequest
+	1,  // 9: ws.GigoWS.SubmitCreateWorkspace:input_type -> ws.CreateWorkspaceRequest
+	2,  // 10: ws.GigoWS.SubmitStartWorkspace:input_type -> ws.StartWorkspaceRequest
+	3,  // 11: ws.GigoWS.SubmitStopWorkspace:input_type -> ws.StopWorkspaceRequest
+	4,  // 12: ws.GigoWS.SubmitDestroyWorkspace:input_type -> ws.DestroyWorkspaceRequest
+	7,  // 13: ws.GigoWS.GetJob:input_type -> ws.GetJobRequest
+	8,  // 14: ws.GigoWS.WatchJob:input_type -> ws.WatchJobRequest
+	9,  // 15: ws.GigoWS.CancelOperation:input_type -> ws.CancelOperationRequest
+	10, // 16: ws.GigoWS.UpdateWorkspaceResources:input_type -> ws.UpdateWorkspaceResourcesRequest
+	11, // 17: ws.GigoWS.PlanWorkspace:input_type -> ws.PlanWorkspaceRequest
+	12, // 18: ws.GigoWS.ForceUnlock:input_type -> ws.ForceUnlockRequest
+	13, // 19: ws.GigoWS.GetWorkspaceDrift:input_type -> ws.GetWorkspaceDriftRequest
+	14, // 20: ws.GigoWS.UpgradeWorkspace:input_type -> ws.UpgradeWorkspaceRequest
+	15, // 21: ws.GigoWS.GetOperationLogs:input_type -> ws.GetOperationLogsRequest
+	16, // 22: ws.GigoWS.ListStatefileVersions:input_type -> ws.ListStatefileVersionsRequest
+	17, // 23: ws.GigoWS.GetStatefileVersion:input_type -> ws.GetStatefileVersionRequest
+	18, // 24: ws.GigoWS.RestoreStatefile:input_type -> ws.RestoreStatefileRequest
+	19, // 25: ws.GigoWS.Echo:output_type -> ws.EchoResponse
+	20, // 26: ws.GigoWS.CreateWorkspace:output_type -> ws.CreateWorkspaceResponse
+	21, // 27: ws.GigoWS.StartWorkspace:output_type -> ws.StartWorkspaceResponse
+	22, // 28: ws.GigoWS.StopWorkspace:output_type -> ws.StopWorkspaceResponse
+	23, // 29: ws.GigoWS.DestroyWorkspace:output_type -> ws.DestroyWorkspaceResponse
+	24, // 30: ws.GigoWS.CreateWorkspaceStream:output_type -> ws.CreateWorkspaceStreamResponse
+	25, // 31: ws.GigoWS.StartWorkspaceStream:output_type -> ws.StartWorkspaceStreamResponse
+	26, // 32: ws.GigoWS.GetWorkspace:output_type -> ws.GetWorkspaceResponse
+	27, // 33: ws.GigoWS.ListWorkspaces:output_type -> ws.ListWorkspacesResponse
+	28, // 34: ws.GigoWS.SubmitCreateWorkspace:output_type -> ws.SubmitJobResponse
+	28, // 35: ws.GigoWS.SubmitStartWorkspace:output_type -> ws.SubmitJobResponse
+	28, // 36: ws.GigoWS.SubmitStopWorkspace:output_type -> ws.SubmitJobResponse
+	28, // 37: ws.GigoWS.SubmitDestroyWorkspace:output_type -> ws.SubmitJobResponse
+	29, // 38: ws.GigoWS.GetJob:output_type -> ws.GetJobResponse
+	30, // 39: ws.GigoWS.WatchJob:output_type -> ws.WatchJobResponse
+	31, // 40: ws.GigoWS.CancelOperation:output_type -> ws.CancelOperationResponse
+	32, // 41: ws.GigoWS.UpdateWorkspaceResources:output_type -> ws.UpdateWorkspaceResourcesResponse
+	33, // 42: ws.GigoWS.PlanWorkspace:output_type -> ws.PlanWorkspaceResponse
+	34, // 43: ws.GigoWS.ForceUnlock:output_type -> ws.ForceUnlockResponse
+	35, // 44: ws.GigoWS.GetWorkspaceDrift:output_type -> ws.GetWorkspaceDriftResponse
+	36, // 45: ws.GigoWS.UpgradeWorkspace:output_type -> ws.UpgradeWorkspaceResponse
+	37, // 46: ws.GigoWS.GetOperationLogs:output_type -> ws.GetOperationLogsResponse
+	38, // 47: ws.GigoWS.ListStatefileVersions:output_type -> ws.ListStatefileVersionsResponse
+	39, // 48: ws.GigoWS.GetStatefileVersion:output_type -> ws.GetStatefileVersionResponse
+	40, // 49: ws.GigoWS.RestoreStatefile:output_type -> ws.RestoreStatefileResponse
+	25, // [25:50] is the sub-list for method output_type
+	0,  // [0:25] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_gigo_ws_proto_init() }
@@ -94,6 +272,17 @@ func file_gigo_ws_proto_init() {
 	file_stop_proto_init()
 	file_destroy_proto_init()
 	file_echo_proto_init()
+	file_get_proto_init()
+	file_list_proto_init()
+	file_job_proto_init()
+	file_cancel_proto_init()
+	file_update_proto_init()
+	file_plan_proto_init()
+	file_lock_proto_init()
+	file_drift_proto_init()
+	file_upgrade_proto_init()
+	file_logs_proto_init()
+	file_history_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
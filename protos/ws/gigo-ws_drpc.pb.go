@@ -43,6 +43,26 @@ type DRPCGigoWSClient interface {
 	StartWorkspace(ctx context.Context, in *StartWorkspaceRequest) (*StartWorkspaceResponse, error)
 	StopWorkspace(ctx context.Context, in *StopWorkspaceRequest) (*StopWorkspaceResponse, error)
 	DestroyWorkspace(ctx context.Context, in *DestroyWorkspaceRequest) (*DestroyWorkspaceResponse, error)
+	CreateWorkspaceStream(ctx context.Context, in *CreateWorkspaceRequest) (DRPCGigoWS_CreateWorkspaceStreamClient, error)
+	StartWorkspaceStream(ctx context.Context, in *StartWorkspaceRequest) (DRPCGigoWS_StartWorkspaceStreamClient, error)
+	GetWorkspace(ctx context.Context, in *GetWorkspaceRequest) (*GetWorkspaceResponse, error)
+	ListWorkspaces(ctx context.Context, in *ListWorkspacesRequest) (*ListWorkspacesResponse, error)
+	SubmitCreateWorkspace(ctx context.Context, in *CreateWorkspaceRequest) (*SubmitJobResponse, error)
+	SubmitStartWorkspace(ctx context.Context, in *StartWorkspaceRequest) (*SubmitJobResponse, error)
+	SubmitStopWorkspace(ctx context.Context, in *StopWorkspaceRequest) (*SubmitJobResponse, error)
+	SubmitDestroyWorkspace(ctx context.Context, in *DestroyWorkspaceRequest) (*SubmitJobResponse, error)
+	GetJob(ctx context.Context, in *GetJobRequest) (*GetJobResponse, error)
+	WatchJob(ctx context.Context, in *WatchJobRequest) (DRPCGigoWS_WatchJobClient, error)
+	CancelOperation(ctx context.Context, in *CancelOperationRequest) (*CancelOperationResponse, error)
+	UpdateWorkspaceResources(ctx context.Context, in *UpdateWorkspaceResourcesRequest) (*UpdateWorkspaceResourcesResponse, error)
+	PlanWorkspace(ctx context.Context, in *PlanWorkspaceRequest) (*PlanWorkspaceResponse, error)
+	ForceUnlock(ctx context.Context, in *ForceUnlockRequest) (*ForceUnlockResponse, error)
+	GetWorkspaceDrift(ctx context.Context, in *GetWorkspaceDriftRequest) (*GetWorkspaceDriftResponse, error)
+	UpgradeWorkspace(ctx context.Context, in *UpgradeWorkspaceRequest) (*UpgradeWorkspaceResponse, error)
+	GetOperationLogs(ctx context.Context, in *GetOperationLogsRequest) (*GetOperationLogsResponse, error)
+	ListStatefileVersions(ctx context.Context, in *ListStatefileVersionsRequest) (*ListStatefileVersionsResponse, error)
+	GetStatefileVersion(ctx context.Context, in *GetStatefileVersionRequest) (*GetStatefileVersionResponse, error)
+	RestoreStatefile(ctx context.Context, in *RestoreStatefileRequest) (*RestoreStatefileResponse, error)
 }
 
 type drpcGigoWSClient struct {
@@ -100,12 +120,293 @@ func (c *drpcGigoWSClient) DestroyWorkspace(ctx context.Context, in *DestroyWork
 	return out, nil
 }
 
+func (c *drpcGigoWSClient) CreateWorkspaceStream(ctx context.Context, in *CreateWorkspaceRequest) (DRPCGigoWS_CreateWorkspaceStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/ws.GigoWS/CreateWorkspaceStream", drpcEncoding_File_gigo_ws_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcGigoWS_CreateWorkspaceStreamClient{stream}
+	if err := x.MsgSend(in, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DRPCGigoWS_CreateWorkspaceStreamClient interface {
+	drpc.Stream
+	Recv() (*CreateWorkspaceStreamResponse, error)
+}
+
+type drpcGigoWS_CreateWorkspaceStreamClient struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_CreateWorkspaceStreamClient) Recv() (*CreateWorkspaceStreamResponse, error) {
+	m := new(CreateWorkspaceStreamResponse)
+	if err := x.MsgRecv(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcGigoWS_CreateWorkspaceStreamClient) RecvMsg(m *CreateWorkspaceStreamResponse) error {
+	return x.MsgRecv(m, drpcEncoding_File_gigo_ws_proto{})
+}
+
+func (c *drpcGigoWSClient) StartWorkspaceStream(ctx context.Context, in *StartWorkspaceRequest) (DRPCGigoWS_StartWorkspaceStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/ws.GigoWS/StartWorkspaceStream", drpcEncoding_File_gigo_ws_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcGigoWS_StartWorkspaceStreamClient{stream}
+	if err := x.MsgSend(in, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DRPCGigoWS_StartWorkspaceStreamClient interface {
+	drpc.Stream
+	Recv() (*StartWorkspaceStreamResponse, error)
+}
+
+type drpcGigoWS_StartWorkspaceStreamClient struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_StartWorkspaceStreamClient) Recv() (*StartWorkspaceStreamResponse, error) {
+	m := new(StartWorkspaceStreamResponse)
+	if err := x.MsgRecv(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcGigoWS_StartWorkspaceStreamClient) RecvMsg(m *StartWorkspaceStreamResponse) error {
+	return x.MsgRecv(m, drpcEncoding_File_gigo_ws_proto{})
+}
+
+func (c *drpcGigoWSClient) GetWorkspace(ctx context.Context, in *GetWorkspaceRequest) (*GetWorkspaceResponse, error) {
+	out := new(GetWorkspaceResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/GetWorkspace", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) ListWorkspaces(ctx context.Context, in *ListWorkspacesRequest) (*ListWorkspacesResponse, error) {
+	out := new(ListWorkspacesResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/ListWorkspaces", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) SubmitCreateWorkspace(ctx context.Context, in *CreateWorkspaceRequest) (*SubmitJobResponse, error) {
+	out := new(SubmitJobResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/SubmitCreateWorkspace", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) SubmitStartWorkspace(ctx context.Context, in *StartWorkspaceRequest) (*SubmitJobResponse, error) {
+	out := new(SubmitJobResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/SubmitStartWorkspace", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) SubmitStopWorkspace(ctx context.Context, in *StopWorkspaceRequest) (*SubmitJobResponse, error) {
+	out := new(SubmitJobResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/SubmitStopWorkspace", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) SubmitDestroyWorkspace(ctx context.Context, in *DestroyWorkspaceRequest) (*SubmitJobResponse, error) {
+	out := new(SubmitJobResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/SubmitDestroyWorkspace", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) GetJob(ctx context.Context, in *GetJobRequest) (*GetJobResponse, error) {
+	out := new(GetJobResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/GetJob", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) WatchJob(ctx context.Context, in *WatchJobRequest) (DRPCGigoWS_WatchJobClient, error) {
+	stream, err := c.cc.NewStream(ctx, "/ws.GigoWS/WatchJob", drpcEncoding_File_gigo_ws_proto{})
+	if err != nil {
+		return nil, err
+	}
+	x := &drpcGigoWS_WatchJobClient{stream}
+	if err := x.MsgSend(in, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return nil, err
+	}
+	if err := x.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DRPCGigoWS_WatchJobClient interface {
+	drpc.Stream
+	Recv() (*WatchJobResponse, error)
+}
+
+type drpcGigoWS_WatchJobClient struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_WatchJobClient) Recv() (*WatchJobResponse, error) {
+	m := new(WatchJobResponse)
+	if err := x.MsgRecv(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (x *drpcGigoWS_WatchJobClient) RecvMsg(m *WatchJobResponse) error {
+	return x.MsgRecv(m, drpcEncoding_File_gigo_ws_proto{})
+}
+
+func (c *drpcGigoWSClient) CancelOperation(ctx context.Context, in *CancelOperationRequest) (*CancelOperationResponse, error) {
+	out := new(CancelOperationResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/CancelOperation", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) UpdateWorkspaceResources(ctx context.Context, in *UpdateWorkspaceResourcesRequest) (*UpdateWorkspaceResourcesResponse, error) {
+	out := new(UpdateWorkspaceResourcesResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/UpdateWorkspaceResources", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) PlanWorkspace(ctx context.Context, in *PlanWorkspaceRequest) (*PlanWorkspaceResponse, error) {
+	out := new(PlanWorkspaceResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/PlanWorkspace", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) ForceUnlock(ctx context.Context, in *ForceUnlockRequest) (*ForceUnlockResponse, error) {
+	out := new(ForceUnlockResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/ForceUnlock", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) GetWorkspaceDrift(ctx context.Context, in *GetWorkspaceDriftRequest) (*GetWorkspaceDriftResponse, error) {
+	out := new(GetWorkspaceDriftResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/GetWorkspaceDrift", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) UpgradeWorkspace(ctx context.Context, in *UpgradeWorkspaceRequest) (*UpgradeWorkspaceResponse, error) {
+	out := new(UpgradeWorkspaceResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/UpgradeWorkspace", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) GetOperationLogs(ctx context.Context, in *GetOperationLogsRequest) (*GetOperationLogsResponse, error) {
+	out := new(GetOperationLogsResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/GetOperationLogs", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) ListStatefileVersions(ctx context.Context, in *ListStatefileVersionsRequest) (*ListStatefileVersionsResponse, error) {
+	out := new(ListStatefileVersionsResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/ListStatefileVersions", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) GetStatefileVersion(ctx context.Context, in *GetStatefileVersionRequest) (*GetStatefileVersionResponse, error) {
+	out := new(GetStatefileVersionResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/GetStatefileVersion", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcGigoWSClient) RestoreStatefile(ctx context.Context, in *RestoreStatefileRequest) (*RestoreStatefileResponse, error) {
+	out := new(RestoreStatefileResponse)
+	err := c.cc.Invoke(ctx, "/ws.GigoWS/RestoreStatefile", drpcEncoding_File_gigo_ws_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type DRPCGigoWSServer interface {
 	Echo(context.Context, *EchoRequest) (*EchoResponse, error)
 	CreateWorkspace(context.Context, *CreateWorkspaceRequest) (*CreateWorkspaceResponse, error)
 	StartWorkspace(context.Context, *StartWorkspaceRequest) (*StartWorkspaceResponse, error)
 	StopWorkspace(context.Context, *StopWorkspaceRequest) (*StopWorkspaceResponse, error)
 	DestroyWorkspace(context.Context, *DestroyWorkspaceRequest) (*DestroyWorkspaceResponse, error)
+	CreateWorkspaceStream(*CreateWorkspaceRequest, DRPCGigoWS_CreateWorkspaceStreamStream) error
+	StartWorkspaceStream(*StartWorkspaceRequest, DRPCGigoWS_StartWorkspaceStreamStream) error
+	GetWorkspace(context.Context, *GetWorkspaceRequest) (*GetWorkspaceResponse, error)
+	ListWorkspaces(context.Context, *ListWorkspacesRequest) (*ListWorkspacesResponse, error)
+	SubmitCreateWorkspace(context.Context, *CreateWorkspaceRequest) (*SubmitJobResponse, error)
+	SubmitStartWorkspace(context.Context, *StartWorkspaceRequest) (*SubmitJobResponse, error)
+	SubmitStopWorkspace(context.Context, *StopWorkspaceRequest) (*SubmitJobResponse, error)
+	SubmitDestroyWorkspace(context.Context, *DestroyWorkspaceRequest) (*SubmitJobResponse, error)
+	GetJob(context.Context, *GetJobRequest) (*GetJobResponse, error)
+	WatchJob(*WatchJobRequest, DRPCGigoWS_WatchJobStream) error
+	CancelOperation(context.Context, *CancelOperationRequest) (*CancelOperationResponse, error)
+	UpdateWorkspaceResources(context.Context, *UpdateWorkspaceResourcesRequest) (*UpdateWorkspaceResourcesResponse, error)
+	PlanWorkspace(context.Context, *PlanWorkspaceRequest) (*PlanWorkspaceResponse, error)
+	ForceUnlock(context.Context, *ForceUnlockRequest) (*ForceUnlockResponse, error)
+	GetWorkspaceDrift(context.Context, *GetWorkspaceDriftRequest) (*GetWorkspaceDriftResponse, error)
+	UpgradeWorkspace(context.Context, *UpgradeWorkspaceRequest) (*UpgradeWorkspaceResponse, error)
+	GetOperationLogs(context.Context, *GetOperationLogsRequest) (*GetOperationLogsResponse, error)
+	ListStatefileVersions(context.Context, *ListStatefileVersionsRequest) (*ListStatefileVersionsResponse, error)
+	GetStatefileVersion(context.Context, *GetStatefileVersionRequest) (*GetStatefileVersionResponse, error)
+	RestoreStatefile(context.Context, *RestoreStatefileRequest) (*RestoreStatefileResponse, error)
 }
 
 type DRPCGigoWSUnimplementedServer struct{}
@@ -130,9 +431,89 @@ func (s *DRPCGigoWSUnimplementedServer) DestroyWorkspace(context.Context, *Destr
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCGigoWSUnimplementedServer) CreateWorkspaceStream(*CreateWorkspaceRequest, DRPCGigoWS_CreateWorkspaceStreamStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) StartWorkspaceStream(*StartWorkspaceRequest, DRPCGigoWS_StartWorkspaceStreamStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) GetWorkspace(context.Context, *GetWorkspaceRequest) (*GetWorkspaceResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) ListWorkspaces(context.Context, *ListWorkspacesRequest) (*ListWorkspacesResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) SubmitCreateWorkspace(context.Context, *CreateWorkspaceRequest) (*SubmitJobResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) SubmitStartWorkspace(context.Context, *StartWorkspaceRequest) (*SubmitJobResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) SubmitStopWorkspace(context.Context, *StopWorkspaceRequest) (*SubmitJobResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) SubmitDestroyWorkspace(context.Context, *DestroyWorkspaceRequest) (*SubmitJobResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) GetJob(context.Context, *GetJobRequest) (*GetJobResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) WatchJob(*WatchJobRequest, DRPCGigoWS_WatchJobStream) error {
+	return drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) CancelOperation(context.Context, *CancelOperationRequest) (*CancelOperationResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) UpdateWorkspaceResources(context.Context, *UpdateWorkspaceResourcesRequest) (*UpdateWorkspaceResourcesResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) PlanWorkspace(context.Context, *PlanWorkspaceRequest) (*PlanWorkspaceResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) ForceUnlock(context.Context, *ForceUnlockRequest) (*ForceUnlockResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) GetWorkspaceDrift(context.Context, *GetWorkspaceDriftRequest) (*GetWorkspaceDriftResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) UpgradeWorkspace(context.Context, *UpgradeWorkspaceRequest) (*UpgradeWorkspaceResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) GetOperationLogs(context.Context, *GetOperationLogsRequest) (*GetOperationLogsResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) ListStatefileVersions(context.Context, *ListStatefileVersionsRequest) (*ListStatefileVersionsResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) GetStatefileVersion(context.Context, *GetStatefileVersionRequest) (*GetStatefileVersionResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
+func (s *DRPCGigoWSUnimplementedServer) RestoreStatefile(context.Context, *RestoreStatefileRequest) (*RestoreStatefileResponse, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 type DRPCGigoWSDescription struct{}
 
-func (DRPCGigoWSDescription) NumMethods() int { return 5 }
+func (DRPCGigoWSDescription) NumMethods() int { return 25 }
 
 func (DRPCGigoWSDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
 	switch n {
@@ -140,47 +521,227 @@ func (DRPCGigoWSDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver
 		return "/ws.GigoWS/Echo", drpcEncoding_File_gigo_ws_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCGigoWSServer).
-					Echo(
+					Echo(
+						ctx,
+						in1.(*EchoRequest),
+					)
+			}, DRPCGigoWSServer.Echo, true
+	case 1:
+		return "/ws.GigoWS/CreateWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					CreateWorkspace(
+						ctx,
+						in1.(*CreateWorkspaceRequest),
+					)
+			}, DRPCGigoWSServer.CreateWorkspace, true
+	case 2:
+		return "/ws.GigoWS/StartWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					StartWorkspace(
+						ctx,
+						in1.(*StartWorkspaceRequest),
+					)
+			}, DRPCGigoWSServer.StartWorkspace, true
+	case 3:
+		return "/ws.GigoWS/StopWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					StopWorkspace(
+						ctx,
+						in1.(*StopWorkspaceRequest),
+					)
+			}, DRPCGigoWSServer.StopWorkspace, true
+	case 4:
+		return "/ws.GigoWS/DestroyWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					DestroyWorkspace(
+						ctx,
+						in1.(*DestroyWorkspaceRequest),
+					)
+			}, DRPCGigoWSServer.DestroyWorkspace, true
+	case 5:
+		return "/ws.GigoWS/CreateWorkspaceStream", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCGigoWSServer).
+					CreateWorkspaceStream(
+						in1.(*CreateWorkspaceRequest),
+						&drpcGigoWS_CreateWorkspaceStreamStream{in2.(drpc.Stream)},
+					)
+			}, DRPCGigoWSServer.CreateWorkspaceStream, true
+	case 6:
+		return "/ws.GigoWS/StartWorkspaceStream", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCGigoWSServer).
+					StartWorkspaceStream(
+						in1.(*StartWorkspaceRequest),
+						&drpcGigoWS_StartWorkspaceStreamStream{in2.(drpc.Stream)},
+					)
+			}, DRPCGigoWSServer.StartWorkspaceStream, true
+	case 7:
+		return "/ws.GigoWS/GetWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					GetWorkspace(
+						ctx,
+						in1.(*GetWorkspaceRequest),
+					)
+			}, DRPCGigoWSServer.GetWorkspace, true
+	case 8:
+		return "/ws.GigoWS/ListWorkspaces", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					ListWorkspaces(
+						ctx,
+						in1.(*ListWorkspacesRequest),
+					)
+			}, DRPCGigoWSServer.ListWorkspaces, true
+	case 9:
+		return "/ws.GigoWS/SubmitCreateWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					SubmitCreateWorkspace(
+						ctx,
+						in1.(*CreateWorkspaceRequest),
+					)
+			}, DRPCGigoWSServer.SubmitCreateWorkspace, true
+	case 10:
+		return "/ws.GigoWS/SubmitStartWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					SubmitStartWorkspace(
+						ctx,
+						in1.(*StartWorkspaceRequest),
+					)
+			}, DRPCGigoWSServer.SubmitStartWorkspace, true
+	case 11:
+		return "/ws.GigoWS/SubmitStopWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					SubmitStopWorkspace(
+						ctx,
+						in1.(*StopWorkspaceRequest),
+					)
+			}, DRPCGigoWSServer.SubmitStopWorkspace, true
+	case 12:
+		return "/ws.GigoWS/SubmitDestroyWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					SubmitDestroyWorkspace(
+						ctx,
+						in1.(*DestroyWorkspaceRequest),
+					)
+			}, DRPCGigoWSServer.SubmitDestroyWorkspace, true
+	case 13:
+		return "/ws.GigoWS/GetJob", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					GetJob(
+						ctx,
+						in1.(*GetJobRequest),
+					)
+			}, DRPCGigoWSServer.GetJob, true
+	case 14:
+		return "/ws.GigoWS/WatchJob", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return nil, srv.(DRPCGigoWSServer).
+					WatchJob(
+						in1.(*WatchJobRequest),
+						&drpcGigoWS_WatchJobStream{in2.(drpc.Stream)},
+					)
+			}, DRPCGigoWSServer.WatchJob, true
+	case 15:
+		return "/ws.GigoWS/CancelOperation", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					CancelOperation(
+						ctx,
+						in1.(*CancelOperationRequest),
+					)
+			}, DRPCGigoWSServer.CancelOperation, true
+	case 16:
+		return "/ws.GigoWS/UpdateWorkspaceResources", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					UpdateWorkspaceResources(
+						ctx,
+						in1.(*UpdateWorkspaceResourcesRequest),
+					)
+			}, DRPCGigoWSServer.UpdateWorkspaceResources, true
+	case 17:
+		return "/ws.GigoWS/PlanWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					PlanWorkspace(
+						ctx,
+						in1.(*PlanWorkspaceRequest),
+					)
+			}, DRPCGigoWSServer.PlanWorkspace, true
+	case 18:
+		return "/ws.GigoWS/ForceUnlock", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					ForceUnlock(
+						ctx,
+						in1.(*ForceUnlockRequest),
+					)
+			}, DRPCGigoWSServer.ForceUnlock, true
+	case 19:
+		return "/ws.GigoWS/GetWorkspaceDrift", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					GetWorkspaceDrift(
 						ctx,
-						in1.(*EchoRequest),
+						in1.(*GetWorkspaceDriftRequest),
 					)
-			}, DRPCGigoWSServer.Echo, true
-	case 1:
-		return "/ws.GigoWS/CreateWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			}, DRPCGigoWSServer.GetWorkspaceDrift, true
+	case 20:
+		return "/ws.GigoWS/UpgradeWorkspace", drpcEncoding_File_gigo_ws_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCGigoWSServer).
-					CreateWorkspace(
+					UpgradeWorkspace(
 						ctx,
-						in1.(*CreateWorkspaceRequest),
+						in1.(*UpgradeWorkspaceRequest),
 					)
-			}, DRPCGigoWSServer.CreateWorkspace, true
-	case 2:
-		return "/ws.GigoWS/StartWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			}, DRPCGigoWSServer.UpgradeWorkspace, true
+	case 21:
+		return "/ws.GigoWS/GetOperationLogs", drpcEncoding_File_gigo_ws_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCGigoWSServer).
-					StartWorkspace(
+					GetOperationLogs(
 						ctx,
-						in1.(*StartWorkspaceRequest),
+						in1.(*GetOperationLogsRequest),
 					)
-			}, DRPCGigoWSServer.StartWorkspace, true
-	case 3:
-		return "/ws.GigoWS/StopWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			}, DRPCGigoWSServer.GetOperationLogs, true
+	case 22:
+		return "/ws.GigoWS/ListStatefileVersions", drpcEncoding_File_gigo_ws_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCGigoWSServer).
-					StopWorkspace(
+					ListStatefileVersions(
 						ctx,
-						in1.(*StopWorkspaceRequest),
+						in1.(*ListStatefileVersionsRequest),
 					)
-			}, DRPCGigoWSServer.StopWorkspace, true
-	case 4:
-		return "/ws.GigoWS/DestroyWorkspace", drpcEncoding_File_gigo_ws_proto{},
+			}, DRPCGigoWSServer.ListStatefileVersions, true
+	case 23:
+		return "/ws.GigoWS/GetStatefileVersion", drpcEncoding_File_gigo_ws_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCGigoWSServer).
-					DestroyWorkspace(
+					GetStatefileVersion(
 						ctx,
-						in1.(*DestroyWorkspaceRequest),
+						in1.(*GetStatefileVersionRequest),
 					)
-			}, DRPCGigoWSServer.DestroyWorkspace, true
+			}, DRPCGigoWSServer.GetStatefileVersion, true
+	case 24:
+		return "/ws.GigoWS/RestoreStatefile", drpcEncoding_File_gigo_ws_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGigoWSServer).
+					RestoreStatefile(
+						ctx,
+						in1.(*RestoreStatefileRequest),
+					)
+			}, DRPCGigoWSServer.RestoreStatefile, true
 	default:
 		return "", nil, nil, nil, false
 	}
@@ -269,3 +830,314 @@ func (x *drpcGigoWS_DestroyWorkspaceStream) SendAndClose(m *DestroyWorkspaceResp
 	}
 	return x.CloseSend()
 }
+
+type DRPCGigoWS_CreateWorkspaceStreamStream interface {
+	drpc.Stream
+	Send(*CreateWorkspaceStreamResponse) error
+}
+
+type drpcGigoWS_CreateWorkspaceStreamStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_CreateWorkspaceStreamStream) Send(m *CreateWorkspaceStreamResponse) error {
+	return x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{})
+}
+
+type DRPCGigoWS_StartWorkspaceStreamStream interface {
+	drpc.Stream
+	Send(*StartWorkspaceStreamResponse) error
+}
+
+type drpcGigoWS_StartWorkspaceStreamStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_StartWorkspaceStreamStream) Send(m *StartWorkspaceStreamResponse) error {
+	return x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{})
+}
+
+type DRPCGigoWS_GetWorkspaceStream interface {
+	drpc.Stream
+	SendAndClose(*GetWorkspaceResponse) error
+}
+
+type drpcGigoWS_GetWorkspaceStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_GetWorkspaceStream) SendAndClose(m *GetWorkspaceResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_ListWorkspacesStream interface {
+	drpc.Stream
+	SendAndClose(*ListWorkspacesResponse) error
+}
+
+type drpcGigoWS_ListWorkspacesStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_ListWorkspacesStream) SendAndClose(m *ListWorkspacesResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_SubmitCreateWorkspaceStream interface {
+	drpc.Stream
+	SendAndClose(*SubmitJobResponse) error
+}
+
+type drpcGigoWS_SubmitCreateWorkspaceStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_SubmitCreateWorkspaceStream) SendAndClose(m *SubmitJobResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_SubmitStartWorkspaceStream interface {
+	drpc.Stream
+	SendAndClose(*SubmitJobResponse) error
+}
+
+type drpcGigoWS_SubmitStartWorkspaceStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_SubmitStartWorkspaceStream) SendAndClose(m *SubmitJobResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_SubmitStopWorkspaceStream interface {
+	drpc.Stream
+	SendAndClose(*SubmitJobResponse) error
+}
+
+type drpcGigoWS_SubmitStopWorkspaceStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_SubmitStopWorkspaceStream) SendAndClose(m *SubmitJobResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_SubmitDestroyWorkspaceStream interface {
+	drpc.Stream
+	SendAndClose(*SubmitJobResponse) error
+}
+
+type drpcGigoWS_SubmitDestroyWorkspaceStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_SubmitDestroyWorkspaceStream) SendAndClose(m *SubmitJobResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_GetJobStream interface {
+	drpc.Stream
+	SendAndClose(*GetJobResponse) error
+}
+
+type drpcGigoWS_GetJobStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_GetJobStream) SendAndClose(m *GetJobResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_WatchJobStream interface {
+	drpc.Stream
+	Send(*WatchJobResponse) error
+}
+
+type drpcGigoWS_WatchJobStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_WatchJobStream) Send(m *WatchJobResponse) error {
+	return x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{})
+}
+
+type DRPCGigoWS_CancelOperationStream interface {
+	drpc.Stream
+	SendAndClose(*CancelOperationResponse) error
+}
+
+type drpcGigoWS_CancelOperationStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_CancelOperationStream) SendAndClose(m *CancelOperationResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_UpdateWorkspaceResourcesStream interface {
+	drpc.Stream
+	SendAndClose(*UpdateWorkspaceResourcesResponse) error
+}
+
+type drpcGigoWS_UpdateWorkspaceResourcesStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_UpdateWorkspaceResourcesStream) SendAndClose(m *UpdateWorkspaceResourcesResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_PlanWorkspaceStream interface {
+	drpc.Stream
+	SendAndClose(*PlanWorkspaceResponse) error
+}
+
+type drpcGigoWS_PlanWorkspaceStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_PlanWorkspaceStream) SendAndClose(m *PlanWorkspaceResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_ForceUnlockStream interface {
+	drpc.Stream
+	SendAndClose(*ForceUnlockResponse) error
+}
+
+type drpcGigoWS_ForceUnlockStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_ForceUnlockStream) SendAndClose(m *ForceUnlockResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_GetWorkspaceDriftStream interface {
+	drpc.Stream
+	SendAndClose(*GetWorkspaceDriftResponse) error
+}
+
+type drpcGigoWS_GetWorkspaceDriftStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_GetWorkspaceDriftStream) SendAndClose(m *GetWorkspaceDriftResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_UpgradeWorkspaceStream interface {
+	drpc.Stream
+	SendAndClose(*UpgradeWorkspaceResponse) error
+}
+
+type drpcGigoWS_UpgradeWorkspaceStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_UpgradeWorkspaceStream) SendAndClose(m *UpgradeWorkspaceResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_GetOperationLogsStream interface {
+	drpc.Stream
+	SendAndClose(*GetOperationLogsResponse) error
+}
+
+type drpcGigoWS_GetOperationLogsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_GetOperationLogsStream) SendAndClose(m *GetOperationLogsResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_ListStatefileVersionsStream interface {
+	drpc.Stream
+	SendAndClose(*ListStatefileVersionsResponse) error
+}
+
+type drpcGigoWS_ListStatefileVersionsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_ListStatefileVersionsStream) SendAndClose(m *ListStatefileVersionsResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_GetStatefileVersionStream interface {
+	drpc.Stream
+	SendAndClose(*GetStatefileVersionResponse) error
+}
+
+type drpcGigoWS_GetStatefileVersionStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_GetStatefileVersionStream) SendAndClose(m *GetStatefileVersionResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCGigoWS_RestoreStatefileStream interface {
+	drpc.Stream
+	SendAndClose(*RestoreStatefileResponse) error
+}
+
+type drpcGigoWS_RestoreStatefileStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGigoWS_RestoreStatefileStream) SendAndClose(m *RestoreStatefileResponse) error {
+	if err := x.MsgSend(m, drpcEncoding_File_gigo_ws_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
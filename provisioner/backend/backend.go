@@ -1,6 +1,48 @@
 package backend
 
-import "io"
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrLockIDMismatch is returned when a force unlock targets a lock other than the current lock
+var ErrLockIDMismatch = errors.New("lock id does not match the current state lock")
+
+// ErrStatefileVersionNotFound is returned when a restore targets a statefile version that does not exist
+var ErrStatefileVersionNotFound = errors.New("statefile version not found")
+
+// StatefileInfo
+//
+//	Metadata of a statefile stored in a provisioner backend
+type StatefileInfo struct {
+	BucketPath   string
+	LastModified time.Time
+}
+
+// StatefileVersion
+//
+//	Metadata of a version of a statefile kept in
+//	the history of a provisioner backend
+type StatefileVersion struct {
+	ID           string
+	LastModified time.Time
+	Size         int64
+}
+
+// LockInfo
+//
+//	Metadata of a terraform state lock as written by
+//	terraform when it acquires the lock for an operation
+type LockInfo struct {
+	ID        string    `json:"ID"`
+	Operation string    `json:"Operation"`
+	Info      string    `json:"Info"`
+	Who       string    `json:"Who"`
+	Version   string    `json:"Version"`
+	Created   time.Time `json:"Created"`
+	Path      string    `json:"Path"`
+}
 
 type ProvisionerBackend interface {
 	// String
@@ -34,4 +76,105 @@ type ProvisionerBackend interface {
 	//  Removes the statefile and the backup statefile (if it exists) from
 	//  the provisioner backend at the passed bucket path
 	RemoveStatefile(bucketPath string) error
+
+	// List
+	//
+	//  Lists the statefiles stored in the provisioner backend
+	//  under the passed prefix. Backup and lock files are excluded.
+	List(prefix string) ([]StatefileInfo, error)
+
+	// GetLock
+	//
+	//  Returns the state lock held for the statefile at the passed
+	//  bucket path or nil if the statefile is not locked
+	GetLock(bucketPath string) (*LockInfo, error)
+
+	// ForceUnlock
+	//
+	//  Removes the state lock held for the statefile at the passed bucket
+	//  path. If a lock id is passed the lock is only removed if it is the
+	//  current lock. No-op if the statefile is not locked.
+	ForceUnlock(bucketPath string, lockID string) error
+
+	// ListStatefileVersions
+	//
+	//  Lists the versions of the statefile at the passed bucket
+	//  path that can be restored ordered from newest to oldest
+	ListStatefileVersions(bucketPath string) ([]StatefileVersion, error)
+
+	// GetStatefileVersion
+	//
+	//  Returns a version of the statefile at the passed bucket
+	//  path or nil if the version does not exist
+	GetStatefileVersion(bucketPath string, versionID string) (io.ReadCloser, error)
+
+	// RestoreStatefile
+	//
+	//  Replaces the statefile at the passed bucket path with a previous
+	//  version. ErrStatefileVersionNotFound is returned if the version
+	//  does not exist.
+	RestoreStatefile(bucketPath string, versionID string) error
+}
+
+// WorkspaceProvisionerBackend
+//
+//	Implemented by provisioner backends that store each statefile in its
+//	own terraform workspace. Terraform refuses to initialize a module
+//	without input when the selected workspace does not exist yet.
+type WorkspaceProvisionerBackend interface {
+	ProvisionerBackend
+
+	// CreateWorkspace
+	//
+	//  Creates the terraform workspace of the statefile at the
+	//  passed bucket path. No-op if the workspace exists.
+	CreateWorkspace(bucketPath string) error
+}
+
+// HistoryProvisionerBackend
+//
+//	Implemented by provisioner backends whose storage does not keep the
+//	versions of a statefile. The provisioner snapshots the statefile
+//	before it is handed to terraform so that the state that existed
+//	before each operation can be restored.
+type HistoryProvisionerBackend interface {
+	ProvisionerBackend
+
+	// SnapshotStatefile
+	//
+	//  Copies the statefile at the passed bucket path into its history
+	//  unless it matches the newest version. The oldest versions are
+	//  removed once more than the retention are kept.
+	SnapshotStatefile(bucketPath string, retention int) error
+}
+
+// MigratingProvisionerBackend
+//
+//	Implemented by provisioner backends that take over the statefiles of
+//	a previous backend. The backend configuration that was rendered into
+//	the stored modules by the previous backend is replaced when they are
+//	prepped so that terraform operates on the statefiles of this backend.
+type MigratingProvisionerBackend interface {
+	ProvisionerBackend
+
+	// PreviousTerraform
+	//
+	//  Returns the backend configuration that the previous backend
+	//  rendered for the statefile at the passed bucket path
+	PreviousTerraform(bucketPath string) string
+}
+
+// VersionedProvisionerBackend
+//
+//	Implemented by provisioner backends whose storage keeps the versions
+//	of a statefile itself. The provisioner prunes the versions before the
+//	statefile is handed to terraform so that the retention is enforced.
+type VersionedProvisionerBackend interface {
+	ProvisionerBackend
+
+	// PruneStatefileVersions
+	//
+	//  Removes the oldest versions of the statefile at the passed
+	//  bucket path once more than the retention are kept
+	PruneStatefileVersions(bucketPath string, retention int) error
 }
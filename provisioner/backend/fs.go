@@ -1,11 +1,14 @@
 package backend
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/gage-technologies/gigo-lib/config"
 	"github.com/gage-technologies/gigo-lib/storage"
 	"io"
+	"os"
 	"path/filepath"
+	"strings"
 )
 
 const provisionerBackendFSTemplate = `backend "local" {
@@ -19,6 +22,7 @@ const provisionerBackendFSTemplate = `backend "local" {
 type ProvisionerBackendFS struct {
 	config.StorageFSConfig
 	storageEngine storage.Storage
+	history       statefileHistory
 }
 
 // NewProvisionerBackendFS
@@ -34,6 +38,7 @@ func NewProvisionerBackendFS(c config.StorageFSConfig) (ProvisionerBackend, erro
 	return &ProvisionerBackendFS{
 		StorageFSConfig: c,
 		storageEngine:   storageEngine,
+		history:         statefileHistory{storageEngine: storageEngine},
 	}, nil
 }
 
@@ -63,7 +68,7 @@ func (b *ProvisionerBackendFS) String() string {
 func (b *ProvisionerBackendFS) ToTerraform(bucketPath string) (string, []string) {
 	return fmt.Sprintf(
 		provisionerBackendFSTemplate,
-		filepath.Join(b.Root, "states", bucketPath),
+		b.statePath(bucketPath),
 	), []string{}
 }
 
@@ -77,8 +82,9 @@ func (b *ProvisionerBackendFS) GetStatefile(bucketPath string) (io.ReadCloser, e
 
 // RemoveStatefile
 //
-//	Removes the statefile and the backup statefile (if it exists) from
-//	the provisioner backend at the passed bucket path
+//	Removes the statefile, the backup statefile (if it exists) and the
+//	history of the statefile from the provisioner backend at the passed
+//	bucket path
 func (b *ProvisionerBackendFS) RemoveStatefile(bucketPath string) error {
 	// delete statefile
 	err := b.storageEngine.DeleteFile(bucketPath)
@@ -100,5 +106,148 @@ func (b *ProvisionerBackendFS) RemoveStatefile(bucketPath string) error {
 		}
 	}
 
+	err = b.history.Remove(bucketPath)
+	if err != nil {
+		return fmt.Errorf("failed to delete statefile history: %v", err)
+	}
+
+	return nil
+}
+
+// List
+//
+//	Lists the statefiles stored in the provisioner backend
+//	under the passed prefix. Backup and lock files are excluded.
+func (b *ProvisionerBackendFS) List(prefix string) ([]StatefileInfo, error) {
+	// list all files under the prefix
+	files, err := b.storageEngine.ListDir(prefix, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefiles: %v", err)
+	}
+
+	statefiles := make([]StatefileInfo, 0, len(files))
+	for _, f := range files {
+		// skip backups and hidden lock files written by the local backend
+		if strings.HasSuffix(f, ".backup") || strings.HasPrefix(filepath.Base(f), ".") {
+			continue
+		}
+
+		// stat the file to retrieve the modification time
+		stat, err := os.Stat(filepath.Join(b.Root, f))
+		if err != nil {
+			// skip files that were removed since the listing
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to stat statefile %q: %v", f, err)
+		}
+
+		statefiles = append(statefiles, StatefileInfo{
+			BucketPath:   f,
+			LastModified: stat.ModTime(),
+		})
+	}
+
+	return statefiles, nil
+}
+
+// statePath
+//
+//	Returns the local path of the statefile that terraform
+//	writes for the passed bucket path
+func (b *ProvisionerBackendFS) statePath(bucketPath string) string {
+	return filepath.Join(b.Root, "states", bucketPath)
+}
+
+// lockInfoPath
+//
+//	Returns the path of the lock info file that the terraform local
+//	backend writes beside the statefile while the state is locked
+func (b *ProvisionerBackendFS) lockInfoPath(bucketPath string) string {
+	statePath := b.statePath(bucketPath)
+	return filepath.Join(filepath.Dir(statePath), fmt.Sprintf(".%s.lock.info", filepath.Base(statePath)))
+}
+
+// GetLock
+//
+//	Returns the state lock held for the statefile at the passed
+//	bucket path or nil if the statefile is not locked. The local
+//	backend leaves its lock info behind if terraform exits without
+//	releasing the lock which is reported as a held lock.
+func (b *ProvisionerBackendFS) GetLock(bucketPath string) (*LockInfo, error) {
+	buf, err := os.ReadFile(b.lockInfoPath(bucketPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read lock info: %v", err)
+	}
+
+	var info LockInfo
+	err = json.Unmarshal(buf, &info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode lock info: %v", err)
+	}
+
+	return &info, nil
+}
+
+// ForceUnlock
+//
+//	Removes the state lock held for the statefile at the passed bucket
+//	path. If a lock id is passed the lock is only removed if it is the
+//	current lock. No-op if the statefile is not locked.
+func (b *ProvisionerBackendFS) ForceUnlock(bucketPath string, lockID string) error {
+	info, err := b.GetLock(bucketPath)
+	if err != nil {
+		return fmt.Errorf("failed to get lock: %v", err)
+	}
+	if info == nil {
+		return nil
+	}
+
+	if lockID != "" && info.ID != lockID {
+		return ErrLockIDMismatch
+	}
+
+	err = os.Remove(b.lockInfoPath(bucketPath))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove lock info: %v", err)
+	}
+
 	return nil
 }
+
+// ListStatefileVersions
+//
+//	Lists the versions of the statefile at the passed bucket
+//	path that can be restored ordered from newest to oldest
+func (b *ProvisionerBackendFS) ListStatefileVersions(bucketPath string) ([]StatefileVersion, error) {
+	return b.history.List(bucketPath)
+}
+
+// GetStatefileVersion
+//
+//	Returns a version of the statefile at the passed bucket
+//	path or nil if the version does not exist
+func (b *ProvisionerBackendFS) GetStatefileVersion(bucketPath string, versionID string) (io.ReadCloser, error) {
+	return b.history.Get(bucketPath, versionID)
+}
+
+// RestoreStatefile
+//
+//	Replaces the statefile at the passed bucket path with a previous
+//	version. ErrStatefileVersionNotFound is returned if the version
+//	does not exist.
+func (b *ProvisionerBackendFS) RestoreStatefile(bucketPath string, versionID string) error {
+	return b.history.Restore(bucketPath, versionID)
+}
+
+// SnapshotStatefile
+//
+//	Copies the statefile at the passed bucket path into the history
+//	directory unless it matches the newest version. The oldest versions
+//	are removed once more than the retention are kept.
+func (b *ProvisionerBackendFS) SnapshotStatefile(bucketPath string, retention int) error {
+	return b.history.Snapshot(bucketPath, retention)
+}
@@ -0,0 +1,108 @@
+package backend
+
+import (
+	"fmt"
+	config2 "gigo-ws/config"
+	"gigo-ws/encryption"
+	"github.com/gage-technologies/gigo-lib/config"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProvisionerBackendEncryptedFS
+//
+//	Filesystem based implementation of the Terraform remote backend
+//	that encrypts the statefiles. The terraform local backend cannot
+//	encrypt the statefiles so they are served by the built in state
+//	server from the filesystem through an encrypting storage engine.
+type ProvisionerBackendEncryptedFS struct {
+	*ProvisionerBackendHTTP
+	// local backend that rendered the modules before encryption was enabled
+	local *ProvisionerBackendFS
+}
+
+// NewProvisionerBackendEncryptedFS
+//
+//	Creates a new ProvisionerBackendEncryptedFS from as FS storage
+//	configuration and begins serving the states. The statefiles of
+//	the local backend are moved into the state server and the files
+//	written before encryption was enabled are encrypted in place so
+//	no state is left in plaintext. No operation may run while the
+//	backend is created.
+func NewProvisionerBackendEncryptedFS(c config.StorageFSConfig, httpConfig config2.HTTPBackendConfig, keyring *encryption.Keyring) (ProvisionerBackend, error) {
+	b, err := NewProvisionerBackendFS(c)
+	if err != nil {
+		return nil, err
+	}
+	local := b.(*ProvisionerBackendFS)
+	encryptedStorage := encryption.NewStorage(local.storageEngine, keyring)
+
+	err = local.migrateStatefiles(encryptedStorage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate local statefiles: %v", err)
+	}
+
+	for _, prefix := range []string{httpStatePrefix, StatefileHistoryDir} {
+		_, err = encryptedStorage.Seal(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt %s: %v", prefix, err)
+		}
+	}
+
+	httpBackend, err := NewProvisionerBackendHTTP(httpConfig, encryptedStorage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProvisionerBackendEncryptedFS{
+		ProvisionerBackendHTTP: httpBackend.(*ProvisionerBackendHTTP),
+		local:                  local,
+	}, nil
+}
+
+// PreviousTerraform
+//
+//	Returns the local backend configuration that was rendered into
+//	the modules for the statefile at the passed bucket path before
+//	encryption was enabled
+func (b *ProvisionerBackendEncryptedFS) PreviousTerraform(bucketPath string) string {
+	s, _ := b.local.ToTerraform(bucketPath)
+	return s
+}
+
+// migrateStatefiles
+//
+//	Writes the statefiles of the terraform local backend to the passed
+//	storage engine at their bucket paths and removes the local files
+//	including the backups and lock info that terraform left beside them
+func (b *ProvisionerBackendFS) migrateStatefiles(storageEngine *encryption.Storage) error {
+	dir := filepath.Dir(b.statePath(httpStatePrefix + "0"))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read local statefiles: %v", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasSuffix(name, ".backup") || strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		buf, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("failed to read local statefile %s: %v", name, err)
+		}
+
+		// terraform wrote the local statefile so it replaces any copy at the bucket path
+		err = storageEngine.CreateFile(httpStatePrefix+name, buf)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.RemoveAll(dir)
+}
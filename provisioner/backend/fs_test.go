@@ -1,8 +1,20 @@
 package backend
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	config2 "gigo-ws/config"
+	"gigo-ws/encryption"
 	"github.com/gage-technologies/gigo-lib/config"
 	"github.com/gage-technologies/gigo-lib/utils"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -58,3 +70,313 @@ func TestProvisionerBackendFS_ToTerraform(t *testing.T) {
 		t.Fatalf("invalid hash: %s != 6f4ca85336b825f6d6a7f47a3a4ec98ba8a3040c2d2adbecc884d0ac0b936200\n%s", h, o)
 	}
 }
+
+func TestProvisionerBackendFS_List(t *testing.T) {
+	_, b, _, _ := runtime.Caller(0)
+	basepath := strings.Replace(filepath.Dir(b), "/provisioner/backend", "", -1)
+	provisioner, err := NewProvisionerBackendFS(config.StorageFSConfig{
+		Root: basepath + "/test_data/statefiles",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statefiles, err := provisioner.List("states")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := make([]string, 0, len(statefiles))
+	for _, s := range statefiles {
+		if s.LastModified.IsZero() {
+			t.Fatalf("missing last modified time for %s", s.BucketPath)
+		}
+		paths = append(paths, s.BucketPath)
+	}
+	sort.Strings(paths)
+
+	if !reflect.DeepEqual(paths, []string{"states/420", "states/421"}) {
+		t.Fatalf("unexpected statefiles: %v", paths)
+	}
+
+	// ensure that a missing prefix returns no statefiles
+	statefiles, err = provisioner.List("missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(statefiles) != 0 {
+		t.Fatalf("expected no statefiles, got %v", statefiles)
+	}
+}
+
+func TestProvisionerBackendFS_ForceUnlock(t *testing.T) {
+	root := t.TempDir()
+	provisioner, err := NewProvisionerBackendFS(config.StorageFSConfig{
+		Root: root,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an unlocked statefile has no lock
+	lock, err := provisioner.GetLock("states/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock != nil {
+		t.Fatalf("expected no lock, got %+v", lock)
+	}
+
+	// write the lock info left behind by a terraform run that died
+	err = os.MkdirAll(filepath.Join(root, "states", "states"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(
+		filepath.Join(root, "states", "states", ".1.lock.info"),
+		[]byte(`{"ID":"6c4ba3f1-7e41-7e2b-4c5d-d9b1c0e1c2a3","Path":"states/1","Operation":"OperationTypeApply","Who":"root@gigo-ws-0","Version":"1.3.7","Created":"2023-01-31T18:00:00.000000Z","Info":""}`),
+		0600,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err = provisioner.GetLock("states/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock == nil || lock.ID != "6c4ba3f1-7e41-7e2b-4c5d-d9b1c0e1c2a3" || lock.Operation != "OperationTypeApply" {
+		t.Fatalf("unexpected lock: %+v", lock)
+	}
+
+	// the lock is only removed when the id matches
+	err = provisioner.ForceUnlock("states/1", "wrong")
+	if !errors.Is(err, ErrLockIDMismatch) {
+		t.Fatalf("expected ErrLockIDMismatch, got %v", err)
+	}
+
+	err = provisioner.ForceUnlock("states/1", lock.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err = provisioner.GetLock("states/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock != nil {
+		t.Fatalf("expected lock to be removed, got %+v", lock)
+	}
+
+	// unlocking an unlocked statefile is a no-op
+	err = provisioner.ForceUnlock("states/1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProvisionerBackendFS_History(t *testing.T) {
+	root := t.TempDir()
+	b, err := NewProvisionerBackendFS(config.StorageFSConfig{
+		Root: root,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	provisioner := b.(*ProvisionerBackendFS)
+
+	writeState := func(state string) {
+		err := os.MkdirAll(filepath.Join(root, "states"), 0700)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(root, "states", "1"), []byte(state), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// snapshotting a missing statefile is a no-op
+	err = provisioner.SnapshotStatefile("states/1", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, state := range []string{`{"serial": 1}`, `{"serial": 2}`, `{"serial": 2}`, `{"serial": 3}`} {
+		writeState(state)
+		err = provisioner.SnapshotStatefile("states/1", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// unchanged states are skipped and only the newest versions are retained
+	versions, err := provisioner.ListStatefileVersions("states/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("expected 2 versions, got %+v", versions)
+	}
+	if versions[0].ID <= versions[1].ID || versions[0].Size != int64(len(`{"serial": 3}`)) {
+		t.Fatalf("unexpected versions: %+v", versions)
+	}
+
+	buf, err := provisioner.GetStatefileVersion("states/1", versions[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := io.ReadAll(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = buf.Close()
+	if string(state) != `{"serial": 2}` {
+		t.Fatalf("unexpected statefile version: %s", string(state))
+	}
+
+	err = provisioner.RestoreStatefile("states/1", versions[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err = provisioner.GetStatefile("states/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err = io.ReadAll(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = buf.Close()
+	if string(state) != `{"serial": 2}` {
+		t.Fatalf("statefile was not restored: %s", string(state))
+	}
+
+	// versions outside of the history are rejected
+	err = provisioner.RestoreStatefile("states/1", "1")
+	if !errors.Is(err, ErrStatefileVersionNotFound) {
+		t.Fatalf("expected ErrStatefileVersionNotFound, got %v", err)
+	}
+	_, err = provisioner.GetStatefileVersion("states/1", "../../states/1")
+	if err == nil {
+		t.Fatal("expected invalid version id to be rejected")
+	}
+
+	// the history is removed with the statefile
+	err = provisioner.RemoveStatefile("states/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err = provisioner.ListStatefileVersions("states/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 0 {
+		t.Fatalf("expected history to be removed, got %+v", versions)
+	}
+}
+
+func TestProvisionerBackendEncryptedFS(t *testing.T) {
+	keyring, err := encryption.NewKeyring(config2.EncryptionConfig{
+		Keys: []config2.EncryptionKeyConfig{{ID: "key-1", Key: base64.StdEncoding.EncodeToString(make([]byte, 32))}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// write a statefile and its backup with the terraform local backend
+	root := t.TempDir()
+	state := `{"serial": 1, "token": "agent-token"}`
+	localDir := filepath.Join(root, "states", "states")
+	err = os.MkdirAll(localDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"1", "1.backup"} {
+		err = os.WriteFile(filepath.Join(localDir, name), []byte(state), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := NewProvisionerBackendEncryptedFS(config.StorageFSConfig{
+		Root: root,
+	}, config2.HTTPBackendConfig{Port: -1}, keyring)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provisioner := b.(*ProvisionerBackendEncryptedFS)
+	defer provisioner.Close()
+
+	// stored modules are moved off of the local backend
+	local, _ := (&ProvisionerBackendFS{StorageFSConfig: config.StorageFSConfig{Root: root}}).ToTerraform("states/1")
+	if provisioner.PreviousTerraform("states/1") != local {
+		t.Fatalf("unexpected previous backend configuration:\n%s", provisioner.PreviousTerraform("states/1"))
+	}
+
+	// the local statefiles are moved into the state server
+	_, err = os.Stat(localDir)
+	if !os.IsNotExist(err) {
+		t.Fatalf("expected local statefiles to be removed, got %v", err)
+	}
+
+	// the statefile is encrypted on disk and decrypted when read
+	readState := func(expected string) {
+		t.Helper()
+		raw, err := os.ReadFile(filepath.Join(root, "states", "1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !encryption.IsEncrypted(raw) || strings.Contains(string(raw), "agent-token") {
+			t.Fatal("expected statefile to be encrypted")
+		}
+
+		f, err := provisioner.GetStatefile("states/1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f == nil {
+			t.Fatal("expected statefile to exist")
+		}
+		buf, err := io.ReadAll(f)
+		_ = f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(buf) != expected {
+			t.Fatalf("expected %s, got %s", expected, string(buf))
+		}
+	}
+	readState(state)
+
+	// states written by terraform are encrypted
+	_, envs := provisioner.ToTerraform("states/1")
+	updated := `{"serial": 2, "token": "agent-token"}`
+	status, _ := testHTTPRequest(t, "POST", fmt.Sprintf("http://127.0.0.1:%d/state/1", provisioner.Port), envs, []byte(updated))
+	if status != 200 {
+		t.Fatalf("expected state to be written, got %d", status)
+	}
+	readState(updated)
+
+	// the statefile versions are encrypted
+	err = provisioner.SnapshotStatefile("states/1", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err := provisioner.ListStatefileVersions("states/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 {
+		t.Fatalf("unexpected versions: %+v", versions)
+	}
+	raw, err := os.ReadFile(filepath.Join(root, StatefileHistoryDir, "states", "1", versions[0].ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !encryption.IsEncrypted(raw) || strings.Contains(string(raw), "agent-token") {
+		t.Fatal("expected statefile version to be encrypted")
+	}
+}
@@ -0,0 +1,235 @@
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/gage-technologies/gigo-lib/storage"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatefileHistoryDir directory of the storage engine that
+// the versions of each statefile are copied to
+const StatefileHistoryDir = "state-history"
+
+// newStatefileVersionID
+//
+//	Returns the id of a new statefile version. Versions are named after
+//	the time of the snapshot padded so that the ids sort chronologically.
+func newStatefileVersionID() string {
+	return fmt.Sprintf("%020d", time.Now().UnixNano())
+}
+
+// parseStatefileVersionID
+//
+//	Returns the time that the statefile version was created at. Ids
+//	that were not created by newStatefileVersionID are rejected so
+//	that a version id can never be used to escape the history.
+func parseStatefileVersionID(versionID string) (time.Time, error) {
+	ns, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil || ns < 0 {
+		return time.Time{}, fmt.Errorf("invalid statefile version id: %q", versionID)
+	}
+	return time.Unix(0, ns), nil
+}
+
+// statefileVersionPath
+//
+//	Returns the path of a version of the statefile at the passed bucket path
+func statefileVersionPath(bucketPath string, versionID string) string {
+	return path.Join(StatefileHistoryDir, bucketPath, versionID)
+}
+
+// statefileHistory
+//
+//	Copy-on-write history of the statefiles stored in a storage
+//	engine. Each version is written to its own file in the history
+//	directory of the statefile.
+type statefileHistory struct {
+	storageEngine storage.Storage
+}
+
+// readFile
+//
+//	Reads a file from a storage engine returning nil if it does not exist
+func readFile(storageEngine storage.Storage, path string) ([]byte, error) {
+	f, err := storageEngine.GetFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, nil
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// versionIDs
+//
+//	Returns the ids of the versions of the statefile at the
+//	passed bucket path ordered from newest to oldest
+func (h statefileHistory) versionIDs(bucketPath string) ([]string, error) {
+	files, err := h.storageEngine.ListDir(path.Join(StatefileHistoryDir, bucketPath), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefile history: %v", err)
+	}
+
+	ids := make([]string, 0, len(files))
+	for _, f := range files {
+		// skip the history of statefiles nested under this one
+		if strings.HasSuffix(f, "/") {
+			continue
+		}
+
+		id := path.Base(f)
+		_, err := parseStatefileVersionID(id)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	return ids, nil
+}
+
+// List
+//
+//	Lists the versions of the statefile at the passed bucket path
+//	ordered from newest to oldest
+func (h statefileHistory) List(bucketPath string) ([]StatefileVersion, error) {
+	ids, err := h.versionIDs(bucketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]StatefileVersion, 0, len(ids))
+	for _, id := range ids {
+		// the storage engine does not expose file sizes so the version is read
+		buf, err := readFile(h.storageEngine, statefileVersionPath(bucketPath, id))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read statefile version %s: %v", id, err)
+		}
+		if buf == nil {
+			continue
+		}
+
+		created, _ := parseStatefileVersionID(id)
+		versions = append(versions, StatefileVersion{
+			ID:           id,
+			LastModified: created,
+			Size:         int64(len(buf)),
+		})
+	}
+
+	return versions, nil
+}
+
+// Get
+//
+//	Returns a version of the statefile at the passed bucket
+//	path or nil if the version does not exist
+func (h statefileHistory) Get(bucketPath string, versionID string) (io.ReadCloser, error) {
+	_, err := parseStatefileVersionID(versionID)
+	if err != nil {
+		return nil, err
+	}
+	return h.storageEngine.GetFile(statefileVersionPath(bucketPath, versionID))
+}
+
+// Restore
+//
+//	Overwrites the statefile at the passed bucket path with a
+//	previous version. ErrStatefileVersionNotFound is returned
+//	if the version does not exist.
+func (h statefileHistory) Restore(bucketPath string, versionID string) error {
+	_, err := parseStatefileVersionID(versionID)
+	if err != nil {
+		return err
+	}
+
+	buf, err := readFile(h.storageEngine, statefileVersionPath(bucketPath, versionID))
+	if err != nil {
+		return fmt.Errorf("failed to read statefile version: %v", err)
+	}
+	if buf == nil {
+		return ErrStatefileVersionNotFound
+	}
+
+	err = h.storageEngine.CreateFile(bucketPath, buf)
+	if err != nil {
+		return fmt.Errorf("failed to write statefile: %v", err)
+	}
+
+	return nil
+}
+
+// Snapshot
+//
+//	Copies the statefile at the passed bucket path into its history
+//	unless it matches the newest version and removes the oldest
+//	versions once more than the retention are kept
+func (h statefileHistory) Snapshot(bucketPath string, retention int) error {
+	state, err := readFile(h.storageEngine, bucketPath)
+	if err != nil {
+		return fmt.Errorf("failed to read statefile: %v", err)
+	}
+	if state == nil {
+		return nil
+	}
+
+	ids, err := h.versionIDs(bucketPath)
+	if err != nil {
+		return err
+	}
+
+	// skip the copy if the state has not changed since the last snapshot
+	if len(ids) > 0 {
+		newest, err := readFile(h.storageEngine, statefileVersionPath(bucketPath, ids[0]))
+		if err != nil {
+			return fmt.Errorf("failed to read statefile version: %v", err)
+		}
+		if bytes.Equal(state, newest) {
+			return nil
+		}
+	}
+
+	err = h.storageEngine.CreateFile(statefileVersionPath(bucketPath, newStatefileVersionID()), state)
+	if err != nil {
+		return fmt.Errorf("failed to write statefile version: %v", err)
+	}
+
+	// the new version is not part of the listing so one less is kept
+	if retention <= 0 || len(ids) < retention {
+		return nil
+	}
+	for _, id := range ids[retention-1:] {
+		err = h.storageEngine.DeleteFile(statefileVersionPath(bucketPath, id))
+		if err != nil {
+			return fmt.Errorf("failed to delete statefile version %s: %v", id, err)
+		}
+	}
+
+	return nil
+}
+
+// Remove
+//
+//	Removes every version of the statefile at the passed bucket path
+func (h statefileHistory) Remove(bucketPath string) error {
+	ids, err := h.versionIDs(bucketPath)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		err = h.storageEngine.DeleteFile(statefileVersionPath(bucketPath, id))
+		if err != nil {
+			return fmt.Errorf("failed to delete statefile version %s: %v", id, err)
+		}
+	}
+	return nil
+}
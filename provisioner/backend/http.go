@@ -0,0 +1,521 @@
+package backend
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gigo-ws/config"
+	"github.com/gage-technologies/gigo-lib/storage"
+	etcd "go.etcd.io/etcd/client/v3"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const provisionerBackendHTTPTemplate = `backend "http" {
+  address = "%s"
+  lock_address = "%s"
+  unlock_address = "%s"
+}`
+
+const (
+	// defaultHTTPBackendPort port of the state server when none is configured
+	defaultHTTPBackendPort = 45248
+	// defaultHTTPCredentialTTL lifetime of the credentials of an operation
+	defaultHTTPCredentialTTL = time.Hour * 6
+	// httpStatePrefix bucket path prefix of the statefiles - the remainder
+	// of the bucket path is the name of the state in the state server url
+	httpStatePrefix = "states/"
+	// httpUsername username of the credentials passed to terraform
+	httpUsername = "gigo-ws"
+)
+
+// httpStateNamePattern limits the state names served by the state server
+var httpStateNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ProvisionerBackendHTTP
+//
+//	Implementation of the Terraform remote backend that is served by
+//	gigo-ws itself. Terraform reaches the state server over loopback
+//	with credentials that are issued for each operation. States are
+//	stored in the storage engine and locks are held by the StateLocker.
+type ProvisionerBackendHTTP struct {
+	config.HTTPBackendConfig
+	storageEngine storage.Storage
+	history       statefileHistory
+	locker        StateLocker
+	// key signs the credentials issued to terraform - a key is
+	// generated on startup so credentials do not outlive the process
+	key    []byte
+	server *http.Server
+}
+
+// NewProvisionerBackendHTTP
+//
+//	Creates a new ProvisionerBackendHTTP and begins serving the states on
+//	the configured loopback port. Locks are held in etcd if etcd hosts
+//	are configured and in memory otherwise.
+func NewProvisionerBackendHTTP(c config.HTTPBackendConfig, storageEngine storage.Storage) (ProvisionerBackend, error) {
+	if storageEngine == nil {
+		return nil, fmt.Errorf("http backend requires a storage engine")
+	}
+
+	var locker StateLocker
+	if len(c.Etcd.Hosts) > 0 {
+		var err error
+		locker, err = NewEtcdStateLocker(etcd.Config{
+			Endpoints: c.Etcd.Hosts,
+			Username:  c.Etcd.Username,
+			Password:  c.Etcd.Password,
+		}, "/gigo-ws/state-locks", time.Second*10)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create state locker: %v", err)
+		}
+	} else {
+		locker = NewMemoryStateLocker()
+	}
+
+	b, err := newProvisionerBackendHTTP(c, storageEngine, locker)
+	if err != nil {
+		_ = locker.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+// newProvisionerBackendHTTP
+//
+//	Creates a new ProvisionerBackendHTTP with the passed locker and
+//	begins serving the states. A port of -1 listens on a random port.
+func newProvisionerBackendHTTP(c config.HTTPBackendConfig, storageEngine storage.Storage, locker StateLocker) (*ProvisionerBackendHTTP, error) {
+	if c.Port == 0 {
+		c.Port = defaultHTTPBackendPort
+	}
+	if c.CredentialTTL <= 0 {
+		c.CredentialTTL = int(defaultHTTPCredentialTTL.Seconds())
+	}
+
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate credential key: %v", err)
+	}
+
+	// the state server is only reachable by the terraform processes of this node
+	port := c.Port
+	if port < 0 {
+		port = 0
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for state requests: %v", err)
+	}
+	c.Port = listener.Addr().(*net.TCPAddr).Port
+
+	b := &ProvisionerBackendHTTP{
+		HTTPBackendConfig: c,
+		storageEngine:     storageEngine,
+		history:           statefileHistory{storageEngine: storageEngine},
+		locker:            locker,
+		key:               key,
+	}
+	b.server = &http.Server{
+		Handler:           b,
+		ReadHeaderTimeout: time.Second * 10,
+	}
+
+	go func() {
+		_ = b.server.Serve(listener)
+	}()
+
+	return b, nil
+}
+
+// Close
+//
+//	Stops the state server and closes the state locker
+func (b *ProvisionerBackendHTTP) Close() error {
+	err := b.server.Close()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to close state server: %v", err)
+	}
+	return b.locker.Close()
+}
+
+// String
+//
+//	Formats the provider backend into a string.
+//	Wrapper around ToTerraform to make native Go
+//	printing easier.
+func (b *ProvisionerBackendHTTP) String() string {
+	// format to the terraform HCL configuration string but pass an empty value
+	// for the bucket path since we have no specific target
+	s, _ := b.ToTerraform("")
+	return s
+}
+
+// ToTerraform
+//
+//		Formats the provider backend into a Terraform
+//		compatible backend configuration that can be
+//		inserted into a terraform HCL file
+//
+//	 Args
+//			- bucketPath (string): path to terraform state inside the bucket
+//	 Returns
+//	     (string): terraform HCL compliant backend configuration
+//		 ([]string): credentials in the form of environment variables
+func (b *ProvisionerBackendHTTP) ToTerraform(bucketPath string) (string, []string) {
+	address := fmt.Sprintf("http://127.0.0.1:%d/state/%s", b.Port, stateName(bucketPath))
+
+	backend := fmt.Sprintf(
+		provisionerBackendHTTPTemplate,
+		address,
+		address,
+		address,
+	)
+
+	// the credentials are scoped to the state and expire so that a
+	// leaked module environment cannot be used to access other states
+	expiration := time.Now().Add(time.Duration(b.CredentialTTL) * time.Second)
+	return backend, []string{
+		fmt.Sprintf("TF_HTTP_USERNAME=%s", httpUsername),
+		fmt.Sprintf("TF_HTTP_PASSWORD=%s", b.credential(bucketPath, expiration)),
+	}
+}
+
+// stateName
+//
+//	Returns the name of the statefile at the passed bucket path in the
+//	state server. Statefiles are stored at states/<workspace id> so the
+//	state is named after the id of the workspace.
+func stateName(bucketPath string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(bucketPath, httpStatePrefix), "/", "-")
+}
+
+// credential
+//
+//	Issues a credential for the statefile at the passed bucket
+//	path that is valid until the passed expiration
+func (b *ProvisionerBackendHTTP) credential(bucketPath string, expiration time.Time) string {
+	exp := strconv.FormatInt(expiration.Unix(), 10)
+	return exp + "." + b.sign(bucketPath, exp)
+}
+
+// sign
+//
+//	Signs the bucket path and expiration of a credential
+func (b *ProvisionerBackendHTTP) sign(bucketPath string, exp string) string {
+	mac := hmac.New(sha256.New, b.key)
+	mac.Write([]byte(bucketPath + "\n" + exp))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// authenticate
+//
+//	Validates the credential of a state request for the statefile at the passed bucket path
+func (b *ProvisionerBackendHTTP) authenticate(r *http.Request, bucketPath string) bool {
+	username, password, ok := r.BasicAuth()
+	if !ok || username != httpUsername {
+		return false
+	}
+
+	exp, signature, ok := strings.Cut(password, ".")
+	if !ok {
+		return false
+	}
+
+	expiration, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > expiration {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(signature), []byte(b.sign(bucketPath, exp))) == 1
+}
+
+// ServeHTTP
+//
+//	Serves the terraform http backend protocol for the states
+//	at /state/<name> with the LOCK and UNLOCK methods for locking
+func (b *ProvisionerBackendHTTP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/state/")
+	if name == r.URL.Path || !httpStateNamePattern.MatchString(name) {
+		http.NotFound(w, r)
+		return
+	}
+	bucketPath := httpStatePrefix + name
+
+	if !b.authenticate(r, bucketPath) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="gigo-ws"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		b.getState(w, r, bucketPath)
+	case http.MethodPost:
+		b.updateState(w, r, bucketPath)
+	case http.MethodDelete:
+		b.deleteState(w, r, bucketPath)
+	case "LOCK":
+		b.lockState(w, r, bucketPath)
+	case "UNLOCK":
+		b.unlockState(w, r, bucketPath)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getState
+//
+//	Writes the statefile or responds with not found which
+//	terraform treats as an empty state
+func (b *ProvisionerBackendHTTP) getState(w http.ResponseWriter, r *http.Request, bucketPath string) {
+	buf, err := b.storageEngine.GetFile(bucketPath)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read state: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if buf == nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer buf.Close()
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = io.Copy(w, buf)
+}
+
+// checkLock
+//
+//	Ensures that a state write is performed by the holder of the state
+//	lock. Terraform passes the id of its lock as the ID query parameter.
+func (b *ProvisionerBackendHTTP) checkLock(w http.ResponseWriter, r *http.Request, bucketPath string) bool {
+	holder, err := b.locker.Get(r.Context(), bucketPath)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get lock: %v", err), http.StatusInternalServerError)
+		return false
+	}
+	if holder != nil && holder.ID != r.URL.Query().Get("ID") {
+		writeLockInfo(w, http.StatusLocked, holder)
+		return false
+	}
+	return true
+}
+
+// updateState
+//
+//	Writes the statefile sent by terraform
+func (b *ProvisionerBackendHTTP) updateState(w http.ResponseWriter, r *http.Request, bucketPath string) {
+	if !b.checkLock(w, r, bucketPath) {
+		return
+	}
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read state: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	err = b.storageEngine.CreateFile(bucketPath, buf)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to write state: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// deleteState
+//
+//	Deletes the statefile
+func (b *ProvisionerBackendHTTP) deleteState(w http.ResponseWriter, r *http.Request, bucketPath string) {
+	if !b.checkLock(w, r, bucketPath) {
+		return
+	}
+
+	err := b.storageEngine.DeleteFile(bucketPath)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to delete state: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// lockState
+//
+//	Acquires the state lock with the lock info sent by terraform. The
+//	current lock is returned with a locked status if the state is locked.
+func (b *ProvisionerBackendHTTP) lockState(w http.ResponseWriter, r *http.Request, bucketPath string) {
+	var info LockInfo
+	err := json.NewDecoder(r.Body).Decode(&info)
+	if err != nil || info.ID == "" {
+		http.Error(w, "invalid lock info", http.StatusBadRequest)
+		return
+	}
+
+	holder, err := b.locker.Lock(r.Context(), bucketPath, info)
+	if err != nil {
+		if errors.Is(err, ErrStateLocked) {
+			writeLockInfo(w, http.StatusLocked, holder)
+			return
+		}
+		http.Error(w, fmt.Sprintf("failed to lock state: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// unlockState
+//
+//	Releases the state lock. Terraform sends the lock info of its lock
+//	and sends no lock info when the lock is forcefully released.
+func (b *ProvisionerBackendHTTP) unlockState(w http.ResponseWriter, r *http.Request, bucketPath string) {
+	var info LockInfo
+	err := json.NewDecoder(r.Body).Decode(&info)
+	if err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid lock info", http.StatusBadRequest)
+		return
+	}
+
+	err = b.locker.Unlock(r.Context(), bucketPath, info.ID)
+	if err != nil {
+		if errors.Is(err, ErrLockIDMismatch) {
+			holder, _ := b.locker.Get(r.Context(), bucketPath)
+			writeLockInfo(w, http.StatusConflict, holder)
+			return
+		}
+		http.Error(w, fmt.Sprintf("failed to unlock state: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeLockInfo
+//
+//	Responds with the passed status and the current lock which
+//	terraform reports to the user when it fails to lock the state
+func writeLockInfo(w http.ResponseWriter, status int, holder *LockInfo) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if holder != nil {
+		_ = json.NewEncoder(w).Encode(holder)
+	}
+}
+
+// GetStatefile
+//
+//	Returns the provisioner backend's current state file
+//	for the passed bucket path
+func (b *ProvisionerBackendHTTP) GetStatefile(bucketPath string) (io.ReadCloser, error) {
+	return b.storageEngine.GetFile(bucketPath)
+}
+
+// RemoveStatefile
+//
+//	Removes the statefile and its history from the provisioner
+//	backend at the passed bucket path. Terraform does not write
+//	backups of states that it stores in an http backend.
+func (b *ProvisionerBackendHTTP) RemoveStatefile(bucketPath string) error {
+	err := b.storageEngine.DeleteFile(bucketPath)
+	if err != nil {
+		return fmt.Errorf("failed to delete state file: %v", err)
+	}
+
+	err = b.history.Remove(bucketPath)
+	if err != nil {
+		return fmt.Errorf("failed to delete statefile history: %v", err)
+	}
+
+	return nil
+}
+
+// List
+//
+//	Lists the statefiles stored in the provisioner backend under the
+//	passed prefix. The storage engine does not expose modification
+//	times so the last modification time is left unset.
+func (b *ProvisionerBackendHTTP) List(prefix string) ([]StatefileInfo, error) {
+	files, err := b.storageEngine.ListDir(prefix, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefiles: %v", err)
+	}
+
+	statefiles := make([]StatefileInfo, 0, len(files))
+	for _, f := range files {
+		statefiles = append(statefiles, StatefileInfo{
+			BucketPath: f,
+		})
+	}
+
+	return statefiles, nil
+}
+
+// GetLock
+//
+//	Returns the state lock held for the statefile at the passed
+//	bucket path or nil if the statefile is not locked
+func (b *ProvisionerBackendHTTP) GetLock(bucketPath string) (*LockInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	return b.locker.Get(ctx, bucketPath)
+}
+
+// ForceUnlock
+//
+//	Removes the state lock held for the statefile at the passed bucket
+//	path. If a lock id is passed the lock is only removed if it is the
+//	current lock. No-op if the statefile is not locked.
+func (b *ProvisionerBackendHTTP) ForceUnlock(bucketPath string, lockID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	return b.locker.Unlock(ctx, bucketPath, lockID)
+}
+
+// ListStatefileVersions
+//
+//	Lists the versions of the statefile at the passed bucket
+//	path that can be restored ordered from newest to oldest
+func (b *ProvisionerBackendHTTP) ListStatefileVersions(bucketPath string) ([]StatefileVersion, error) {
+	return b.history.List(bucketPath)
+}
+
+// GetStatefileVersion
+//
+//	Returns a version of the statefile at the passed bucket
+//	path or nil if the version does not exist
+func (b *ProvisionerBackendHTTP) GetStatefileVersion(bucketPath string, versionID string) (io.ReadCloser, error) {
+	return b.history.Get(bucketPath, versionID)
+}
+
+// RestoreStatefile
+//
+//	Replaces the statefile at the passed bucket path with a previous
+//	version. ErrStatefileVersionNotFound is returned if the version
+//	does not exist.
+func (b *ProvisionerBackendHTTP) RestoreStatefile(bucketPath string, versionID string) error {
+	return b.history.Restore(bucketPath, versionID)
+}
+
+// SnapshotStatefile
+//
+//	Copies the statefile at the passed bucket path into its history in
+//	the storage engine unless it matches the newest version. The oldest
+//	versions are removed once more than the retention are kept.
+func (b *ProvisionerBackendHTTP) SnapshotStatefile(bucketPath string, retention int) error {
+	return b.history.Snapshot(bucketPath, retention)
+}
@@ -0,0 +1,167 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gigo-ws/config"
+	"github.com/gage-technologies/gigo-lib/storage"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testHTTPRequest
+//
+//	Sends a state request with the credentials from the passed environment
+func testHTTPRequest(t *testing.T, method string, url string, envs []string, body []byte) (int, []byte) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var username, password string
+	for _, env := range envs {
+		if v, ok := strings.CutPrefix(env, "TF_HTTP_USERNAME="); ok {
+			username = v
+		}
+		if v, ok := strings.CutPrefix(env, "TF_HTTP_PASSWORD="); ok {
+			password = v
+		}
+	}
+	req.SetBasicAuth(username, password)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	buf, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.StatusCode, buf
+}
+
+func TestProvisionerBackendHTTP(t *testing.T) {
+	dir, err := os.MkdirTemp("", "gigo-ws-http-backend-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	storageEngine, err := storage.CreateFileSystemStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provisioner, err := newProvisionerBackendHTTP(config.HTTPBackendConfig{Port: -1}, storageEngine, NewMemoryStateLocker())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer provisioner.Close()
+
+	o, envs := provisioner.ToTerraform("states/42")
+	url := fmt.Sprintf("http://127.0.0.1:%d/state/42", provisioner.Port)
+	if !strings.Contains(o, `address = "`+url+`"`) {
+		t.Fatalf("invalid backend configuration:\n%s", o)
+	}
+
+	// credentials are scoped to a single state
+	_, otherEnvs := provisioner.ToTerraform("states/69")
+	status, _ := testHTTPRequest(t, http.MethodGet, url, otherEnvs, nil)
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized for credentials of another state, got %d", status)
+	}
+	status, _ = testHTTPRequest(t, http.MethodGet, url, nil, nil)
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized without credentials, got %d", status)
+	}
+
+	// missing states are reported as not found
+	status, _ = testHTTPRequest(t, http.MethodGet, url, envs, nil)
+	if status != http.StatusNotFound {
+		t.Fatalf("expected not found for missing state, got %d", status)
+	}
+
+	lock, err := json.Marshal(LockInfo{ID: "lock-1", Operation: "OperationTypeApply", Created: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, _ = testHTTPRequest(t, "LOCK", url, envs, lock)
+	if status != http.StatusOK {
+		t.Fatalf("expected lock to be acquired, got %d", status)
+	}
+
+	// a second lock is rejected with the current lock
+	status, buf := testHTTPRequest(t, "LOCK", url, envs, []byte(`{"ID": "lock-2"}`))
+	if status != http.StatusLocked || !strings.Contains(string(buf), "lock-1") {
+		t.Fatalf("expected locked with the current lock, got %d %s", status, string(buf))
+	}
+
+	// only the lock holder can write the state
+	status, _ = testHTTPRequest(t, http.MethodPost, url, envs, []byte(`{"version": 4}`))
+	if status != http.StatusLocked {
+		t.Fatalf("expected write without lock id to be rejected, got %d", status)
+	}
+	status, _ = testHTTPRequest(t, http.MethodPost, url+"?ID=lock-1", envs, []byte(`{"version": 4}`))
+	if status != http.StatusOK {
+		t.Fatalf("expected write by the lock holder to succeed, got %d", status)
+	}
+
+	status, buf = testHTTPRequest(t, http.MethodGet, url, envs, nil)
+	if status != http.StatusOK || string(buf) != `{"version": 4}` {
+		t.Fatalf("invalid state: %d %s", status, string(buf))
+	}
+
+	info, err := provisioner.GetLock("states/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info == nil || info.ID != "lock-1" {
+		t.Fatalf("invalid lock: %+v", info)
+	}
+
+	err = provisioner.ForceUnlock("states/42", "lock-2")
+	if !errors.Is(err, ErrLockIDMismatch) {
+		t.Fatalf("expected lock id mismatch, got %v", err)
+	}
+
+	status, _ = testHTTPRequest(t, "UNLOCK", url, envs, lock)
+	if status != http.StatusOK {
+		t.Fatalf("expected unlock to succeed, got %d", status)
+	}
+
+	info, err = provisioner.GetLock("states/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info != nil {
+		t.Fatalf("expected lock to be released, got %+v", info)
+	}
+
+	statefiles, err := provisioner.List("states")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statefiles) != 1 || statefiles[0].BucketPath != "states/42" {
+		t.Fatalf("invalid statefiles: %+v", statefiles)
+	}
+
+	err = provisioner.RemoveStatefile("states/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := provisioner.GetStatefile("states/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != nil {
+		t.Fatal("expected state to be removed")
+	}
+}
@@ -0,0 +1,569 @@
+package backend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gigo-ws/config"
+	"io"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sort"
+	"strings"
+)
+
+const provisionerBackendKubernetesTemplate = `backend "kubernetes" {
+  secret_suffix = "%s"
+  namespace = "%s"
+  in_cluster_config = %t
+}`
+
+const (
+	// kubernetesStatePrefix bucket path prefix of the statefiles - the
+	// remainder of the bucket path is used as the secret suffix
+	kubernetesStatePrefix = "states/"
+	// kubernetesWorkspace terraform workspace of all gigo modules
+	kubernetesWorkspace = "default"
+	// kubernetesStateKey key of the gzipped state in the secret data
+	kubernetesStateKey = "tfstate"
+	// kubernetesSuffixLabel label that terraform sets to the secret suffix
+	kubernetesSuffixLabel = "tfstateSecretSuffix"
+	// kubernetesLockInfoAnnotation annotation of the lease that terraform
+	// writes the lock info to while the state is locked
+	kubernetesLockInfoAnnotation = "app.terraform.io/lock-info"
+	// kubernetesHistoryLabel label of the history secrets that is set to
+	// the secret suffix of the statefile that the version belongs to
+	kubernetesHistoryLabel = "gigo-ws/statefile-history"
+)
+
+// kubernetesStateSelector labels that terraform sets on every state secret
+var kubernetesStateSelector = labels.Set{
+	"app.kubernetes.io/managed-by": "terraform",
+	"tfstate":                      "true",
+	"tfstateWorkspace":             kubernetesWorkspace,
+}
+
+// ProvisionerBackendKubernetes
+//
+//	Kubernetes Secret based implementation of the
+//	Terraform remote backend
+type ProvisionerBackendKubernetes struct {
+	config.KubernetesBackendConfig
+	client kubernetes.Interface
+}
+
+// NewProvisionerBackendKubernetes
+//
+//	Creates a new ProvisionerBackendKubernetes from a kubernetes backend configuration
+func NewProvisionerBackendKubernetes(c config.KubernetesBackendConfig) (ProvisionerBackend, error) {
+	// load the same credentials that terraform is configured with
+	var restConfig *rest.Config
+	var err error
+	if c.InCluster {
+		restConfig, err = rest.InClusterConfig()
+	} else {
+		restConfig, err = clientcmd.BuildConfigFromFlags("", c.ConfigPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubernetes config: %v", err)
+	}
+
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %v", err)
+	}
+
+	return newProvisionerBackendKubernetes(c, client), nil
+}
+
+// newProvisionerBackendKubernetes
+//
+//	Creates a new ProvisionerBackendKubernetes with the passed client
+func newProvisionerBackendKubernetes(c config.KubernetesBackendConfig, client kubernetes.Interface) *ProvisionerBackendKubernetes {
+	if c.Namespace == "" {
+		c.Namespace = "default"
+	}
+	return &ProvisionerBackendKubernetes{
+		KubernetesBackendConfig: c,
+		client:                  client,
+	}
+}
+
+// String
+//
+//	Formats the provider backend into a string.
+//	Wrapper around ToTerraform to make native Go
+//	printing easier.
+func (b *ProvisionerBackendKubernetes) String() string {
+	// format to the terraform HCL configuration string but pass an empty value
+	// for the bucket path since we have no specific target
+	s, _ := b.ToTerraform("")
+	return s
+}
+
+// ToTerraform
+//
+//		Formats the provider backend into a Terraform
+//		compatible backend configuration that can be
+//		inserted into a terraform HCL file
+//
+//	 Args
+//			- bucketPath (string): path to terraform state inside the bucket
+//	 Returns
+//	     (string): terraform HCL compliant backend configuration
+//		 ([]string): credentials in the form of environment variables
+func (b *ProvisionerBackendKubernetes) ToTerraform(bucketPath string) (string, []string) {
+	envs := []string{}
+	if !b.InCluster && b.ConfigPath != "" {
+		envs = append(envs, fmt.Sprintf("KUBE_CONFIG_PATH=%s", b.ConfigPath))
+	}
+
+	return fmt.Sprintf(
+		provisionerBackendKubernetesTemplate,
+		secretSuffix(bucketPath),
+		b.Namespace,
+		b.InCluster,
+	), envs
+}
+
+// secretSuffix
+//
+//	Returns the secret suffix of the statefile at the passed bucket
+//	path. Statefiles are stored at states/<workspace id> so the suffix
+//	is the id of the workspace.
+func secretSuffix(bucketPath string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(bucketPath, kubernetesStatePrefix), "/", "-")
+}
+
+// secretName
+//
+//	Returns the name of the secret that terraform writes
+//	the statefile at the passed bucket path to
+func secretName(bucketPath string) string {
+	return fmt.Sprintf("tfstate-%s-%s", kubernetesWorkspace, secretSuffix(bucketPath))
+}
+
+// leaseName
+//
+//	Returns the name of the lease that terraform locks
+//	the statefile at the passed bucket path with
+func leaseName(bucketPath string) string {
+	return "lock-" + secretName(bucketPath)
+}
+
+// GetStatefile
+//
+//	Returns the provisioner backend's current state file
+//	for the passed bucket path. Terraform gzips the state
+//	so the statefile is decompressed before it is returned.
+func (b *ProvisionerBackendKubernetes) GetStatefile(bucketPath string) (io.ReadCloser, error) {
+	secret, err := b.client.CoreV1().Secrets(b.Namespace).Get(context.Background(), secretName(bucketPath), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get state secret: %v", err)
+	}
+
+	return readStateSecret(secret)
+}
+
+// readStateSecret
+//
+//	Returns the decompressed state stored in the passed secret
+func readStateSecret(secret *corev1.Secret) (io.ReadCloser, error) {
+	state, ok := secret.Data[kubernetesStateKey]
+	if !ok {
+		return nil, fmt.Errorf("state secret %s is missing the %s key", secret.Name, kubernetesStateKey)
+	}
+
+	// tolerate uncompressed states that were written by hand
+	if !bytes.HasPrefix(state, []byte{0x1f, 0x8b}) {
+		return io.NopCloser(bytes.NewReader(state)), nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(state))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress statefile: %v", err)
+	}
+
+	return reader, nil
+}
+
+// RemoveStatefile
+//
+//	Removes the statefile, its history and the lock lease (if it
+//	exists) from the provisioner backend at the passed bucket path.
+//	Terraform does not keep a backup of states stored in secrets.
+func (b *ProvisionerBackendKubernetes) RemoveStatefile(bucketPath string) error {
+	// delete statefile
+	err := b.client.CoreV1().Secrets(b.Namespace).Delete(context.Background(), secretName(bucketPath), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete state secret: %v", err)
+	}
+
+	// terraform leaves the lease behind when it releases the lock
+	err = b.client.CoordinationV1().Leases(b.Namespace).Delete(context.Background(), leaseName(bucketPath), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete lock lease: %v", err)
+	}
+
+	history, err := b.listHistory(bucketPath)
+	if err != nil {
+		return err
+	}
+	for _, secret := range history {
+		err = b.client.CoreV1().Secrets(b.Namespace).Delete(context.Background(), secret.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete history secret: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// List
+//
+//	Lists the statefiles stored in the provisioner backend
+//	under the passed prefix. Secrets do not record when they
+//	were last modified so the time of the last update by a
+//	field manager is used when it is available.
+func (b *ProvisionerBackendKubernetes) List(prefix string) ([]StatefileInfo, error) {
+	// conditionally append final slash to prefix if it was not passed
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix = prefix + "/"
+	}
+
+	secrets, err := b.client.CoreV1().Secrets(b.Namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: kubernetesStateSelector.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list state secrets: %v", err)
+	}
+
+	statefiles := make([]StatefileInfo, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		suffix, ok := secret.Labels[kubernetesSuffixLabel]
+		if !ok {
+			continue
+		}
+
+		bucketPath := kubernetesStatePrefix + suffix
+		if !strings.HasPrefix(bucketPath, prefix) {
+			continue
+		}
+
+		lastModified := secret.CreationTimestamp.Time
+		for _, field := range secret.ManagedFields {
+			if field.Time != nil && field.Time.After(lastModified) {
+				lastModified = field.Time.Time
+			}
+		}
+
+		statefiles = append(statefiles, StatefileInfo{
+			BucketPath:   bucketPath,
+			LastModified: lastModified,
+		})
+	}
+
+	return statefiles, nil
+}
+
+// getLease
+//
+//	Returns the lock lease of the statefile at the passed
+//	bucket path or nil if the lease does not exist
+func (b *ProvisionerBackendKubernetes) getLease(bucketPath string) (*coordinationv1.Lease, error) {
+	lease, err := b.client.CoordinationV1().Leases(b.Namespace).Get(context.Background(), leaseName(bucketPath), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get lock lease: %v", err)
+	}
+	return lease, nil
+}
+
+// GetLock
+//
+//	Returns the state lock held for the statefile at the passed
+//	bucket path or nil if the statefile is not locked. Terraform
+//	keeps the lease after it releases the lock so the state is
+//	only locked while the lease has a holder.
+func (b *ProvisionerBackendKubernetes) GetLock(bucketPath string) (*LockInfo, error) {
+	lease, err := b.getLease(bucketPath)
+	if err != nil {
+		return nil, err
+	}
+	if lease == nil || lease.Spec.HolderIdentity == nil {
+		return nil, nil
+	}
+
+	raw, ok := lease.Annotations[kubernetesLockInfoAnnotation]
+	if !ok {
+		// report the holder even if the lock info is missing
+		return &LockInfo{ID: *lease.Spec.HolderIdentity}, nil
+	}
+
+	var info LockInfo
+	err = json.Unmarshal([]byte(raw), &info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode lock info: %v", err)
+	}
+
+	return &info, nil
+}
+
+// ForceUnlock
+//
+//	Removes the state lock held for the statefile at the passed bucket
+//	path. If a lock id is passed the lock is only removed if it is the
+//	current lock. No-op if the statefile is not locked.
+func (b *ProvisionerBackendKubernetes) ForceUnlock(bucketPath string, lockID string) error {
+	lease, err := b.getLease(bucketPath)
+	if err != nil {
+		return err
+	}
+	if lease == nil || lease.Spec.HolderIdentity == nil {
+		return nil
+	}
+
+	if lockID != "" && *lease.Spec.HolderIdentity != lockID {
+		return ErrLockIDMismatch
+	}
+
+	// release the lease the same way terraform does so that the update
+	// fails if the lease changed since we read it
+	lease.Spec.HolderIdentity = nil
+	delete(lease.Annotations, kubernetesLockInfoAnnotation)
+	_, err = b.client.CoordinationV1().Leases(b.Namespace).Update(context.Background(), lease, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to release lock lease: %v", err)
+	}
+
+	return nil
+}
+
+// historySecretPrefix
+//
+//	Returns the name prefix of the secrets holding the
+//	history of the statefile at the passed bucket path
+func historySecretPrefix(bucketPath string) string {
+	return fmt.Sprintf("tfstate-history-%s-", secretSuffix(bucketPath))
+}
+
+// listHistory
+//
+//	Returns the history secrets of the statefile at the passed
+//	bucket path ordered from the newest to the oldest version
+func (b *ProvisionerBackendKubernetes) listHistory(bucketPath string) ([]corev1.Secret, error) {
+	secrets, err := b.client.CoreV1().Secrets(b.Namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: labels.Set{kubernetesHistoryLabel: secretSuffix(bucketPath)}.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history secrets: %v", err)
+	}
+
+	// ids are padded so the names sort chronologically
+	history := secrets.Items
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Name > history[j].Name
+	})
+
+	return history, nil
+}
+
+// getHistorySecret
+//
+//	Returns the secret holding a version of the statefile at the
+//	passed bucket path or nil if the version does not exist
+func (b *ProvisionerBackendKubernetes) getHistorySecret(bucketPath string, versionID string) (*corev1.Secret, error) {
+	_, err := parseStatefileVersionID(versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := b.client.CoreV1().Secrets(b.Namespace).Get(context.Background(), historySecretPrefix(bucketPath)+versionID, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get history secret: %v", err)
+	}
+	return secret, nil
+}
+
+// ListStatefileVersions
+//
+//	Lists the versions of the statefile at the passed bucket
+//	path that can be restored ordered from newest to oldest
+func (b *ProvisionerBackendKubernetes) ListStatefileVersions(bucketPath string) ([]StatefileVersion, error) {
+	history, err := b.listHistory(bucketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]StatefileVersion, 0, len(history))
+	for i := range history {
+		id := strings.TrimPrefix(history[i].Name, historySecretPrefix(bucketPath))
+		created, err := parseStatefileVersionID(id)
+		if err != nil {
+			continue
+		}
+
+		// report the size of the decompressed state like the other backends
+		state, err := readStateSecret(&history[i])
+		if err != nil {
+			return nil, err
+		}
+		size, err := io.Copy(io.Discard, state)
+		_ = state.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read statefile version %s: %v", id, err)
+		}
+
+		versions = append(versions, StatefileVersion{
+			ID:           id,
+			LastModified: created,
+			Size:         size,
+		})
+	}
+
+	return versions, nil
+}
+
+// GetStatefileVersion
+//
+//	Returns a version of the statefile at the passed bucket
+//	path or nil if the version does not exist
+func (b *ProvisionerBackendKubernetes) GetStatefileVersion(bucketPath string, versionID string) (io.ReadCloser, error) {
+	secret, err := b.getHistorySecret(bucketPath, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, nil
+	}
+	return readStateSecret(secret)
+}
+
+// RestoreStatefile
+//
+//	Replaces the statefile at the passed bucket path with a previous
+//	version. The state secret is recreated the way terraform creates
+//	it if it was deleted. ErrStatefileVersionNotFound is returned if
+//	the version does not exist.
+func (b *ProvisionerBackendKubernetes) RestoreStatefile(bucketPath string, versionID string) error {
+	version, err := b.getHistorySecret(bucketPath, versionID)
+	if err != nil {
+		return err
+	}
+	if version == nil {
+		return ErrStatefileVersionNotFound
+	}
+
+	secrets := b.client.CoreV1().Secrets(b.Namespace)
+	secret, err := secrets.Get(context.Background(), secretName(bucketPath), metav1.GetOptions{})
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get state secret: %v", err)
+		}
+
+		stateLabels := labels.Merge(kubernetesStateSelector, labels.Set{kubernetesSuffixLabel: secretSuffix(bucketPath)})
+		_, err = secrets.Create(context.Background(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName(bucketPath),
+				Namespace: b.Namespace,
+				Labels:    stateLabels,
+			},
+			Data: map[string][]byte{
+				kubernetesStateKey: version.Data[kubernetesStateKey],
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create state secret: %v", err)
+		}
+		return nil
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[kubernetesStateKey] = version.Data[kubernetesStateKey]
+	_, err = secrets.Update(context.Background(), secret, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update state secret: %v", err)
+	}
+
+	return nil
+}
+
+// SnapshotStatefile
+//
+//	Copies the state secret of the statefile at the passed bucket path
+//	into a history secret unless it matches the newest version. The
+//	oldest history secrets are removed once more than the retention
+//	are kept.
+func (b *ProvisionerBackendKubernetes) SnapshotStatefile(bucketPath string, retention int) error {
+	secrets := b.client.CoreV1().Secrets(b.Namespace)
+	secret, err := secrets.Get(context.Background(), secretName(bucketPath), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get state secret: %v", err)
+	}
+
+	state, ok := secret.Data[kubernetesStateKey]
+	if !ok {
+		return nil
+	}
+
+	history, err := b.listHistory(bucketPath)
+	if err != nil {
+		return err
+	}
+
+	// skip the copy if the state has not changed since the last snapshot
+	if len(history) > 0 && bytes.Equal(history[0].Data[kubernetesStateKey], state) {
+		return nil
+	}
+
+	_, err = secrets.Create(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      historySecretPrefix(bucketPath) + newStatefileVersionID(),
+			Namespace: b.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "gigo-ws",
+				kubernetesHistoryLabel:         secretSuffix(bucketPath),
+			},
+		},
+		Data: map[string][]byte{
+			kubernetesStateKey: state,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create history secret: %v", err)
+	}
+
+	// the new version is not part of the listing so one less is kept
+	if retention <= 0 || len(history) < retention {
+		return nil
+	}
+	for _, h := range history[retention-1:] {
+		err = secrets.Delete(context.Background(), h.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete history secret: %v", err)
+		}
+	}
+
+	return nil
+}
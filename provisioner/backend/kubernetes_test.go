@@ -0,0 +1,318 @@
+package backend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"gigo-ws/config"
+	"io"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+	"time"
+)
+
+// testStateSecret
+//
+//	Creates a state secret the way the terraform kubernetes backend writes it
+func testStateSecret(t *testing.T, suffix string, state string, created time.Time) *corev1.Secret {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(state))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "tfstate-default-" + suffix,
+			Namespace:         "gigo",
+			CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "terraform",
+				"tfstate":                      "true",
+				"tfstateWorkspace":             "default",
+				"tfstateSecretSuffix":          suffix,
+			},
+		},
+		Data: map[string][]byte{
+			"tfstate": buf.Bytes(),
+		},
+	}
+}
+
+func TestProvisionerBackendKubernetes_ToTerraform(t *testing.T) {
+	provisioner := newProvisionerBackendKubernetes(config.KubernetesBackendConfig{
+		Namespace:  "gigo",
+		ConfigPath: "/etc/kube/config",
+	}, fake.NewSimpleClientset())
+
+	o, envs := provisioner.ToTerraform("states/42")
+
+	expected := `backend "kubernetes" {
+  secret_suffix = "42"
+  namespace = "gigo"
+  in_cluster_config = false
+}`
+	if o != expected {
+		t.Fatalf("invalid backend configuration:\n%s", o)
+	}
+
+	if len(envs) != 1 || envs[0] != "KUBE_CONFIG_PATH=/etc/kube/config" {
+		t.Fatalf("invalid environment: %v", envs)
+	}
+}
+
+func TestProvisionerBackendKubernetes_Statefile(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		testStateSecret(t, "42", `{"version": 4}`, time.Unix(1675188000, 0)),
+		&coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: "lock-tfstate-default-42", Namespace: "gigo"}},
+	)
+	provisioner := newProvisionerBackendKubernetes(config.KubernetesBackendConfig{
+		Namespace: "gigo",
+		InCluster: true,
+	}, client)
+
+	buf, err := provisioner.GetStatefile("states/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := io.ReadAll(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = buf.Close()
+	if string(state) != `{"version": 4}` {
+		t.Fatalf("invalid statefile: %s", string(state))
+	}
+
+	// missing statefiles are not an error
+	buf, err = provisioner.GetStatefile("states/69")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf != nil {
+		t.Fatal("expected nil statefile for missing secret")
+	}
+
+	err = provisioner.RemoveStatefile("states/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.CoreV1().Secrets("gigo").Get(context.Background(), "tfstate-default-42", metav1.GetOptions{})
+	if err == nil {
+		t.Fatal("expected state secret to be deleted")
+	}
+	_, err = client.CoordinationV1().Leases("gigo").Get(context.Background(), "lock-tfstate-default-42", metav1.GetOptions{})
+	if err == nil {
+		t.Fatal("expected lock lease to be deleted")
+	}
+
+	// removing a missing statefile is a no-op
+	err = provisioner.RemoveStatefile("states/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProvisionerBackendKubernetes_List(t *testing.T) {
+	unmanaged := testStateSecret(t, "7", "{}", time.Unix(1675188000, 0))
+	delete(unmanaged.Labels, "app.kubernetes.io/managed-by")
+
+	provisioner := newProvisionerBackendKubernetes(config.KubernetesBackendConfig{
+		Namespace: "gigo",
+	}, fake.NewSimpleClientset(
+		testStateSecret(t, "42", "{}", time.Unix(1675188000, 0)),
+		testStateSecret(t, "69", "{}", time.Unix(1675188600, 0)),
+		unmanaged,
+	))
+
+	statefiles, err := provisioner.List("states")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(statefiles) != 2 {
+		t.Fatalf("expected 2 statefiles, got %+v", statefiles)
+	}
+	for _, s := range statefiles {
+		switch s.BucketPath {
+		case "states/42":
+			if !s.LastModified.Equal(time.Unix(1675188000, 0)) {
+				t.Errorf("invalid last modified for %s: %v", s.BucketPath, s.LastModified)
+			}
+		case "states/69":
+			if !s.LastModified.Equal(time.Unix(1675188600, 0)) {
+				t.Errorf("invalid last modified for %s: %v", s.BucketPath, s.LastModified)
+			}
+		default:
+			t.Errorf("unexpected statefile: %s", s.BucketPath)
+		}
+	}
+}
+
+func TestProvisionerBackendKubernetes_Lock(t *testing.T) {
+	holder := "ab6cc2e5-7d43-4c8c-bd6a-3e3c3ea1a5c4"
+	client := fake.NewSimpleClientset(&coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "lock-tfstate-default-42",
+			Namespace: "gigo",
+			Annotations: map[string]string{
+				"app.terraform.io/lock-info": `{"ID":"` + holder + `","Operation":"OperationTypeApply","Who":"gigo@ws-0"}`,
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity: &holder,
+		},
+	})
+	provisioner := newProvisionerBackendKubernetes(config.KubernetesBackendConfig{
+		Namespace: "gigo",
+	}, client)
+
+	lock, err := provisioner.GetLock("states/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock == nil || lock.ID != holder || lock.Operation != "OperationTypeApply" {
+		t.Fatalf("invalid lock: %+v", lock)
+	}
+
+	// a lease without a holder is not locked
+	lock, err = provisioner.GetLock("states/69")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock != nil {
+		t.Fatalf("expected no lock, got %+v", lock)
+	}
+
+	err = provisioner.ForceUnlock("states/42", "wrong-id")
+	if !errors.Is(err, ErrLockIDMismatch) {
+		t.Fatalf("expected lock id mismatch, got %v", err)
+	}
+
+	err = provisioner.ForceUnlock("states/42", holder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err = provisioner.GetLock("states/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock != nil {
+		t.Fatalf("expected lock to be released, got %+v", lock)
+	}
+
+	// the lease is kept for terraform to lock again
+	lease, err := client.CoordinationV1().Leases("gigo").Get(context.Background(), "lock-tfstate-default-42", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := lease.Annotations["app.terraform.io/lock-info"]; ok {
+		t.Fatal("expected lock info to be removed")
+	}
+}
+
+func TestProvisionerBackendKubernetes_History(t *testing.T) {
+	client := fake.NewSimpleClientset(testStateSecret(t, "42", `{"serial": 1}`, time.Unix(1675188000, 0)))
+	provisioner := newProvisionerBackendKubernetes(config.KubernetesBackendConfig{
+		Namespace: "gigo",
+	}, client)
+
+	readState := func(buf io.ReadCloser, err error) string {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf == nil {
+			t.Fatal("expected statefile")
+		}
+		defer buf.Close()
+		state, err := io.ReadAll(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(state)
+	}
+
+	err := provisioner.SnapshotStatefile("states/42", 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate terraform writing a new state
+	_, err = client.CoreV1().Secrets("gigo").Update(
+		context.Background(), testStateSecret(t, "42", `{"serial": 2}`, time.Unix(1675188000, 0)), metav1.UpdateOptions{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the second snapshot is skipped since the state did not change
+	for i := 0; i < 2; i++ {
+		err = provisioner.SnapshotStatefile("states/42", 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	versions, err := provisioner.ListStatefileVersions("states/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[1].Size != int64(len(`{"serial": 1}`)) {
+		t.Fatalf("unexpected versions: %+v", versions)
+	}
+
+	if state := readState(provisioner.GetStatefileVersion("states/42", versions[1].ID)); state != `{"serial": 1}` {
+		t.Fatalf("unexpected statefile version: %s", state)
+	}
+
+	// the state secret is recreated if it was deleted
+	err = client.CoreV1().Secrets("gigo").Delete(context.Background(), "tfstate-default-42", metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = provisioner.RestoreStatefile("states/42", versions[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state := readState(provisioner.GetStatefile("states/42")); state != `{"serial": 1}` {
+		t.Fatalf("statefile was not restored: %s", state)
+	}
+	statefiles, err := provisioner.List("states")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statefiles) != 1 || statefiles[0].BucketPath != "states/42" {
+		t.Fatalf("restored state secret is not listed: %+v", statefiles)
+	}
+
+	err = provisioner.RestoreStatefile("states/42", "1")
+	if !errors.Is(err, ErrStatefileVersionNotFound) {
+		t.Fatalf("expected ErrStatefileVersionNotFound, got %v", err)
+	}
+
+	// the history is removed with the statefile
+	err = provisioner.RemoveStatefile("states/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err = provisioner.ListStatefileVersions("states/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 0 {
+		t.Fatalf("expected history to be removed, got %+v", versions)
+	}
+}
@@ -0,0 +1,471 @@
+package backend
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gigo-ws/config"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const provisionerBackendPGTemplate = `backend "pg" {
+  schema_name = "%s"
+}`
+
+const (
+	// pgDefaultSchema schema that terraform uses when none is configured
+	pgDefaultSchema = "terraform_remote_state"
+	// pgStatePrefix bucket path prefix of the statefiles - the remainder
+	// of the bucket path is used as the name of the terraform workspace
+	pgStatePrefix = "states/"
+	// pgEmptyState empty state written to new workspaces - this is the
+	// state that terraform writes when it creates a workspace itself
+	pgEmptyState = `{"version": 4, "terraform_version": "1.0.0", "serial": 0, "lineage": "%s", "outputs": {}, "resources": []}`
+)
+
+// pgSchemaPattern limits schema names to identifiers that are safe to
+// render into the backend configuration
+var pgSchemaPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ProvisionerBackendPG
+//
+//	PostgreSQL based implementation of the Terraform
+//	remote backend
+type ProvisionerBackendPG struct {
+	config.PGBackendConfig
+	db *sql.DB
+}
+
+// NewProvisionerBackendPG
+//
+//	Creates a new ProvisionerBackendPG from a pg backend configuration.
+//	The schema and state table are created the same way terraform
+//	creates them so that statefiles can be read before the first apply.
+func NewProvisionerBackendPG(c config.PGBackendConfig) (ProvisionerBackend, error) {
+	if c.SchemaName == "" {
+		c.SchemaName = pgDefaultSchema
+	}
+	if !pgSchemaPattern.MatchString(c.SchemaName) {
+		return nil, fmt.Errorf("invalid schema name: %q", c.SchemaName)
+	}
+
+	db, err := sql.Open("postgres", c.ConnStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %v", err)
+	}
+
+	b := &ProvisionerBackendPG{
+		PGBackendConfig: c,
+		db:              db,
+	}
+
+	err = b.createTable()
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// createTable
+//
+//	Creates the schema, the state table, the state history
+//	table and their indexes if they do not already exist
+func (b *ProvisionerBackendPG) createTable() error {
+	stmts := []string{
+		fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", pq.QuoteIdentifier(b.SchemaName)),
+		"CREATE SEQUENCE IF NOT EXISTS public.global_states_id_seq AS bigint",
+		fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s (id bigint NOT NULL DEFAULT nextval('public.global_states_id_seq') PRIMARY KEY, name text UNIQUE, data text)",
+			b.table(),
+		),
+		fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS states_by_name ON %s (name)", b.table()),
+		// terraform does not keep previous states so the history table is our own
+		fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s (id bigserial PRIMARY KEY, name text NOT NULL, data text, created timestamptz NOT NULL DEFAULT now())",
+			b.historyTable(),
+		),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS states_history_by_name ON %s (name, id)", b.historyTable()),
+	}
+	for _, stmt := range stmts {
+		_, err := b.db.Exec(stmt)
+		if err != nil {
+			return fmt.Errorf("failed to create state table: %v", err)
+		}
+	}
+	return nil
+}
+
+// table
+//
+//	Returns the quoted name of the state table
+func (b *ProvisionerBackendPG) table() string {
+	return pq.QuoteIdentifier(b.SchemaName) + ".states"
+}
+
+// historyTable
+//
+//	Returns the quoted name of the state history table
+func (b *ProvisionerBackendPG) historyTable() string {
+	return pq.QuoteIdentifier(b.SchemaName) + ".states_history"
+}
+
+// workspaceName
+//
+//	Returns the name of the terraform workspace of the statefile at the
+//	passed bucket path. Statefiles are stored at states/<workspace id>
+//	so the workspace is named after the id of the gigo workspace.
+func workspaceName(bucketPath string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(bucketPath, pgStatePrefix), "/", "-")
+}
+
+// String
+//
+//	Formats the provider backend into a string.
+//	Wrapper around ToTerraform to make native Go
+//	printing easier.
+func (b *ProvisionerBackendPG) String() string {
+	// format to the terraform HCL configuration string but pass an empty value
+	// for the bucket path since we have no specific target
+	s, _ := b.ToTerraform("")
+	return s
+}
+
+// ToTerraform
+//
+//		Formats the provider backend into a Terraform
+//		compatible backend configuration that can be
+//		inserted into a terraform HCL file
+//
+//	 Args
+//			- bucketPath (string): path to terraform state inside the bucket
+//	 Returns
+//	     (string): terraform HCL compliant backend configuration
+//		 ([]string): credentials in the form of environment variables
+func (b *ProvisionerBackendPG) ToTerraform(bucketPath string) (string, []string) {
+	backend := fmt.Sprintf(
+		provisionerBackendPGTemplate,
+		b.SchemaName,
+	)
+
+	// the pg backend stores every state of a schema in the same table
+	// so the statefile is selected through the terraform workspace
+	return backend, []string{
+		fmt.Sprintf("PG_CONN_STR=%s", b.ConnStr),
+		fmt.Sprintf("TF_WORKSPACE=%s", workspaceName(bucketPath)),
+	}
+}
+
+// CreateWorkspace
+//
+//	Creates the terraform workspace of the statefile at the
+//	passed bucket path. No-op if the workspace exists.
+func (b *ProvisionerBackendPG) CreateWorkspace(bucketPath string) error {
+	_, err := b.db.Exec(
+		fmt.Sprintf("INSERT INTO %s (name, data) VALUES ($1, $2) ON CONFLICT (name) DO NOTHING", b.table()),
+		workspaceName(bucketPath), fmt.Sprintf(pgEmptyState, uuid.NewString()),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create workspace: %v", err)
+	}
+	return nil
+}
+
+// GetStatefile
+//
+//	Returns the provisioner backend's current state file
+//	for the passed bucket path
+func (b *ProvisionerBackendPG) GetStatefile(bucketPath string) (io.ReadCloser, error) {
+	var data []byte
+	err := b.db.QueryRow(
+		fmt.Sprintf("SELECT data FROM %s WHERE name = $1", b.table()),
+		workspaceName(bucketPath),
+	).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query statefile: %v", err)
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// RemoveStatefile
+//
+//	Removes the statefile and its history from the provisioner
+//	backend at the passed bucket path. Terraform does not keep
+//	a backup of states stored in postgres.
+func (b *ProvisionerBackendPG) RemoveStatefile(bucketPath string) error {
+	_, err := b.db.Exec(
+		fmt.Sprintf("DELETE FROM %s WHERE name = $1", b.table()),
+		workspaceName(bucketPath),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete state file: %v", err)
+	}
+
+	_, err = b.db.Exec(
+		fmt.Sprintf("DELETE FROM %s WHERE name = $1", b.historyTable()),
+		workspaceName(bucketPath),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete statefile history: %v", err)
+	}
+
+	return nil
+}
+
+// List
+//
+//	Lists the statefiles stored in the provisioner backend under the
+//	passed prefix. The state table does not record when a state was
+//	modified so the last modification time is never set.
+func (b *ProvisionerBackendPG) List(prefix string) ([]StatefileInfo, error) {
+	// conditionally append final slash to prefix if it was not passed
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix = prefix + "/"
+	}
+
+	// the default workspace is never used by a module
+	rows, err := b.db.Query(fmt.Sprintf("SELECT name FROM %s WHERE name != 'default' ORDER BY name", b.table()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefiles: %v", err)
+	}
+	defer rows.Close()
+
+	statefiles := make([]StatefileInfo, 0)
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan statefile: %v", err)
+		}
+
+		bucketPath := pgStatePrefix + name
+		if !strings.HasPrefix(bucketPath, prefix) {
+			continue
+		}
+
+		statefiles = append(statefiles, StatefileInfo{
+			BucketPath: bucketPath,
+		})
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefiles: %v", err)
+	}
+
+	return statefiles, nil
+}
+
+// GetLock
+//
+//	Returns the state lock held for the statefile at the passed bucket
+//	path or nil if the statefile is not locked. Terraform locks the
+//	state with a session advisory lock on the id of the state row so
+//	the lock is identified by the pid of the session that holds it.
+func (b *ProvisionerBackendPG) GetLock(bucketPath string) (*LockInfo, error) {
+	// bigint advisory locks are split into the classid and objid of pg_locks
+	var pid int64
+	var who string
+	info := LockInfo{
+		Path: bucketPath,
+	}
+	err := b.db.QueryRow(
+		fmt.Sprintf(
+			`SELECT l.pid, COALESCE(a.usename::text, '') || '@' || COALESCE(host(a.client_addr), 'local'), COALESCE(a.backend_start, now())
+			FROM pg_locks l
+			JOIN %s s ON l.classid::bigint = (s.id >> 32) AND l.objid::bigint = (s.id & 4294967295) AND l.objsubid = 1
+			LEFT JOIN pg_stat_activity a ON a.pid = l.pid
+			WHERE l.locktype = 'advisory' AND l.granted AND l.database = (SELECT oid FROM pg_database WHERE datname = current_database()) AND s.name = $1
+			LIMIT 1`,
+			b.table(),
+		),
+		workspaceName(bucketPath),
+	).Scan(&pid, &who, &info.Created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query state lock: %v", err)
+	}
+
+	info.ID = strconv.FormatInt(pid, 10)
+	info.Who = who
+	info.Info = fmt.Sprintf("advisory lock held by postgres backend %d", pid)
+
+	return &info, nil
+}
+
+// ForceUnlock
+//
+//	Removes the state lock held for the statefile at the passed bucket
+//	path. If a lock id is passed the lock is only removed if it is the
+//	current lock. No-op if the statefile is not locked. Advisory locks
+//	can only be released by their session so the session that holds
+//	the lock is terminated which requires permission to signal it.
+func (b *ProvisionerBackendPG) ForceUnlock(bucketPath string, lockID string) error {
+	info, err := b.GetLock(bucketPath)
+	if err != nil {
+		return fmt.Errorf("failed to get lock: %v", err)
+	}
+	if info == nil {
+		return nil
+	}
+
+	if lockID != "" && info.ID != lockID {
+		return ErrLockIDMismatch
+	}
+
+	var terminated bool
+	err = b.db.QueryRow("SELECT pg_terminate_backend($1)", info.ID).Scan(&terminated)
+	if err != nil {
+		return fmt.Errorf("failed to terminate lock session: %v", err)
+	}
+	if !terminated {
+		return fmt.Errorf("failed to terminate lock session %s", info.ID)
+	}
+
+	return nil
+}
+
+// ListStatefileVersions
+//
+//	Lists the versions of the statefile at the passed bucket
+//	path that can be restored ordered from newest to oldest
+func (b *ProvisionerBackendPG) ListStatefileVersions(bucketPath string) ([]StatefileVersion, error) {
+	rows, err := b.db.Query(
+		fmt.Sprintf("SELECT id, created, octet_length(data) FROM %s WHERE name = $1 ORDER BY id DESC", b.historyTable()),
+		workspaceName(bucketPath),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefile versions: %v", err)
+	}
+	defer rows.Close()
+
+	versions := make([]StatefileVersion, 0)
+	for rows.Next() {
+		var id int64
+		var size sql.NullInt64
+		var version StatefileVersion
+		err = rows.Scan(&id, &version.LastModified, &size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan statefile version: %v", err)
+		}
+		version.ID = strconv.FormatInt(id, 10)
+		version.Size = size.Int64
+		versions = append(versions, version)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefile versions: %v", err)
+	}
+
+	return versions, nil
+}
+
+// GetStatefileVersion
+//
+//	Returns a version of the statefile at the passed bucket
+//	path or nil if the version does not exist
+func (b *ProvisionerBackendPG) GetStatefileVersion(bucketPath string, versionID string) (io.ReadCloser, error) {
+	id, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid statefile version id: %q", versionID)
+	}
+
+	var data []byte
+	err = b.db.QueryRow(
+		fmt.Sprintf("SELECT data FROM %s WHERE name = $1 AND id = $2", b.historyTable()),
+		workspaceName(bucketPath), id,
+	).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query statefile version: %v", err)
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// RestoreStatefile
+//
+//	Replaces the statefile at the passed bucket path with a previous
+//	version. The workspace is recreated if it was deleted since the
+//	version was taken. ErrStatefileVersionNotFound is returned if the
+//	version does not exist.
+func (b *ProvisionerBackendPG) RestoreStatefile(bucketPath string, versionID string) error {
+	id, err := strconv.ParseInt(versionID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid statefile version id: %q", versionID)
+	}
+
+	res, err := b.db.Exec(
+		fmt.Sprintf(
+			"INSERT INTO %s (name, data) SELECT name, data FROM %s WHERE name = $1 AND id = $2 ON CONFLICT (name) DO UPDATE SET data = EXCLUDED.data",
+			b.table(), b.historyTable(),
+		),
+		workspaceName(bucketPath), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to restore statefile: %v", err)
+	}
+
+	restored, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to restore statefile: %v", err)
+	}
+	if restored == 0 {
+		return ErrStatefileVersionNotFound
+	}
+
+	return nil
+}
+
+// SnapshotStatefile
+//
+//	Copies the statefile at the passed bucket path into the history
+//	table unless it matches the newest version. The oldest versions
+//	are removed once more than the retention are kept.
+func (b *ProvisionerBackendPG) SnapshotStatefile(bucketPath string, retention int) error {
+	name := workspaceName(bucketPath)
+	_, err := b.db.Exec(
+		fmt.Sprintf(
+			`INSERT INTO %[1]s (name, data) SELECT s.name, s.data FROM %[2]s s WHERE s.name = $1
+			AND s.data IS DISTINCT FROM (SELECT h.data FROM %[1]s h WHERE h.name = $1 ORDER BY h.id DESC LIMIT 1)`,
+			b.historyTable(), b.table(),
+		),
+		name,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot statefile: %v", err)
+	}
+
+	if retention <= 0 {
+		return nil
+	}
+
+	_, err = b.db.Exec(
+		fmt.Sprintf(
+			"DELETE FROM %[1]s WHERE name = $1 AND id NOT IN (SELECT id FROM %[1]s WHERE name = $1 ORDER BY id DESC LIMIT $2)",
+			b.historyTable(),
+		),
+		name, retention,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prune statefile history: %v", err)
+	}
+
+	return nil
+}
@@ -1,10 +1,16 @@
 package backend
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/gage-technologies/gigo-lib/config"
 	"github.com/gage-technologies/gigo-lib/storage"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
 	"io"
+	"sort"
+	"strings"
 )
 
 const provisionerBackendS3Template = `backend "s3" {
@@ -31,23 +37,53 @@ const provisionerBackendS3InsecureTemplate = `backend "s3" {
 //	remote backend
 type ProvisionerBackendS3 struct {
 	config.StorageS3Config
-	insecure      bool
+	insecure bool
+	// lockfile enables terraform's native s3 state locking which
+	// writes the lock to a .tflock object beside the statefile
+	lockfile      bool
 	storageEngine *storage.MinioObjectStorage
+	// client is used for operations that require object
+	// metadata which the storage engine does not expose
+	client *minio.Client
 }
 
 // NewProvisionerBackendS3
 //
 //	Creates a new ProvisionerBackendS3 from as S3 storage configuration
-func NewProvisionerBackendS3(c config.StorageS3Config, insecureS3 bool) (ProvisionerBackend, error) {
+func NewProvisionerBackendS3(c config.StorageS3Config, insecureS3 bool, lockfile bool) (ProvisionerBackend, error) {
 	// create storage engine that correlated to provisioner backend
 	storageEngine, err := storage.CreateMinioObjectStorage(c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create storage engine: %v", err)
 	}
+
+	// create minio client options
+	opts := &minio.Options{
+		Secure: c.UseSSL,
+	}
+
+	// conditionally add access credentials
+	if c.AccessKey != "" && c.SecretKey != "" {
+		opts.Creds = credentials.NewStaticV4(c.AccessKey, c.SecretKey, "")
+	}
+
+	// conditionally add region to minio client options
+	if c.Region != "" {
+		opts.Region = c.Region
+	}
+
+	// create minio client
+	client, err := minio.New(c.Endpoint, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %v", err)
+	}
+
 	return &ProvisionerBackendS3{
 		StorageS3Config: c,
 		insecure:        insecureS3,
+		lockfile:        lockfile,
 		storageEngine:   storageEngine,
+		client:          client,
 	}, nil
 }
 
@@ -91,16 +127,24 @@ func (b *ProvisionerBackendS3) ToTerraform(bucketPath string) (string, []string)
 	if !b.UseSSL {
 		endpoint = "http://" + endpoint
 	}
-	return fmt.Sprintf(
-			template,
-			b.Bucket,
-			b.Region,
-			endpoint,
-			bucketPath,
-		), []string{
-			fmt.Sprintf("AWS_ACCESS_KEY_ID=%s", b.AccessKey),
-			fmt.Sprintf("AWS_SECRET_ACCESS_KEY=%s", b.SecretKey),
-		}
+
+	backend := fmt.Sprintf(
+		template,
+		b.Bucket,
+		b.Region,
+		endpoint,
+		bucketPath,
+	)
+
+	// the s3 backend only locks the state when it is explicitly enabled
+	if b.lockfile {
+		backend = strings.TrimSuffix(backend, "}") + "  use_lockfile = true\n}"
+	}
+
+	return backend, []string{
+		fmt.Sprintf("AWS_ACCESS_KEY_ID=%s", b.AccessKey),
+		fmt.Sprintf("AWS_SECRET_ACCESS_KEY=%s", b.SecretKey),
+	}
 }
 
 // GetStatefile
@@ -113,28 +157,289 @@ func (b *ProvisionerBackendS3) GetStatefile(bucketPath string) (io.ReadCloser, e
 
 // RemoveStatefile
 //
-//	Removes the statefile and the backup statefile (if it exists) from
-//	the provisioner backend at the passed bucket path
+//	Removes every version of the statefile and the backup statefile
+//	(if it exists) from the provisioner backend at the passed bucket
+//	path. Previous versions are removed so that no copy of the state
+//	outlives the workspace when versioning is enabled on the bucket.
 func (b *ProvisionerBackendS3) RemoveStatefile(bucketPath string) error {
-	// delete statefile
-	err := b.storageEngine.DeleteFile(bucketPath)
+	for _, key := range []string{bucketPath, bucketPath + ".backup"} {
+		err := b.removeObjectVersions(key, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeObjectVersions
+//
+//	Removes the versions and deletion markers of the object with the
+//	passed key. If keep is passed only the versions it rejects are removed.
+func (b *ProvisionerBackendS3) removeObjectVersions(key string, keep func(object minio.ObjectInfo) bool) error {
+	// create cancellable context
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// collect the versions before removing them so the listing is not modified
+	versions := make([]minio.ObjectInfo, 0)
+	objects := b.client.ListObjects(ctx, b.Bucket, minio.ListObjectsOptions{
+		Prefix:       key,
+		Recursive:    true,
+		WithVersions: true,
+	})
+	for object := range objects {
+		// handle error for object
+		if object.Err != nil {
+			return fmt.Errorf("failed to list versions of %s: %v", key, object.Err)
+		}
+
+		// skip objects sharing the prefix
+		if object.Key != key {
+			continue
+		}
+
+		if keep != nil && keep(object) {
+			continue
+		}
+		versions = append(versions, object)
+	}
+
+	for _, object := range versions {
+		err := b.client.RemoveObject(ctx, b.Bucket, key, minio.RemoveObjectOptions{
+			VersionID: object.VersionID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete version %s of %s: %v", object.VersionID, key, err)
+		}
+	}
+
+	return nil
+}
+
+// List
+//
+//	Lists the statefiles stored in the provisioner backend
+//	under the passed prefix. Backup and lock files are excluded.
+func (b *ProvisionerBackendS3) List(prefix string) ([]StatefileInfo, error) {
+	// create cancellable context
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// conditionally append final slash to prefix if it was not passed
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix = prefix + "/"
+	}
+
+	// iterate the objects under the prefix
+	statefiles := make([]StatefileInfo, 0)
+	objects := b.client.ListObjects(ctx, b.Bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true})
+	for object := range objects {
+		// handle error for object
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list statefiles: %v", object.Err)
+		}
+
+		// skip backups and lock info files
+		if strings.HasSuffix(object.Key, ".backup") || strings.HasSuffix(object.Key, ".tflock") {
+			continue
+		}
+
+		statefiles = append(statefiles, StatefileInfo{
+			BucketPath:   object.Key,
+			LastModified: object.LastModified,
+		})
+	}
+
+	return statefiles, nil
+}
+
+// GetLock
+//
+//	Returns the state lock held for the statefile at the passed
+//	bucket path or nil if the statefile is not locked. The state
+//	is never locked unless the s3 lockfile is enabled.
+func (b *ProvisionerBackendS3) GetLock(bucketPath string) (*LockInfo, error) {
+	if !b.lockfile {
+		return nil, nil
+	}
+
+	// read the lock object written beside the statefile
+	buf, err := b.storageEngine.GetFile(bucketPath + ".tflock")
 	if err != nil {
-		return fmt.Errorf("failed to delete state file: %v", err)
+		return nil, fmt.Errorf("failed to read lock file: %v", err)
+	}
+	if buf == nil {
+		return nil, nil
 	}
+	defer buf.Close()
 
-	// check for backup
-	exists, _, err := b.storageEngine.Exists(bucketPath + ".backup")
+	var info LockInfo
+	err = json.NewDecoder(buf).Decode(&info)
 	if err != nil {
-		return fmt.Errorf("failed to check for backup file: %v", err)
+		return nil, fmt.Errorf("failed to decode lock file: %v", err)
 	}
 
-	// remove backup state if it exists
-	if exists {
-		err = b.storageEngine.DeleteFile(bucketPath + ".backup")
-		if err != nil {
-			return fmt.Errorf("failed to delete backup file: %v", err)
+	return &info, nil
+}
+
+// ForceUnlock
+//
+//	Removes the state lock held for the statefile at the passed bucket
+//	path. If a lock id is passed the lock is only removed if it is the
+//	current lock. No-op if the statefile is not locked.
+func (b *ProvisionerBackendS3) ForceUnlock(bucketPath string, lockID string) error {
+	info, err := b.GetLock(bucketPath)
+	if err != nil {
+		return fmt.Errorf("failed to get lock: %v", err)
+	}
+	if info == nil {
+		return nil
+	}
+
+	if lockID != "" && info.ID != lockID {
+		return ErrLockIDMismatch
+	}
+
+	err = b.storageEngine.DeleteFile(bucketPath + ".tflock")
+	if err != nil {
+		return fmt.Errorf("failed to delete lock file: %v", err)
+	}
+
+	return nil
+}
+
+// isS3VersionNotFound
+//
+//	Returns true if the error is returned by s3 for a
+//	version or object that does not exist
+func isS3VersionNotFound(err error) bool {
+	code := minio.ToErrorResponse(err).Code
+	return code == "NoSuchVersion" || code == "NoSuchKey"
+}
+
+// ListStatefileVersions
+//
+//	Lists the versions of the statefile at the passed bucket path
+//	ordered from newest to oldest including the current version.
+//	Previous versions are only kept if versioning is enabled on
+//	the bucket.
+func (b *ProvisionerBackendS3) ListStatefileVersions(bucketPath string) ([]StatefileVersion, error) {
+	// create cancellable context
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	versions := make([]StatefileVersion, 0)
+	objects := b.client.ListObjects(ctx, b.Bucket, minio.ListObjectsOptions{
+		Prefix:       bucketPath,
+		Recursive:    true,
+		WithVersions: true,
+	})
+	for object := range objects {
+		// handle error for object
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list statefile versions: %v", object.Err)
 		}
+
+		// skip statefiles sharing the prefix and the markers left by deletions
+		if object.Key != bucketPath || object.IsDeleteMarker {
+			continue
+		}
+
+		versions = append(versions, StatefileVersion{
+			ID:           object.VersionID,
+			LastModified: object.LastModified,
+			Size:         object.Size,
+		})
+	}
+
+	// versions of a key are listed newest first but we do not rely on it
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].LastModified.After(versions[j].LastModified)
+	})
+
+	return versions, nil
+}
+
+// GetStatefileVersion
+//
+//	Returns a version of the statefile at the passed bucket
+//	path or nil if the version does not exist
+func (b *ProvisionerBackendS3) GetStatefileVersion(bucketPath string, versionID string) (io.ReadCloser, error) {
+	object, err := b.client.GetObject(context.Background(), b.Bucket, bucketPath, minio.GetObjectOptions{
+		VersionID: versionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statefile version: %v", err)
+	}
+
+	// the object is retrieved lazily so we stat it to surface a missing version
+	_, err = object.Stat()
+	if err != nil {
+		_ = object.Close()
+		if isS3VersionNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get statefile version: %v", err)
+	}
+
+	return object, nil
+}
+
+// RestoreStatefile
+//
+//	Replaces the statefile at the passed bucket path with a previous
+//	version. The version is copied on top of the statefile so that the
+//	replaced state is kept as a version of its own. ErrStatefileVersionNotFound
+//	is returned if the version does not exist.
+func (b *ProvisionerBackendS3) RestoreStatefile(bucketPath string, versionID string) error {
+	_, err := b.client.CopyObject(
+		context.Background(),
+		minio.CopyDestOptions{
+			Bucket: b.Bucket,
+			Object: bucketPath,
+		},
+		minio.CopySrcOptions{
+			Bucket:    b.Bucket,
+			Object:    bucketPath,
+			VersionID: versionID,
+		},
+	)
+	if err != nil {
+		if isS3VersionNotFound(err) {
+			return ErrStatefileVersionNotFound
+		}
+		return fmt.Errorf("failed to copy statefile version: %v", err)
 	}
 
 	return nil
 }
+
+// PruneStatefileVersions
+//
+//	Removes the oldest versions of the statefile at the passed bucket
+//	path once more than the retention are kept. The current version
+//	is always kept since the versions are ordered newest first.
+func (b *ProvisionerBackendS3) PruneStatefileVersions(bucketPath string, retention int) error {
+	if retention <= 0 {
+		return nil
+	}
+
+	versions, err := b.ListStatefileVersions(bucketPath)
+	if err != nil {
+		return err
+	}
+	if len(versions) <= retention {
+		return nil
+	}
+
+	kept := make(map[string]struct{}, retention)
+	for _, version := range versions[:retention] {
+		kept[version.ID] = struct{}{}
+	}
+
+	// deletion markers are left to the lifecycle of the bucket
+	return b.removeObjectVersions(bucketPath, func(object minio.ObjectInfo) bool {
+		_, ok := kept[object.VersionID]
+		return ok || object.IsDeleteMarker
+	})
+}
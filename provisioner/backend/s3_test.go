@@ -13,7 +13,7 @@ func TestNewProvisionerBackendS3(t *testing.T) {
 		Endpoint:  "127.0.0.1:9000",
 		SecretKey: "test",
 		AccessKey: "test",
-	}, true)
+	}, true, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -30,7 +30,7 @@ func TestProvisionerBackendS3_String(t *testing.T) {
 		Endpoint:  "127.0.0.1:9000",
 		SecretKey: "test",
 		AccessKey: "test",
-	}, true)
+	}, true, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -54,7 +54,7 @@ func TestProvisionerBackendS3_ToTerraform(t *testing.T) {
 		Endpoint:  "127.0.0.1:9000",
 		SecretKey: "test",
 		AccessKey: "test",
-	}, false)
+	}, false, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,299 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	etcd "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrStateLocked is returned when a state lock is held by another operation
+var ErrStateLocked = errors.New("state is locked by another operation")
+
+// StateLocker
+//
+//	Stores the terraform state locks of backends that implement
+//	locking themselves instead of relying on the storage engine
+type StateLocker interface {
+	// Lock
+	//
+	//  Acquires the lock of the statefile at the passed bucket path. If
+	//  the lock is held ErrStateLocked is returned with the current lock.
+	Lock(ctx context.Context, bucketPath string, info LockInfo) (*LockInfo, error)
+
+	// Unlock
+	//
+	//  Releases the lock of the statefile at the passed bucket path. If a
+	//  lock id is passed the lock is only released if it is the current
+	//  lock. No-op if the statefile is not locked.
+	Unlock(ctx context.Context, bucketPath string, lockID string) error
+
+	// Get
+	//
+	//  Returns the lock of the statefile at the passed bucket
+	//  path or nil if the statefile is not locked
+	Get(ctx context.Context, bucketPath string) (*LockInfo, error)
+
+	// Close
+	//
+	//  Releases all resources held by the locker
+	Close() error
+}
+
+// MemoryStateLocker
+//
+//	Single node implementation of StateLocker that holds the locks in memory
+type MemoryStateLocker struct {
+	mu    sync.Mutex
+	locks map[string]LockInfo
+}
+
+// NewMemoryStateLocker
+//
+//	Creates a new MemoryStateLocker
+func NewMemoryStateLocker() *MemoryStateLocker {
+	return &MemoryStateLocker{
+		locks: make(map[string]LockInfo),
+	}
+}
+
+// Lock
+//
+//	Acquires the lock of the statefile at the passed bucket path. If
+//	the lock is held ErrStateLocked is returned with the current lock.
+func (l *MemoryStateLocker) Lock(ctx context.Context, bucketPath string, info LockInfo) (*LockInfo, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if holder, ok := l.locks[bucketPath]; ok {
+		return &holder, ErrStateLocked
+	}
+
+	l.locks[bucketPath] = info
+	return nil, nil
+}
+
+// Unlock
+//
+//	Releases the lock of the statefile at the passed bucket path. If a
+//	lock id is passed the lock is only released if it is the current
+//	lock. No-op if the statefile is not locked.
+func (l *MemoryStateLocker) Unlock(ctx context.Context, bucketPath string, lockID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	holder, ok := l.locks[bucketPath]
+	if !ok {
+		return nil
+	}
+
+	if lockID != "" && holder.ID != lockID {
+		return ErrLockIDMismatch
+	}
+
+	delete(l.locks, bucketPath)
+	return nil
+}
+
+// Get
+//
+//	Returns the lock of the statefile at the passed bucket
+//	path or nil if the statefile is not locked
+func (l *MemoryStateLocker) Get(ctx context.Context, bucketPath string) (*LockInfo, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	holder, ok := l.locks[bucketPath]
+	if !ok {
+		return nil, nil
+	}
+	return &holder, nil
+}
+
+// Close
+//
+//	No-op for the memory locker
+func (l *MemoryStateLocker) Close() error {
+	return nil
+}
+
+// EtcdStateLocker
+//
+//	Cluster wide implementation of StateLocker. Locks are bound to a
+//	session lease of this node so that the locks of the operations
+//	that were running on a crashed node expire with the node.
+type EtcdStateLocker struct {
+	prefix  string
+	ttl     time.Duration
+	client  *etcd.Client
+	mu      sync.Mutex
+	session *concurrency.Session
+}
+
+// NewEtcdStateLocker
+//
+//	Creates a new EtcdStateLocker that stores the locks under the passed prefix
+func NewEtcdStateLocker(cfg etcd.Config, prefix string, ttl time.Duration) (*EtcdStateLocker, error) {
+	if ttl < time.Second {
+		return nil, fmt.Errorf("ttl must be at least 1 second")
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	client, err := etcd.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create etcd client: %v", err)
+	}
+
+	return &EtcdStateLocker{
+		prefix: prefix,
+		ttl:    ttl,
+		client: client,
+	}, nil
+}
+
+// getSession
+//
+//	Returns the session that locks are bound to creating a
+//	new session if the previous session expired
+func (l *EtcdStateLocker) getSession() (*concurrency.Session, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.session != nil {
+		select {
+		case <-l.session.Done():
+		default:
+			return l.session, nil
+		}
+	}
+
+	session, err := concurrency.NewSession(l.client, concurrency.WithTTL(int(l.ttl.Seconds())))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %v", err)
+	}
+	l.session = session
+	return session, nil
+}
+
+// Lock
+//
+//	Acquires the lock of the statefile at the passed bucket path. If
+//	the lock is held ErrStateLocked is returned with the current lock.
+func (l *EtcdStateLocker) Lock(ctx context.Context, bucketPath string, info LockInfo) (*LockInfo, error) {
+	session, err := l.getSession()
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := json.Marshal(info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal lock info: %v", err)
+	}
+
+	// atomically create the key only if it does not exist
+	key := l.prefix + bucketPath
+	res, err := l.client.Txn(ctx).
+		If(etcd.Compare(etcd.CreateRevision(key), "=", 0)).
+		Then(etcd.OpPut(key, string(buf), etcd.WithLease(session.Lease()))).
+		Else(etcd.OpGet(key)).
+		Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute lock transaction: %v", err)
+	}
+
+	if res.Succeeded {
+		return nil, nil
+	}
+
+	// return the current holder if we lost
+	var holder LockInfo
+	if kvs := res.Responses[0].GetResponseRange().GetKvs(); len(kvs) > 0 {
+		err = json.Unmarshal(kvs[0].Value, &holder)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode lock info: %v", err)
+		}
+	}
+	return &holder, ErrStateLocked
+}
+
+// Unlock
+//
+//	Releases the lock of the statefile at the passed bucket path. If a
+//	lock id is passed the lock is only released if it is the current
+//	lock. No-op if the statefile is not locked.
+func (l *EtcdStateLocker) Unlock(ctx context.Context, bucketPath string, lockID string) error {
+	key := l.prefix + bucketPath
+	res, err := l.client.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to get lock: %v", err)
+	}
+	if len(res.Kvs) == 0 {
+		return nil
+	}
+
+	var holder LockInfo
+	err = json.Unmarshal(res.Kvs[0].Value, &holder)
+	if err != nil {
+		return fmt.Errorf("failed to decode lock info: %v", err)
+	}
+
+	if lockID != "" && holder.ID != lockID {
+		return ErrLockIDMismatch
+	}
+
+	// only delete the key if it is still the lock that we read
+	_, err = l.client.Txn(ctx).
+		If(etcd.Compare(etcd.ModRevision(key), "=", res.Kvs[0].ModRevision)).
+		Then(etcd.OpDelete(key)).
+		Commit()
+	if err != nil {
+		return fmt.Errorf("failed to execute unlock transaction: %v", err)
+	}
+
+	return nil
+}
+
+// Get
+//
+//	Returns the lock of the statefile at the passed bucket
+//	path or nil if the statefile is not locked
+func (l *EtcdStateLocker) Get(ctx context.Context, bucketPath string) (*LockInfo, error) {
+	res, err := l.client.Get(ctx, l.prefix+bucketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lock: %v", err)
+	}
+	if len(res.Kvs) == 0 {
+		return nil, nil
+	}
+
+	var holder LockInfo
+	err = json.Unmarshal(res.Kvs[0].Value, &holder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode lock info: %v", err)
+	}
+	return &holder, nil
+}
+
+// Close
+//
+//	Revokes the session of the locker releasing all locks
+//	held by this node and closes the etcd client
+func (l *EtcdStateLocker) Close() error {
+	l.mu.Lock()
+	session := l.session
+	l.session = nil
+	l.mu.Unlock()
+
+	if session != nil {
+		_ = session.Close()
+	}
+
+	return l.client.Close()
+}
@@ -0,0 +1,114 @@
+package provisioner
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"gigo-ws/models"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// planDrift
+//
+//	Subset of the json representation of a saved plan that contains
+//	the resource drift since it is not modelled by terraform-json
+type planDrift struct {
+	ResourceDrift []*tfjson.ResourceChange `json:"resource_drift"`
+}
+
+// parseDrift
+//
+//	Parses the resource drift from the json representation of a
+//	refresh-only plan. Updated resources include the paths of the
+//	attributes that no longer match the statefile.
+func parseDrift(buf []byte) ([]models.ResourceDrift, error) {
+	var plan planDrift
+	err := json.Unmarshal(buf, &plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %v", err)
+	}
+
+	drift := make([]models.ResourceDrift, 0)
+	for _, rc := range plan.ResourceDrift {
+		if rc == nil || rc.Change == nil {
+			continue
+		}
+
+		d := models.ResourceDrift{
+			Address: rc.Address,
+			Type:    rc.Type,
+		}
+		switch {
+		case rc.Change.Actions.Delete():
+			d.Action = "delete"
+		case rc.Change.Actions.Update():
+			d.Action = "update"
+			d.Attributes = diffAttributes("", rc.Change.Before, rc.Change.After)
+		default:
+			continue
+		}
+		drift = append(drift, d)
+	}
+
+	return drift, nil
+}
+
+// diffAttributes
+//
+//	Returns the paths of the attributes that differ between the before
+//	and after values of a resource change. Paths use the flatmap style
+//	of the terraform statefile, e.g. spec.0.container.0.image
+func diffAttributes(path string, before, after interface{}) []string {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+
+	switch b := before.(type) {
+	case map[string]interface{}:
+		a, ok := after.(map[string]interface{})
+		if !ok {
+			return []string{path}
+		}
+
+		// walk the union of the keys in a stable order
+		keys := make([]string, 0, len(b))
+		for k := range b {
+			keys = append(keys, k)
+		}
+		for k := range a {
+			if _, ok := b[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		diff := make([]string, 0)
+		for _, k := range keys {
+			diff = append(diff, diffAttributes(join(k), b[k], a[k])...)
+		}
+		return diff
+	case []interface{}:
+		a, ok := after.([]interface{})
+		if !ok || len(a) != len(b) {
+			return []string{path}
+		}
+
+		diff := make([]string, 0)
+		for i := range b {
+			diff = append(diff, diffAttributes(join(strconv.Itoa(i)), b[i], a[i])...)
+		}
+		return diff
+	default:
+		if reflect.DeepEqual(before, after) {
+			return nil
+		}
+		return []string{path}
+	}
+}
@@ -0,0 +1,101 @@
+package provisioner
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testRefreshOnlyPlan = `{
+  "format_version": "1.1",
+  "terraform_version": "1.3.7",
+  "resource_drift": [
+    {
+      "address": "kubernetes_pod.main[0]",
+      "mode": "managed",
+      "type": "kubernetes_pod",
+      "name": "main",
+      "index": 0,
+      "change": {
+        "actions": ["update"],
+        "before": {
+          "id": "gigo/ws-1",
+          "spec": [{"container": [{"image": "gigo/workspace:1", "name": "dev"}]}]
+        },
+        "after": {
+          "id": "gigo/ws-1",
+          "spec": [{"container": [{"image": "gigo/workspace:2", "name": "dev"}]}]
+        }
+      }
+    },
+    {
+      "address": "kubernetes_persistent_volume_claim.home",
+      "mode": "managed",
+      "type": "kubernetes_persistent_volume_claim",
+      "name": "home",
+      "change": {
+        "actions": ["update"],
+        "before": {"spec": [{"resources": [{"requests": {"storage": "10Gi"}}]}]},
+        "after": {"spec": [{"resources": [{"requests": {"storage": "20Gi"}}]}], "wait_until_bound": true}
+      }
+    },
+    {
+      "address": "kubernetes_service.main",
+      "mode": "managed",
+      "type": "kubernetes_service",
+      "name": "main",
+      "change": {
+        "actions": ["delete"],
+        "before": {"id": "gigo/ws-1"},
+        "after": null
+      }
+    },
+    {
+      "address": "coder_agent.main",
+      "mode": "managed",
+      "type": "coder_agent",
+      "name": "main",
+      "change": {
+        "actions": ["no-op"],
+        "before": {"id": "1"},
+        "after": {"id": "1"}
+      }
+    }
+  ],
+  "resource_changes": []
+}`
+
+func TestParseDrift(t *testing.T) {
+	drift, err := parseDrift([]byte(testRefreshOnlyPlan))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(drift) != 3 {
+		t.Fatalf("expected 3 drifted resources, got %+v", drift)
+	}
+
+	if drift[0].Address != "kubernetes_pod.main[0]" || drift[0].Action != "update" {
+		t.Errorf("unexpected pod drift: %+v", drift[0])
+	}
+	if !reflect.DeepEqual(drift[0].Attributes, []string{"spec.0.container.0.image"}) {
+		t.Errorf("unexpected pod attributes: %v", drift[0].Attributes)
+	}
+
+	expected := []string{"spec.0.resources.0.requests.storage", "wait_until_bound"}
+	if !reflect.DeepEqual(drift[1].Attributes, expected) {
+		t.Errorf("expected pvc attributes %v, got %v", expected, drift[1].Attributes)
+	}
+
+	if drift[2].Type != "kubernetes_service" || drift[2].Action != "delete" || len(drift[2].Attributes) != 0 {
+		t.Errorf("unexpected service drift: %+v", drift[2])
+	}
+
+	// a plan without drift reports nothing
+	drift, err = parseDrift([]byte(`{"format_version":"1.1","resource_changes":[]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drift) != 0 {
+		t.Fatalf("expected no drift, got %+v", drift)
+	}
+}
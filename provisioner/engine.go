@@ -0,0 +1,346 @@
+package provisioner
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"gigo-ws/config"
+	"gigo-ws/models"
+
+	"github.com/hashicorp/go-version"
+)
+
+// openTofuReleasesURL base url of the OpenTofu release artifacts
+const openTofuReleasesURL = "https://github.com/opentofu/opentofu/releases/download"
+
+// Engine
+//
+//	Infrastructure as code engine that executes the terraform modules.
+//	Terraform and OpenTofu share the same cli so the engines only differ
+//	in the name of their binary and how they are installed.
+type Engine interface {
+	// Type returns the type of the engine
+	Type() models.IaCEngineType
+	// Binary returns the file name of the engine binary
+	Binary() string
+	// Install installs the passed version of the engine binary into the directory
+	Install(ctx context.Context, vrs *version.Version, dir string) error
+	// Version returns the version of the engine binary at the passed path
+	Version(binary string) (*version.Version, error)
+}
+
+// NewEngine
+//
+//	Creates the Engine for the passed provisioner configuration. Engines
+//	are installed from the configured local archive when one is set.
+func NewEngine(cfg config.ProvisionerConfig) (Engine, error) {
+	var engine Engine
+	switch cfg.Engine {
+	case models.IaCEngineTerraform:
+		engine = &terraformEngine{}
+	case models.IaCEngineOpenTofu:
+		engine = &openTofuEngine{}
+	default:
+		return nil, fmt.Errorf("unknown iac engine type: %d", cfg.Engine)
+	}
+
+	if cfg.InstallArchive != "" {
+		if cfg.InstallArchiveSHA256 == "" {
+			return nil, fmt.Errorf("install archive requires a sha256 checksum")
+		}
+		engine = &archiveEngine{
+			Engine:   engine,
+			archive:  cfg.InstallArchive,
+			checksum: cfg.InstallArchiveSHA256,
+		}
+	}
+
+	return engine, nil
+}
+
+// terraformEngine
+//
+//	HashiCorp terraform installed from the official releases
+type terraformEngine struct{}
+
+func (e *terraformEngine) Type() models.IaCEngineType {
+	return models.IaCEngineTerraform
+}
+
+func (e *terraformEngine) Binary() string {
+	return "terraform"
+}
+
+func (e *terraformEngine) Install(ctx context.Context, vrs *version.Version, dir string) error {
+	return installTf(ctx, vrs, dir)
+}
+
+func (e *terraformEngine) Version(binary string) (*version.Version, error) {
+	return getTfVersion(binary)
+}
+
+// openTofuEngine
+//
+//	OpenTofu installed from the GitHub releases
+type openTofuEngine struct{}
+
+func (e *openTofuEngine) Type() models.IaCEngineType {
+	return models.IaCEngineOpenTofu
+}
+
+func (e *openTofuEngine) Binary() string {
+	return "tofu"
+}
+
+func (e *openTofuEngine) Install(ctx context.Context, vrs *version.Version, dir string) error {
+	return installTofu(ctx, vrs, dir)
+}
+
+// Version
+//
+//	OpenTofu retains the terraform_version key in its version
+//	output so the terraform version detection is compatible
+func (e *openTofuEngine) Version(binary string) (*version.Version, error) {
+	return getTfVersion(binary)
+}
+
+// archiveEngine
+//
+//	Wraps an Engine to install the binary from a local release archive
+//	instead of downloading it so that air-gapped environments can be
+//	provisioned from a pre-staged archive
+type archiveEngine struct {
+	Engine
+	archive  string
+	checksum string
+}
+
+func (e *archiveEngine) Install(_ context.Context, _ *version.Version, dir string) error {
+	return installArchive(e.archive, e.checksum, e.Binary(), dir)
+}
+
+// installTofu
+//
+//	Downloads the OpenTofu release archive for the current platform and
+//	installs it once the archive is verified against the release checksums
+func installTofu(ctx context.Context, vrs *version.Version, dir string) error {
+	vrsStr := vrs.String()
+	archiveName := fmt.Sprintf("tofu_%s_%s_%s.zip", vrsStr, runtime.GOOS, runtime.GOARCH)
+	baseURL := fmt.Sprintf("%s/v%s", openTofuReleasesURL, vrsStr)
+
+	// retrieve the checksum of the archive from the release checksums
+	sums, err := download(ctx, fmt.Sprintf("%s/tofu_%s_SHA256SUMS", baseURL, vrsStr))
+	if err != nil {
+		return fmt.Errorf("failed to download opentofu checksums: %v", err)
+	}
+	defer os.Remove(sums)
+
+	checksum, err := findChecksum(sums, archiveName)
+	if err != nil {
+		return fmt.Errorf("failed to find opentofu checksum: %v", err)
+	}
+
+	archive, err := download(ctx, fmt.Sprintf("%s/%s", baseURL, archiveName))
+	if err != nil {
+		return fmt.Errorf("failed to download opentofu release: %v", err)
+	}
+	defer os.Remove(archive)
+
+	return installArchive(archive, checksum, "tofu", dir)
+}
+
+// download
+//
+//	Downloads the passed url to a temporary file and returns the file path
+func download(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code for %s: %d", url, res.StatusCode)
+	}
+
+	f, err := os.CreateTemp("", "gigo-ws-download-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, res.Body)
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write download: %v", err)
+	}
+
+	return f.Name(), nil
+}
+
+// findChecksum
+//
+//	Retrieves the checksum of the passed file from a SHA256SUMS file
+func findChecksum(sumsPath string, name string) (string, error) {
+	f, err := os.Open(sumsPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			return fields[0], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("no checksum for %s", name)
+}
+
+// installArchive
+//
+//	Verifies the checksum of a release archive and extracts the engine
+//	binary into the passed directory. Zip and gzipped tar archives are
+//	supported since terraform and OpenTofu publish releases in both.
+func installArchive(archive string, checksum string, binary string, dir string) error {
+	// verify the archive before we extract anything from it
+	f, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %v", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return fmt.Errorf("failed to hash archive: %v", err)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, checksum) {
+		return fmt.Errorf("archive checksum mismatch: expected %s, got %s", checksum, sum)
+	}
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create install directory: %v", err)
+	}
+
+	// extract to a temporary file and rename into place so that
+	// a failed extraction never leaves a partial binary behind
+	tmp, err := os.CreateTemp(dir, fmt.Sprintf(".%s-*", binary))
+	if err != nil {
+		return fmt.Errorf("failed to create temporary binary: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	switch {
+	case strings.HasSuffix(archive, ".zip"):
+		err = extractZip(archive, binary, tmp)
+	case strings.HasSuffix(archive, ".tar.gz"), strings.HasSuffix(archive, ".tgz"):
+		_, err = f.Seek(0, io.SeekStart)
+		if err == nil {
+			err = extractTarGz(f, binary, tmp)
+		}
+	default:
+		err = fmt.Errorf("unsupported archive format: %s", filepath.Base(archive))
+	}
+	if err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to extract %s: %v", binary, err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write binary: %v", err)
+	}
+
+	err = os.Chmod(tmp.Name(), 0755)
+	if err != nil {
+		return fmt.Errorf("failed to change permissions of binary: %v", err)
+	}
+
+	err = os.Rename(tmp.Name(), filepath.Join(dir, binary))
+	if err != nil {
+		return fmt.Errorf("failed to move binary into place: %v", err)
+	}
+
+	return nil
+}
+
+// extractZip
+//
+//	Copies the binary from a zip archive into the passed writer
+func extractZip(archive string, binary string, w io.Writer) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || filepath.Base(f.Name) != binary {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		_, err = io.Copy(w, rc)
+		return err
+	}
+
+	return fmt.Errorf("binary not found in archive")
+}
+
+// extractTarGz
+//
+//	Copies the binary from a gzipped tar archive into the passed writer
+func extractTarGz(r io.Reader, binary string, w io.Writer) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return fmt.Errorf("binary not found in archive")
+		}
+		if err != nil {
+			return err
+		}
+
+		if hdr.Typeflag != tar.TypeReg || filepath.Base(hdr.Name) != binary {
+			continue
+		}
+
+		_, err = io.Copy(w, tr)
+		return err
+	}
+}
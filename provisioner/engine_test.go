@@ -0,0 +1,210 @@
+package provisioner
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gigo-ws/config"
+	"gigo-ws/models"
+)
+
+const testEngineBinary = "#!/bin/sh\necho tofu\n"
+
+func writeTestZip(t *testing.T, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, content := range map[string]string{"LICENSE": "license", "tofu": testEngineBinary} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = w.Write([]byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = zw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTestTarGz(t *testing.T, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	err = tw.WriteHeader(&tar.Header{Name: "tofu_1.6.2/", Typeflag: tar.TypeDir, Mode: 0755})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tw.WriteHeader(&tar.Header{Name: "tofu_1.6.2/tofu", Typeflag: tar.TypeReg, Mode: 0755, Size: int64(len(testEngineBinary))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tw.Write([]byte(testEngineBinary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = gw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func hashTestFile(t *testing.T, path string) string {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestInstallArchive(t *testing.T) {
+	dir, err := os.MkdirTemp("", "gigo-ws-install-archive-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name  string
+		file  string
+		write func(t *testing.T, path string)
+	}{
+		{
+			name:  "zip",
+			file:  "tofu_1.6.2_linux_amd64.zip",
+			write: writeTestZip,
+		},
+		{
+			name:  "tar.gz",
+			file:  "tofu_1.6.2_linux_amd64.tar.gz",
+			write: writeTestTarGz,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := filepath.Join(dir, tt.file)
+			tt.write(t, archive)
+			installDir := filepath.Join(dir, tt.name)
+
+			// a mismatched checksum must not install anything
+			err := installArchive(archive, strings.Repeat("0", 64), "tofu", installDir)
+			if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+				t.Fatalf("expected checksum mismatch, got %v", err)
+			}
+
+			err = installArchive(archive, hashTestFile(t, archive), "tofu", installDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			info, err := os.Stat(filepath.Join(installDir, "tofu"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm()&0100 == 0 {
+				t.Fatalf("expected binary to be executable, got %s", info.Mode())
+			}
+
+			buf, err := os.ReadFile(filepath.Join(installDir, "tofu"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(buf) != testEngineBinary {
+				t.Fatalf("unexpected binary content: %q", buf)
+			}
+
+			// the archive does not contain a terraform binary
+			err = installArchive(archive, hashTestFile(t, archive), "terraform", installDir)
+			if err == nil {
+				t.Fatal("expected error for missing binary")
+			}
+		})
+	}
+}
+
+func TestFindChecksum(t *testing.T) {
+	f, err := os.CreateTemp("", "gigo-ws-sha256sums-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString("aaaa  tofu_1.6.2_darwin_arm64.zip\nbbbb  tofu_1.6.2_linux_amd64.zip\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	sum, err := findChecksum(f.Name(), "tofu_1.6.2_linux_amd64.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != "bbbb" {
+		t.Fatalf("expected bbbb, got %s", sum)
+	}
+
+	_, err = findChecksum(f.Name(), "tofu_1.6.2_windows_amd64.zip")
+	if err == nil {
+		t.Fatal("expected error for missing checksum")
+	}
+}
+
+func TestNewEngine(t *testing.T) {
+	engine, err := NewEngine(config.ProvisionerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if engine.Type() != models.IaCEngineTerraform || engine.Binary() != "terraform" {
+		t.Fatalf("expected terraform engine by default, got %s", engine.Binary())
+	}
+
+	engine, err = NewEngine(config.ProvisionerConfig{
+		Engine:               models.IaCEngineOpenTofu,
+		InstallArchive:       "/tmp/tofu.zip",
+		InstallArchiveSHA256: "abcd",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := engine.(*archiveEngine); !ok {
+		t.Fatalf("expected archive engine, got %T", engine)
+	}
+	if engine.Type() != models.IaCEngineOpenTofu || engine.Binary() != "tofu" {
+		t.Fatalf("expected opentofu engine, got %s", engine.Binary())
+	}
+
+	_, err = NewEngine(config.ProvisionerConfig{InstallArchive: "/tmp/terraform.zip"})
+	if err == nil {
+		t.Fatal("expected error for archive without checksum")
+	}
+
+	_, err = NewEngine(config.ProvisionerConfig{Engine: 69})
+	if err == nil {
+		t.Fatal("expected error for unknown engine")
+	}
+}
@@ -1,13 +1,209 @@
 package provisioner
 
-type ResultParseError struct {
-	s string
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gigo-ws/models"
+	"gigo-ws/provisioner/tfevent"
+	utils2 "gigo-ws/utils"
+)
+
+// maxErrorStderrLines is the number of trailing stderr lines included in
+// the message of a TerraformError that carries no error diagnostics
+const maxErrorStderrLines = 10
+
+// stateLockSummary summary of the diagnostic terraform emits when the state is locked by another operation
+const stateLockSummary = "Error acquiring the state lock"
+
+// lockIDRegex extracts the lock id from the lock info printed with a state lock conflict
+var lockIDRegex = regexp.MustCompile(`(?m)^\s*ID:\s+(\S+)`)
+
+// ErrStateLocked is matched by a TerraformError for an operation that failed to acquire the state lock
+var ErrStateLocked = errors.New("terraform state is locked")
+
+type FailureKind int
+
+const (
+	// FailureInit terraform failed to initialize the module
+	FailureInit FailureKind = iota
+	// FailureValidation the module configuration is invalid
+	FailureValidation
+	// FailureProvisioning terraform failed to create, update or destroy resources
+	FailureProvisioning
+	// FailureStateLocked the state is locked by another terraform operation
+	FailureStateLocked
+)
+
+func (k FailureKind) String() string {
+	switch k {
+	case FailureInit:
+		return "Init"
+	case FailureValidation:
+		return "Validation"
+	case FailureProvisioning:
+		return "Provisioning"
+	case FailureStateLocked:
+		return "StateLocked"
+	default:
+		return "Unknown"
+	}
+}
+
+// TerraformError
+//
+//	Error returned when a terraform command exits with a non-zero
+//	exit code. The error diagnostics emitted by terraform are used
+//	to summarize the failure instead of the full command output.
+type TerraformError struct {
+	Kind        FailureKind
+	Operation   string
+	Result      *utils2.CommandResult
+	Diagnostics []tfevent.Diagnostic
+	// LockID id of the conflicting state lock for FailureStateLocked
+	LockID string
+	// Log holds the full output of the failed command
+	Log *models.OperationLog
+}
+
+// newTerraformError
+//
+//	Creates a TerraformError for a failed terraform operation
+//	classifying the failure using the events of the operation
+func newTerraformError(operation string, res *utils2.CommandResult, log *models.OperationLog, events []tfevent.Event) *TerraformError {
+	tfErr := &TerraformError{
+		Kind:        classifyFailure(operation, events),
+		Operation:   operation,
+		Result:      res,
+		Diagnostics: tfevent.Diagnostics(events, "error"),
+		Log:         log,
+	}
+
+	// a lock conflict takes precedence since the operation never ran
+	if lockID, ok := stateLockConflict(res, tfErr.Diagnostics); ok {
+		tfErr.Kind = FailureStateLocked
+		tfErr.LockID = lockID
+	}
+
+	return tfErr
+}
+
+// stateLockConflict
+//
+//	Determines if a terraform operation failed to acquire the state
+//	lock and returns the id of the conflicting lock. The conflict is
+//	reported as a diagnostic for json output and on stderr otherwise.
+func stateLockConflict(res *utils2.CommandResult, diags []tfevent.Diagnostic) (string, bool) {
+	for _, d := range diags {
+		if d.Summary == stateLockSummary {
+			return parseLockID(d.Detail), true
+		}
+	}
+
+	if res != nil && strings.Contains(res.Stderr, stateLockSummary) {
+		return parseLockID(res.Stderr), true
+	}
+
+	return "", false
+}
+
+// parseLockID
+//
+//	Extracts the lock id from the lock info printed by terraform
+func parseLockID(s string) string {
+	m := lockIDRegex.FindStringSubmatch(s)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// classifyFailure
+//
+//	Determines the kind of failure of a terraform operation. A failure
+//	after terraform began to modify resources is a provisioning failure
+//	while error diagnostics that reference the configuration before that
+//	point indicate an invalid module.
+func classifyFailure(operation string, events []tfevent.Event) FailureKind {
+	switch operation {
+	case "init":
+		return FailureInit
+	case "validate":
+		return FailureValidation
+	}
+
+	for _, e := range events {
+		switch {
+		case e.Type == tfevent.TypeApplyStart, e.Type == tfevent.TypeApplyErrored:
+			return FailureProvisioning
+		case e.IsError() && e.Diagnostic.Range != nil:
+			return FailureValidation
+		}
+	}
+
+	return FailureProvisioning
 }
 
-func NewResultParseError(s string) error {
-	return &ResultParseError{s: s}
+func (e *TerraformError) Error() string {
+	exitCode := -1
+	if e.Result != nil {
+		exitCode = e.Result.ExitCode
+	}
+
+	msg := fmt.Sprintf("terraform %s failed with exit code %d", e.Operation, exitCode)
+
+	// reference the log so that the full output can be retrieved
+	if e.Log != nil {
+		msg = fmt.Sprintf("%s (operation log %s)", msg, e.Log.ID)
+	}
+
+	// summarize the failure with the error diagnostics
+	if len(e.Diagnostics) > 0 {
+		summaries := make([]string, 0, len(e.Diagnostics))
+		for _, d := range e.Diagnostics {
+			summary := d.Summary
+			if d.Address != "" {
+				summary = fmt.Sprintf("%s: %s", d.Address, summary)
+			}
+			if d.Detail != "" {
+				summary = fmt.Sprintf("%s: %s", summary, d.Detail)
+			}
+			summaries = append(summaries, summary)
+		}
+		return fmt.Sprintf("%s: %s", msg, strings.Join(summaries, "; "))
+	}
+
+	// fallback on the tail of stderr for operations that do not emit diagnostics
+	if tail := e.StderrTail(maxErrorStderrLines); tail != "" {
+		return fmt.Sprintf("%s:\n%s", msg, tail)
+	}
+
+	return msg
+}
+
+// Unwrap
+//
+//	Allows callers to match state lock conflicts with errors.Is(err, ErrStateLocked)
+func (e *TerraformError) Unwrap() error {
+	if e.Kind == FailureStateLocked {
+		return ErrStateLocked
+	}
+	return nil
 }
 
-func (e *ResultParseError) Error() string {
-	return e.s
+// StderrTail
+//
+//	Returns the last n lines of the stderr of the failed command
+func (e *TerraformError) StderrTail(n int) string {
+	if e.Result == nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimSpace(e.Result.Stderr), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
 }
@@ -0,0 +1,164 @@
+package provisioner
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gigo-ws/provisioner/tfevent"
+	utils2 "gigo-ws/utils"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	configDiag := tfevent.Event{
+		Type: tfevent.TypeDiagnostic,
+		Diagnostic: &tfevent.Diagnostic{
+			Severity: "error",
+			Summary:  "Unsupported argument",
+			Range:    &tfevent.Range{Filename: "main.tf"},
+		},
+	}
+	runtimeDiag := tfevent.Event{
+		Type: tfevent.TypeDiagnostic,
+		Diagnostic: &tfevent.Diagnostic{
+			Severity: "error",
+			Summary:  "failed to create pod",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		operation string
+		events    []tfevent.Event
+		kind      FailureKind
+	}{
+		{
+			name:      "init",
+			operation: "init",
+			kind:      FailureInit,
+		},
+		{
+			name:      "validate",
+			operation: "validate",
+			kind:      FailureValidation,
+		},
+		{
+			name:      "configuration error",
+			operation: "apply",
+			events:    []tfevent.Event{configDiag},
+			kind:      FailureValidation,
+		},
+		{
+			name:      "configuration error after apply started",
+			operation: "apply",
+			events:    []tfevent.Event{{Type: tfevent.TypeApplyStart}, configDiag},
+			kind:      FailureProvisioning,
+		},
+		{
+			name:      "runtime error",
+			operation: "destroy",
+			events:    []tfevent.Event{runtimeDiag},
+			kind:      FailureProvisioning,
+		},
+		{
+			name:      "no events",
+			operation: "apply",
+			kind:      FailureProvisioning,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kind := classifyFailure(test.operation, test.events)
+			if kind != test.kind {
+				t.Fatalf("expected %s, got %s", test.kind, kind)
+			}
+		})
+	}
+}
+
+func TestTerraformErrorMessage(t *testing.T) {
+	res := &utils2.CommandResult{
+		ExitCode: 1,
+		Stderr:   strings.Repeat("noise\n", 100) + "last line\n",
+	}
+
+	// diagnostics are preferred over the command output
+	err := newTerraformError("apply", res, nil, []tfevent.Event{
+		{
+			Type: tfevent.TypeDiagnostic,
+			Diagnostic: &tfevent.Diagnostic{
+				Severity: "error",
+				Summary:  "failed to create pod",
+				Detail:   "quota exceeded",
+				Address:  "kubernetes_pod.main[0]",
+			},
+		},
+	})
+	expected := "terraform apply failed with exit code 1: kubernetes_pod.main[0]: failed to create pod: quota exceeded"
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+
+	// the stderr tail is used when there are no diagnostics
+	err = newTerraformError("init", res, nil, nil)
+	msg := err.Error()
+	if !strings.HasSuffix(msg, "last line") {
+		t.Fatalf("expected message to end with the last stderr line, got %q", msg)
+	}
+	if strings.Count(msg, "\n") != maxErrorStderrLines {
+		t.Fatalf("expected %d stderr lines, got %q", maxErrorStderrLines, msg)
+	}
+}
+
+func TestTerraformErrorStateLocked(t *testing.T) {
+	detail := `Error message: ConditionalCheckFailedException: The conditional request failed
+Lock Info:
+  ID:        6c4ba3f1-7e41-7e2b-4c5d-d9b1c0e1c2a3
+  Path:      gigo-provisioner/states/1
+  Operation: OperationTypeApply
+  Who:       root@gigo-ws-0
+  Version:   1.3.7
+  Created:   2023-01-31 18:00:00.000000 +0000 UTC
+  Info:
+
+Terraform acquires a state lock to protect the state from being written
+by multiple users at the same time.`
+
+	// json output reports the conflict as a diagnostic
+	err := newTerraformError("apply", &utils2.CommandResult{ExitCode: 1}, nil, []tfevent.Event{
+		{
+			Type: tfevent.TypeDiagnostic,
+			Diagnostic: &tfevent.Diagnostic{
+				Severity: "error",
+				Summary:  stateLockSummary,
+				Detail:   detail,
+			},
+		},
+	})
+	if err.Kind != FailureStateLocked {
+		t.Fatalf("expected %s, got %s", FailureStateLocked, err.Kind)
+	}
+	if err.LockID != "6c4ba3f1-7e41-7e2b-4c5d-d9b1c0e1c2a3" {
+		t.Fatalf("unexpected lock id: %q", err.LockID)
+	}
+	if !errors.Is(fmt.Errorf("failed to apply configuration: %w", err), ErrStateLocked) {
+		t.Fatal("expected error to match ErrStateLocked")
+	}
+
+	// plain output reports the conflict on stderr
+	err = newTerraformError("init", &utils2.CommandResult{
+		ExitCode: 1,
+		Stderr:   "\nError: " + stateLockSummary + "\n\n" + detail,
+	}, nil, nil)
+	if err.Kind != FailureStateLocked || err.LockID != "6c4ba3f1-7e41-7e2b-4c5d-d9b1c0e1c2a3" {
+		t.Fatalf("unexpected state lock error: %s %q", err.Kind, err.LockID)
+	}
+
+	// other failures do not match
+	err = newTerraformError("apply", &utils2.CommandResult{ExitCode: 1, Stderr: "Error: failed to create pod"}, nil, nil)
+	if errors.Is(err, ErrStateLocked) {
+		t.Fatal("expected error to not match ErrStateLocked")
+	}
+}
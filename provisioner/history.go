@@ -0,0 +1,69 @@
+package provisioner
+
+import (
+	"fmt"
+
+	"gigo-ws/encryption"
+	"gigo-ws/provisioner/backend"
+
+	"github.com/gage-technologies/gigo-lib/storage"
+)
+
+// defaultStateRetention is the number of statefile versions kept
+// per workspace when no retention is configured
+const defaultStateRetention = 20
+
+// snapshotStatefile
+//
+//	Copies the statefile at the passed bucket path into the history of
+//	the backend if the backend does not version statefiles itself and
+//	prunes the versions of backends that do
+func (p *Provisioner) snapshotStatefile(bucketPath string) error {
+	retention := p.stateRetention
+	if retention <= 0 {
+		retention = defaultStateRetention
+	}
+
+	switch b := p.Backend.(type) {
+	case backend.HistoryProvisionerBackend:
+		return b.SnapshotStatefile(bucketPath, retention)
+	case backend.VersionedProvisionerBackend:
+		return b.PruneStatefileVersions(bucketPath, retention)
+	}
+	return nil
+}
+
+// RestoreStatefile
+//
+//	Replaces the statefile of a workspace with a previous version. The
+//	current statefile is snapshotted first so that the restore itself
+//	can be rolled back. The caller must ensure that no operation is
+//	running for the workspace.
+func (p *Provisioner) RestoreStatefile(workspaceId int64, versionID string) error {
+	bucketPath := fmt.Sprintf("states/%d", workspaceId)
+
+	err := p.snapshotStatefile(bucketPath)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot current statefile: %v", err)
+	}
+
+	err = p.Backend.RestoreStatefile(bucketPath, versionID)
+	if err != nil {
+		return fmt.Errorf("failed to restore statefile: %w", err)
+	}
+
+	return nil
+}
+
+// rewrapFSStatefiles
+//
+//	Seals the data keys of the statefiles and statefile versions kept
+//	by the fs backend with the primary key of the keyring so that
+//	retired keys are no longer needed to read a state
+func rewrapFSStatefiles(root string, keyring *encryption.Keyring) (int, error) {
+	storageEngine, err := storage.CreateFileSystemStorage(root)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create storage engine: %v", err)
+	}
+	return encryption.NewStorage(storageEngine, keyring).Rewrap("")
+}
@@ -0,0 +1,307 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"gigo-ws/models"
+	utils2 "gigo-ws/utils"
+)
+
+const (
+	// defaultLogTailSize is the number of bytes of stderr retained in
+	// memory for a terraform command when no tail size is configured
+	defaultLogTailSize = 64 * 1024
+
+	// defaultLogRetention is the number of operation logs kept per
+	// workspace when no retention is configured
+	defaultLogRetention = 20
+)
+
+// ErrOperationLogNotFound is returned when an operation log does not exist
+var ErrOperationLogNotFound = errors.New("operation log not found")
+
+// operationLog
+//
+//	Log of a single terraform command. Stderr is streamed into a local
+//	file that is uploaded to module storage once the command exits while
+//	only the tail is retained in memory to summarize a failure.
+type operationLog struct {
+	ref  *models.OperationLog
+	file *os.File
+	tail *utils2.LineRingBuffer
+	err  error
+}
+
+// writeLine
+//
+//	Writes a line of stderr to the log
+func (l *operationLog) writeLine(line string) {
+	l.tail.WriteLine(line)
+
+	// stop writing to the file after the first failure - the log
+	// is discarded but the operation must not fail because of it
+	if l.file == nil || l.err != nil {
+		return
+	}
+	_, l.err = l.file.WriteString(line + "\n")
+}
+
+// startOperationLog
+//
+//	Creates the log of a terraform command for the module. The log is
+//	only retained in memory if the local log file cannot be created.
+func (p *Provisioner) startOperationLog(module *models.TerraformModule, operation string) *operationLog {
+	l := &operationLog{
+		ref:  models.NewOperationLog(module.ModuleID, operation, time.Now()),
+		tail: utils2.NewLineRingBuffer(p.logTailSize),
+	}
+
+	if p.storage == nil {
+		return l
+	}
+
+	file, err := os.CreateTemp("", "gigo-ws-oplog-*")
+	if err != nil {
+		p.logger.Warnf("failed to create operation log for module %d: %v", module.ModuleID, err)
+		return l
+	}
+	_ = file.Close()
+
+	// terraform appends its internal logs to the same file via TF_LOG_PATH
+	// so we open the file in append mode to interleave the writes safely
+	l.file, err = os.OpenFile(file.Name(), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		p.logger.Warnf("failed to open operation log for module %d: %v", module.ModuleID, err)
+		_ = os.Remove(file.Name())
+		return l
+	}
+
+	return l
+}
+
+// finishOperationLog
+//
+//	Uploads the log of a terraform command to module storage and prunes
+//	the oldest logs of the module beyond the retention. The reference of
+//	the stored log is returned or nil if the log could not be stored.
+func (p *Provisioner) finishOperationLog(l *operationLog) *models.OperationLog {
+	if l.file == nil {
+		return nil
+	}
+	defer os.Remove(l.file.Name())
+
+	err := l.file.Close()
+	if err == nil {
+		err = l.err
+	}
+	if err != nil {
+		p.logger.Warnf("failed to write operation log %s: %v", l.ref.Path(), err)
+		return nil
+	}
+
+	err = p.storeOperationLog(l)
+	if err != nil {
+		p.logger.Warnf("failed to store operation log %s: %v", l.ref.Path(), err)
+		return nil
+	}
+
+	err = p.pruneOperationLogs(l.ref.WorkspaceID)
+	if err != nil {
+		p.logger.Warnf("failed to prune operation logs of module %d: %v", l.ref.WorkspaceID, err)
+	}
+
+	return l.ref
+}
+
+// storeOperationLog
+//
+//	Streams the local log file into module storage
+func (p *Provisioner) storeOperationLog(l *operationLog) error {
+	file, err := os.Open(l.file.Name())
+	if err != nil {
+		return fmt.Errorf("failed to open log: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat log: %v", err)
+	}
+
+	return p.storage.CreateFileStreamed(l.ref.Path(), info.Size(), file)
+}
+
+// pruneOperationLogs
+//
+//	Deletes the oldest operation logs of the module beyond the retention
+func (p *Provisioner) pruneOperationLogs(moduleId int64) error {
+	logs, err := p.OperationLogs(moduleId)
+	if err != nil {
+		return err
+	}
+
+	if len(logs) <= p.logRetention {
+		return nil
+	}
+
+	for _, l := range logs[:len(logs)-p.logRetention] {
+		err = p.storage.DeleteFile(l.Path())
+		if err != nil {
+			return fmt.Errorf("failed to delete operation log %s: %v", l.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// operationEnvironment
+//
+//	Returns the environment of the module for a command writing to the
+//	operation log. The internal terraform logs are written to the log
+//	file instead of stderr so that the error summary only contains the
+//	output of terraform itself.
+func (p *Provisioner) operationEnvironment(module *models.TerraformModule, l *operationLog) []string {
+	env := p.environment(module)
+	if l.file != nil && p.logLevel != "" {
+		env = append(env, "TF_LOG="+p.logLevel, "TF_LOG_PATH="+l.file.Name())
+	}
+	return env
+}
+
+// runCommand
+//
+//	Executes a terraform command for the module streaming its stderr into
+//	an operation log. The returned result carries the tail of stderr and
+//	the log is returned so that a failure can reference the full output.
+func (p *Provisioner) runCommand(ctx context.Context, module *models.TerraformModule, operation string, command string) (*utils2.CommandResult, *models.OperationLog, error) {
+	l := p.startOperationLog(module, operation)
+
+	res, err := utils2.ExecuteCommandStderr(ctx, p.operationEnvironment(module, l), "", l.writeLine, "sh", "-c", command)
+
+	// the log is stored even for an interrupted command since it
+	// is the only record of how far the operation progressed
+	ref := p.finishOperationLog(l)
+	if err != nil {
+		return nil, ref, err
+	}
+
+	res.Stderr = l.tail.String()
+
+	return res, ref, nil
+}
+
+// OperationLogs
+//
+//	Lists the stored operation logs of the module ordered from oldest to newest
+func (p *Provisioner) OperationLogs(moduleId int64) ([]*models.OperationLog, error) {
+	if p.storage == nil {
+		return []*models.OperationLog{}, nil
+	}
+
+	files, err := p.storage.ListDir(models.OperationLogDir(moduleId), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operation logs: %v", err)
+	}
+
+	logs := make([]*models.OperationLog, 0, len(files))
+	for _, f := range files {
+		l, err := models.ParseOperationLog(moduleId, path.Base(f))
+		if err != nil {
+			continue
+		}
+		logs = append(logs, l)
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].StartedAt.Before(logs[j].StartedAt)
+	})
+
+	return logs, nil
+}
+
+// ReadOperationLog
+//
+//	Reads the operation log of the module returning at most the trailing
+//	limit bytes of the log and whether the log was truncated to the limit
+func (p *Provisioner) ReadOperationLog(moduleId int64, id string, limit int) ([]byte, bool, error) {
+	l, err := models.ParseOperationLog(moduleId, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if p.storage == nil {
+		return nil, false, ErrOperationLogNotFound
+	}
+
+	reader, err := p.storage.GetFile(l.Path())
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read operation log: %v", err)
+	}
+	if reader == nil {
+		return nil, false, ErrOperationLogNotFound
+	}
+	defer reader.Close()
+
+	return readTail(reader, limit)
+}
+
+// readTail
+//
+//	Reads the trailing limit bytes of the reader without buffering
+//	the full contents in memory
+func readTail(reader io.Reader, limit int) ([]byte, bool, error) {
+	buf := make([]byte, 0, limit)
+	chunk := make([]byte, 32*1024)
+	truncated := false
+
+	for {
+		n, err := reader.Read(chunk)
+		if n > 0 {
+			buf = append(buf, chunk[:n]...)
+			if len(buf) > limit {
+				// shift the tail to the front so that the buffer never
+				// holds more than the limit plus a single chunk
+				copy(buf, buf[len(buf)-limit:])
+				buf = buf[:limit]
+				truncated = true
+			}
+		}
+		if err == io.EOF {
+			return buf, truncated, nil
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read operation log: %v", err)
+		}
+	}
+}
+
+// DeleteOperationLogs
+//
+//	Deletes all stored operation logs of the module
+func (p *Provisioner) DeleteOperationLogs(moduleId int64) error {
+	if p.storage == nil {
+		return nil
+	}
+
+	exists, _, err := p.storage.Exists(models.OperationLogDir(moduleId))
+	if err != nil {
+		return fmt.Errorf("failed to check operation logs: %v", err)
+	}
+	if !exists {
+		return nil
+	}
+
+	err = p.storage.DeleteDir(models.OperationLogDir(moduleId), true)
+	if err != nil {
+		return fmt.Errorf("failed to delete operation logs: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gigo-ws/models"
+
+	"github.com/gage-technologies/gigo-lib/logging"
+	"github.com/gage-technologies/gigo-lib/storage"
+)
+
+func TestOperationLogs(t *testing.T) {
+	dir, err := os.MkdirTemp("", "gigo-ws-oplog-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	logger, err := logging.CreateBasicLogger(logging.NewDefaultBasicLoggerOptions(filepath.Join(dir, "test.log")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storageEngine, err := storage.CreateFileSystemStorage(filepath.Join(dir, "storage"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Provisioner{
+		storage:      storageEngine,
+		logTailSize:  16,
+		logRetention: 2,
+		logger:       logger,
+	}
+	module := &models.TerraformModule{ModuleID: 42}
+
+	// the full stderr is stored while only the tail is kept in memory
+	res, oplog, err := p.runCommand(
+		context.Background(), module, "apply",
+		"echo '{}'; for i in $(seq 1 100); do echo line $i 1>&2; done; exit 1",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oplog == nil || oplog.Operation != "apply" {
+		t.Fatalf("expected apply operation log, got %+v", oplog)
+	}
+	if res.Stdout != "{}" || res.ExitCode != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if res.Stderr != "line 99\nline 100" {
+		t.Fatalf("expected stderr tail, got %q", res.Stderr)
+	}
+
+	buf, truncated, err := p.ReadOperationLog(module.ModuleID, oplog.ID, 1024*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if truncated || strings.Count(string(buf), "\n") != 100 || !strings.HasPrefix(string(buf), "line 1\n") {
+		t.Fatalf("expected the full stderr in the log, got %q", string(buf))
+	}
+
+	// reads are limited to the tail of the log
+	buf, truncated, err = p.ReadOperationLog(module.ModuleID, oplog.ID, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !truncated || string(buf) != "line 100\n" {
+		t.Fatalf("expected truncated tail of the log, got %q %v", string(buf), truncated)
+	}
+
+	// failures reference the log
+	tfErr := newTerraformError("apply", res, oplog, nil)
+	if !strings.Contains(tfErr.Error(), oplog.ID) {
+		t.Fatalf("expected error to reference the log, got %q", tfErr.Error())
+	}
+
+	// the oldest logs beyond the retention are pruned
+	for _, op := range []string{"plan", "destroy"} {
+		_, _, err = p.runCommand(context.Background(), module, op, "echo "+op+" 1>&2")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	logs, err := p.OperationLogs(module.ModuleID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 || logs[0].Operation != "plan" || logs[1].Operation != "destroy" {
+		t.Fatalf("expected the plan and destroy logs to be retained, got %+v", logs)
+	}
+
+	_, _, err = p.ReadOperationLog(module.ModuleID, oplog.ID, 1024)
+	if !errors.Is(err, ErrOperationLogNotFound) {
+		t.Fatalf("expected pruned log to not be found, got %v", err)
+	}
+}
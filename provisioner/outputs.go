@@ -0,0 +1,170 @@
+package provisioner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gigo-ws/models"
+	utils2 "gigo-ws/utils"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+const (
+	// OutputAgentID output declared by workspace templates with the id of the gigo agent
+	OutputAgentID = "agent_id"
+	// OutputAgentToken output declared by workspace templates with the token of the gigo agent
+	OutputAgentToken = "agent_token"
+	// OutputStartCount output declared by workspace templates with the start count of the workspace
+	OutputStartCount = "start_count"
+)
+
+// errOutputNotFound is returned when a module does not declare an output
+// which is expected for modules created before the outputs were declared
+var errOutputNotFound = errors.New("output not found")
+
+// Outputs
+//
+//	Retrieves the root module outputs of the passed terraform
+//	module from its current state via `terraform output -json`.
+//	The read does not wait for a slot of the operation queue since
+//	it follows the apply that produced the state and rejecting it
+//	would fail an operation that already succeeded. The module is
+//	prepped without side effects on the backend.
+func (p *Provisioner) Outputs(ctx context.Context, module *models.TerraformModule) (map[string]*tfjson.StateOutput, error) {
+	p.logger.Debugf("retrieving outputs of module: %d", module.ModuleID)
+
+	// prep module
+	err := p.prepModuleReadOnly(ctx, module)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare module: %w", err)
+	}
+
+	// read outputs from the state
+	res, err := utils2.ExecuteCommand(
+		ctx, p.environment(module), "",
+		"sh", "-c",
+		fmt.Sprintf("%s -chdir=%s output -json -no-color", p.binaryPath(module), module.LocalPath),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve terraform outputs: %v", err)
+	}
+
+	if res.ExitCode != 0 {
+		return nil, newTerraformError("output", res, nil, nil)
+	}
+
+	outputs := make(map[string]*tfjson.StateOutput)
+	err = json.Unmarshal([]byte(res.Stdout), &outputs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse terraform outputs: %v", err)
+	}
+
+	return outputs, nil
+}
+
+// Agent
+//
+//	Retrieves the gigo agent of the passed terraform module from the
+//	agent outputs. Modules created before the outputs were declared
+//	fallback on parsing the agent resource from the statefile.
+func (p *Provisioner) Agent(ctx context.Context, module *models.TerraformModule) (*models.Agent, error) {
+	outputs, err := p.Outputs(ctx, module)
+	if err != nil {
+		return nil, err
+	}
+
+	agent, err := agentFromOutputs(outputs)
+	if err == nil {
+		return agent, nil
+	}
+	if !errors.Is(err, errOutputNotFound) {
+		return nil, err
+	}
+
+	p.logger.Debugf("module %d does not declare agent outputs - parsing statefile", module.ModuleID)
+
+	return ParseStatefileForAgent(p.Backend, module.ModuleID)
+}
+
+// agentFromOutputs
+//
+//	Parses the gigo agent from the outputs of a workspace module
+func agentFromOutputs(outputs map[string]*tfjson.StateOutput) (*models.Agent, error) {
+	idOutput, ok := outputs[OutputAgentID]
+	if !ok || idOutput == nil {
+		return nil, fmt.Errorf("%w: %s", errOutputNotFound, OutputAgentID)
+	}
+	tokenOutput, ok := outputs[OutputAgentToken]
+	if !ok || tokenOutput == nil {
+		return nil, fmt.Errorf("%w: %s", errOutputNotFound, OutputAgentToken)
+	}
+
+	id, err := outputInt(idOutput.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s output: %v", OutputAgentID, err)
+	}
+
+	token, ok := tokenOutput.Value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s output: expected string, got %T", OutputAgentToken, tokenOutput.Value)
+	}
+
+	return &models.Agent{
+		ID:    id,
+		Token: token,
+	}, nil
+}
+
+// workspaceStateFromOutputs
+//
+//	Parses the state of a workspace from the outputs of a workspace module
+func workspaceStateFromOutputs(outputs map[string]*tfjson.StateOutput) (models.WorkspaceState, error) {
+	output, ok := outputs[OutputStartCount]
+	if !ok || output == nil {
+		return -1, fmt.Errorf("%w: %s", errOutputNotFound, OutputStartCount)
+	}
+
+	startCount, err := outputInt(output.Value)
+	if err != nil {
+		return -1, fmt.Errorf("invalid %s output: %v", OutputStartCount, err)
+	}
+
+	if startCount > 0 {
+		return models.WorkspaceStateActive, nil
+	}
+	return models.WorkspaceStateStopped, nil
+}
+
+// outputInt
+//
+//	Converts the value of an output to an integer. Ids are exposed as
+//	strings by the gigo provider so that they are not rounded by a
+//	float64 while counts are json numbers.
+func outputInt(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	case float64:
+		return int64(v), nil
+	default:
+		return 0, fmt.Errorf("expected number or string, got %T", value)
+	}
+}
+
+// parseStatefileOutputs
+//
+//	Parses the root module outputs recorded in a raw statefile
+func parseStatefileOutputs(stateBuf []byte) (map[string]*tfjson.StateOutput, error) {
+	var state struct {
+		Outputs map[string]*tfjson.StateOutput `json:"outputs"`
+	}
+	err := json.Unmarshal(stateBuf, &state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse statefile outputs: %v", err)
+	}
+	return state.Outputs, nil
+}
@@ -0,0 +1,59 @@
+package provisioner
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"gigo-ws/models"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func TestOutputs(t *testing.T) {
+	// output of `terraform output -json` for a workspace template
+	buf := []byte(`{
+  "agent_id": {"sensitive": false, "type": "string", "value": "1620436895550922752"},
+  "agent_token": {"sensitive": true, "type": "string", "value": "8d6cadd6-3469-4ff8-9543-a92a6b160c64"},
+  "start_count": {"sensitive": false, "type": "number", "value": 1}
+}`)
+
+	outputs := make(map[string]*tfjson.StateOutput)
+	err := json.Unmarshal(buf, &outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agent, err := agentFromOutputs(outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agent.ID != 1620436895550922752 || agent.Token != "8d6cadd6-3469-4ff8-9543-a92a6b160c64" {
+		t.Fatalf("unexpected agent: %+v", agent)
+	}
+
+	state, err := workspaceStateFromOutputs(outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != models.WorkspaceStateActive {
+		t.Fatalf("expected active workspace, got %d", state)
+	}
+
+	// modules created before the outputs were declared fallback on the statefile
+	_, err = agentFromOutputs(map[string]*tfjson.StateOutput{})
+	if !errors.Is(err, errOutputNotFound) {
+		t.Fatalf("expected missing output error, got %v", err)
+	}
+	_, err = workspaceStateFromOutputs(nil)
+	if !errors.Is(err, errOutputNotFound) {
+		t.Fatalf("expected missing output error, got %v", err)
+	}
+
+	// invalid outputs are not silently ignored
+	outputs[OutputAgentID] = &tfjson.StateOutput{Value: true}
+	_, err = agentFromOutputs(outputs)
+	if err == nil || errors.Is(err, errOutputNotFound) {
+		t.Fatalf("expected invalid output error, got %v", err)
+	}
+}
@@ -0,0 +1,313 @@
+package provisioner
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gigo-ws/config"
+	"gigo-ws/models"
+
+	"github.com/gage-technologies/gigo-lib/utils"
+)
+
+const (
+	// lockFileName name of the dependency lock file written by terraform init
+	lockFileName = ".terraform.lock.hcl"
+	// providersDir directory of the installed providers within the .terraform directory
+	providersDir = ".terraform/providers"
+	// initMarkerFile marker written to the .terraform directory once init succeeds
+	initMarkerFile = ".terraform/gigo-ws-initialized"
+)
+
+// cliConfigTemplate
+//
+//	Terraform cli configuration that points terraform at the shared
+//	plugin cache. The provider installation block is appended when a
+//	filesystem mirror is configured.
+const cliConfigTemplate = `plugin_cache_dir = %q
+plugin_cache_may_break_dependency_lock_file = true
+`
+
+// providerMirrorTemplate
+//
+//	Provider installation block that consults the filesystem mirror
+//	before falling back on the provider registries
+const providerMirrorTemplate = `
+provider_installation {
+  filesystem_mirror {
+    path = %q
+  }
+  direct {}
+}
+`
+
+// setupPluginCache
+//
+//	Creates the shared plugin cache and init cache directories and writes
+//	the terraform cli configuration. Returns the environment variables
+//	that must be passed to every terraform command.
+func setupPluginCache(cfg config.ProvisionerConfig) (pluginCacheDir string, initCacheDir string, env []string, err error) {
+	// default the cache directories to live beside the terraform binary
+	pluginCacheDir = cfg.PluginCacheDir
+	if pluginCacheDir == "" {
+		pluginCacheDir = filepath.Join(cfg.TerraformDir, "plugin-cache")
+	}
+	initCacheDir = cfg.InitCacheDir
+	if initCacheDir == "" {
+		initCacheDir = filepath.Join(cfg.TerraformDir, "init-cache")
+	}
+
+	for _, dir := range []string{pluginCacheDir, initCacheDir} {
+		err = os.MkdirAll(dir, 0700)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("failed to create cache directory %q: %v", dir, err)
+		}
+	}
+
+	// format the cli configuration
+	cliConfig := fmt.Sprintf(cliConfigTemplate, pluginCacheDir)
+	if cfg.ProviderMirrorDir != "" {
+		exists, err := utils.PathExists(cfg.ProviderMirrorDir)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("failed to check provider mirror path: %v", err)
+		}
+		if !exists {
+			return "", "", nil, fmt.Errorf("provider mirror %q does not exist", cfg.ProviderMirrorDir)
+		}
+		cliConfig += fmt.Sprintf(providerMirrorTemplate, cfg.ProviderMirrorDir)
+	}
+
+	cliConfigPath := filepath.Join(cfg.TerraformDir, "terraform.rc")
+	err = os.WriteFile(cliConfigPath, []byte(cliConfig), 0600)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to write terraform cli configuration: %v", err)
+	}
+
+	env = []string{
+		fmt.Sprintf("TF_CLI_CONFIG_FILE=%s", cliConfigPath),
+		fmt.Sprintf("TF_PLUGIN_CACHE_DIR=%s", pluginCacheDir),
+	}
+
+	return pluginCacheDir, initCacheDir, env, nil
+}
+
+// requiredProviders
+//
+//	Extracts the required_providers block from the main.tf of a module
+//	with the whitespace and comments removed so that formatting changes
+//	to a template do not change the block. Returns nil if the module
+//	does not declare its required providers.
+func requiredProviders(mainTF []byte) []byte {
+	idx := bytes.Index(mainTF, []byte("required_providers"))
+	if idx == -1 {
+		return nil
+	}
+
+	// locate the opening brace of the block
+	start := bytes.IndexByte(mainTF[idx:], '{')
+	if start == -1 {
+		return nil
+	}
+	start += idx
+
+	// walk the block until the braces are balanced
+	depth := 0
+	end := -1
+	for i := start; i < len(mainTF); i++ {
+		switch mainTF[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+		if depth == 0 {
+			end = i
+			break
+		}
+	}
+	if end == -1 {
+		return nil
+	}
+
+	// normalize the block line-by-line
+	var buf bytes.Buffer
+	for _, line := range strings.Split(string(mainTF[start:end+1]), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes()
+}
+
+// providersHash
+//
+//	Returns the hash of the required providers of a module which is used
+//	to key the init cache. False is returned if the module does not
+//	declare its required providers and therefore cannot be cached.
+func (p *Provisioner) providersHash(module *models.TerraformModule) (string, bool) {
+	block := requiredProviders(module.MainTF)
+	if block == nil {
+		return "", false
+	}
+
+	// include the engine and its version since the lock file format,
+	// provider registry and provider selection differ between them
+	h := sha256.New()
+	h.Write([]byte(p.engine.Binary()))
+	h.Write([]byte(p.ModuleVersion(module)))
+	h.Write(block)
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// restoreInitCache
+//
+//	Copies the cached lock file and installed providers for the module's
+//	required providers into the module directory so that terraform init
+//	does not need to resolve or download any providers. Returns false if
+//	there is no cache entry for the module.
+func (p *Provisioner) restoreInitCache(module *models.TerraformModule) (bool, error) {
+	hash, ok := p.providersHash(module)
+	if !ok {
+		return false, nil
+	}
+
+	entry := filepath.Join(p.initCacheDir, hash)
+	exists, err := utils.PathExists(filepath.Join(entry, lockFileName))
+	if err != nil {
+		return false, fmt.Errorf("failed to check init cache: %v", err)
+	}
+	if !exists {
+		return false, nil
+	}
+
+	err = copyFile(filepath.Join(entry, lockFileName), filepath.Join(module.LocalPath, lockFileName))
+	if err != nil {
+		return false, fmt.Errorf("failed to restore lock file: %v", err)
+	}
+
+	err = copyTree(filepath.Join(entry, providersDir), filepath.Join(module.LocalPath, providersDir))
+	if err != nil {
+		return false, fmt.Errorf("failed to restore providers: %v", err)
+	}
+
+	return true, nil
+}
+
+// saveInitCache
+//
+//	Saves the lock file and installed providers of an initialized module
+//	to the init cache. The entry is staged in a temporary directory and
+//	renamed into place so concurrent inits never observe a partial entry.
+func (p *Provisioner) saveInitCache(module *models.TerraformModule) error {
+	hash, ok := p.providersHash(module)
+	if !ok {
+		return nil
+	}
+
+	entry := filepath.Join(p.initCacheDir, hash)
+	exists, err := utils.PathExists(entry)
+	if err != nil {
+		return fmt.Errorf("failed to check init cache: %v", err)
+	}
+	if exists {
+		return nil
+	}
+
+	staging, err := os.MkdirTemp(p.initCacheDir, ".staging-*")
+	if err != nil {
+		return fmt.Errorf("failed to create init cache staging directory: %v", err)
+	}
+	defer os.RemoveAll(staging)
+
+	err = copyFile(filepath.Join(module.LocalPath, lockFileName), filepath.Join(staging, lockFileName))
+	if err != nil {
+		return fmt.Errorf("failed to cache lock file: %v", err)
+	}
+
+	err = copyTree(filepath.Join(module.LocalPath, providersDir), filepath.Join(staging, providersDir))
+	if err != nil {
+		return fmt.Errorf("failed to cache providers: %v", err)
+	}
+
+	// another init may have won the race - their entry is equivalent
+	err = os.Rename(staging, entry)
+	if err != nil {
+		if exists, _ := utils.PathExists(entry); exists {
+			return nil
+		}
+		return fmt.Errorf("failed to save init cache entry: %v", err)
+	}
+
+	return nil
+}
+
+// copyFile
+//
+//	Copies a regular file preserving its permissions
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// copyTree
+//
+//	Recursively copies a directory preserving symlinks. Terraform links
+//	providers from the plugin cache into the module so the symlinks are
+//	copied as-is rather than duplicating the provider binaries.
+func copyTree(src string, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.IsDir():
+			return os.MkdirAll(target, 0700)
+		default:
+			return copyFile(path, target)
+		}
+	})
+}
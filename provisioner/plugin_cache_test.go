@@ -0,0 +1,179 @@
+package provisioner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gigo-ws/config"
+	"gigo-ws/models"
+
+	"github.com/hashicorp/go-version"
+)
+
+func TestRequiredProviders(t *testing.T) {
+	a := []byte(`terraform {
+  required_providers {
+    kubernetes = {
+      source  = "hashicorp/kubernetes"
+      version = "2.16.1"
+    }
+  }
+}
+
+resource "kubernetes_pod" "main" {}
+`)
+
+	// formatting and comments must not change the block
+	b := []byte(`terraform {
+	required_providers {
+		# the cluster provider
+		kubernetes = {
+			source = "hashicorp/kubernetes"
+			version = "2.16.1"
+		}
+	}
+}
+`)
+
+	blockA := requiredProviders(a)
+	if blockA == nil {
+		t.Fatal("expected required providers block")
+	}
+
+	expected := "{\nkubernetes = {\nsource = \"hashicorp/kubernetes\"\nversion = \"2.16.1\"\n}\n}\n"
+	if string(blockA) != expected {
+		t.Fatalf("unexpected block:\n%s", blockA)
+	}
+
+	if string(requiredProviders(b)) != expected {
+		t.Fatalf("expected formatting to be ignored, got:\n%s", requiredProviders(b))
+	}
+
+	if requiredProviders([]byte(`resource "kubernetes_pod" "main" {}`)) != nil {
+		t.Fatal("expected nil block for module without required providers")
+	}
+
+	if requiredProviders([]byte(`terraform { required_providers { kubernetes = {`)) != nil {
+		t.Fatal("expected nil block for unterminated block")
+	}
+}
+
+func TestInitCache(t *testing.T) {
+	dir, err := os.MkdirTemp("", "gigo-ws-init-cache-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, initCacheDir, env, err := setupPluginCache(config.ProvisionerConfig{TerraformDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(env) != 2 {
+		t.Fatalf("expected 2 environment variables, got %v", env)
+	}
+
+	vrs, err := version.NewVersion("1.3.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Provisioner{
+		engine:         &terraformEngine{},
+		defaultVersion: vrs,
+		legacyVersion:  vrs,
+		initCacheDir:   initCacheDir,
+	}
+
+	mainTF := []byte(testTerraformMain)
+
+	// simulate an initialized module with a provider linked from the plugin cache
+	src := &models.TerraformModule{MainTF: mainTF, ModuleID: 1}
+	err = src.WriteTemporaryCopy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src.LocalPath)
+
+	pluginDir := filepath.Join(src.LocalPath, providersDir, "registry.terraform.io/hashicorp/kubernetes/2.16.1")
+	err = os.MkdirAll(pluginDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Symlink("/plugin-cache/kubernetes", filepath.Join(pluginDir, "linux_amd64"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(src.LocalPath, lockFileName), []byte("# lock"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a cold cache has nothing to restore
+	dst := &models.TerraformModule{MainTF: mainTF, ModuleID: 2}
+	err = dst.WriteTemporaryCopy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dst.LocalPath)
+
+	restored, err := p.restoreInitCache(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored {
+		t.Fatal("expected cold cache miss")
+	}
+
+	err = p.saveInitCache(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// saving an existing entry is a no-op
+	err = p.saveInitCache(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err = p.restoreInitCache(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !restored {
+		t.Fatal("expected cache hit")
+	}
+
+	lock, err := os.ReadFile(filepath.Join(dst.LocalPath, lockFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(lock) != "# lock" {
+		t.Fatalf("unexpected lock file: %s", lock)
+	}
+
+	link, err := os.Readlink(filepath.Join(dst.LocalPath, providersDir, "registry.terraform.io/hashicorp/kubernetes/2.16.1/linux_amd64"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link != "/plugin-cache/kubernetes" {
+		t.Fatalf("expected provider symlink to be preserved, got %s", link)
+	}
+
+	// a module pinned to a different terraform version must not share the entry
+	other := &models.TerraformModule{MainTF: mainTF, ModuleID: 3, TerraformVersion: "1.4.0"}
+	err = other.WriteTemporaryCopy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(other.LocalPath)
+
+	restored, err = p.restoreInitCache(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored {
+		t.Fatal("expected cache miss for a different terraform version")
+	}
+}
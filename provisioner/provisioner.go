@@ -8,13 +8,18 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"gigo-ws/config"
+	"gigo-ws/encryption"
+	"gigo-ws/metrics"
 	"gigo-ws/models"
 	"gigo-ws/provisioner/backend"
+	"gigo-ws/provisioner/tfevent"
 	utils2 "gigo-ws/utils"
 
 	"github.com/gage-technologies/gigo-lib/logging"
+	"github.com/gage-technologies/gigo-lib/storage"
 	"github.com/gage-technologies/gigo-lib/utils"
 	"github.com/hashicorp/go-version"
 	tfjson "github.com/hashicorp/terraform-json"
@@ -23,19 +28,17 @@ import (
 // ApplyLogs
 //
 //	Buffered logs from a terraform apply
-//	unmarshalled into maps
+//	parsed into terraform ui events
 type ApplyLogs struct {
-	StdOut []map[string]interface{}
-	StdErr []map[string]interface{}
+	Events []tfevent.Event
 }
 
 // DestroyLogs
 //
 //	Buffered logs from a terraform destroy
-//	unmarshalled into maps
+//	parsed into terraform ui events
 type DestroyLogs struct {
-	StdOut []map[string]interface{}
-	StdErr []map[string]interface{}
+	Events []tfevent.Event
 }
 
 // Provisioner
@@ -43,96 +46,235 @@ type DestroyLogs struct {
 //	Terraform provisioner used to manage
 //	terraform assets of the GIGO system
 type Provisioner struct {
-	Backend          backend.ProvisionerBackend
-	terraformPath    string
-	terraformVersion *version.Version
-	logger           logging.Logger
+	Backend        backend.ProvisionerBackend
+	engine         Engine
+	terraformDir   string
+	defaultVersion *version.Version
+	legacyVersion  *version.Version
+	overwrite      bool
+	// installed versions that have been verified by this process
+	installed    map[string]bool
+	installMu    sync.Mutex
+	initCacheDir string
+	env          []string
+	// initMu serializes inits that install providers into the shared
+	// plugin cache since terraform does not lock the cache directory
+	initMu sync.Mutex
+	// storage holds the operation logs of each module
+	storage      storage.Storage
+	logLevel     string
+	logTailSize  int
+	logRetention int
+	// stateRetention number of statefile versions kept per workspace
+	stateRetention int
+	// queue limits the terraform operations running on the node
+	queue  *operationQueue
+	logger logging.Logger
 }
 
 // NewProvisioner
 //
-//	Creates a new Provisioner and ensures that the
-//	expected terraform binary is installed
-func NewProvisioner(cfg config.ProvisionerConfig, logger logging.Logger) (*Provisioner, error) {
-	// form binary path from terraform directory
-	binaryPath := filepath.Join(cfg.TerraformDir, "terraform")
-
-	// parse terraform version
-	vrs, err := version.NewVersion(cfg.TerraformVersion)
+//	Creates a new Provisioner and ensures that the configured
+//	versions of the engine are installed side by side. The logs
+//	of each terraform operation are kept in the storage engine or
+//	only summarized in memory if no storage engine is passed.
+func NewProvisioner(cfg config.ProvisionerConfig, storageEngine storage.Storage, logger logging.Logger) (*Provisioner, error) {
+	// select the engine that executes our modules
+	engine, err := NewEngine(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse terraform version: %v", err)
+		return nil, fmt.Errorf("failed to create iac engine: %v", err)
 	}
 
-	// check for existing terraform binary
-	exists, err := utils.PathExists(binaryPath)
+	// parse the version that new modules are pinned to
+	vrs, err := version.NewVersion(cfg.TerraformVersion)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check terraform path: %v", err)
+		return nil, fmt.Errorf("failed to parse terraform version: %v", err)
 	}
 
-	// validate existing terraform install
-	if exists {
-		// ensure the passed binary is the correct version
-		existingVersion, err := getTfVersion(binaryPath)
+	// modules stored before versions were pinned were created with the
+	// single installed version which may differ from the current default
+	legacyVrs := vrs
+	if cfg.LegacyTerraformVersion != "" {
+		legacyVrs, err = version.NewVersion(cfg.LegacyTerraformVersion)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get terraform version: %v", err)
+			return nil, fmt.Errorf("failed to parse legacy terraform version: %v", err)
 		}
+	}
 
-		// handle version conflict
-		if !vrs.Equal(existingVersion) {
-			// exit with error if we don't have overwrite privileges
-			if !cfg.Overwrite {
-				return nil, fmt.Errorf("")
-			}
+	// ensure terraform directory exists
+	err = os.MkdirAll(cfg.TerraformDir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create terraform directory: %v", err)
+	}
 
-			// remove existing install and overwrite `exists` to trigger new install
-			err = os.Remove(binaryPath)
-			if err != nil {
-				return nil, fmt.Errorf("failed to remove existing terraform binary: %v", err)
-			}
-			exists = false
-		}
+	// move a binary installed by a previous release into its version directory
+	err = migrateLegacyInstall(cfg.TerraformDir, engine)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate existing %s install: %v", engine.Binary(), err)
 	}
 
-	// this has to stay as a separate if statement instead of an else for the
-	// above conditional since we may we overwrite the `exists` variable on
-	// a version conflict which would require this conditionals logic to execute
-	if !exists {
-		// ensure terraform directory exists
-		err = os.MkdirAll(cfg.TerraformDir, 0700)
+	p := &Provisioner{
+		engine:         engine,
+		terraformDir:   cfg.TerraformDir,
+		defaultVersion: vrs,
+		legacyVersion:  legacyVrs,
+		overwrite:      cfg.Overwrite,
+		installed:      make(map[string]bool),
+		storage:        storageEngine,
+		logLevel:       cfg.OperationLogs.TerraformLogLevel,
+		logTailSize:    cfg.OperationLogs.TailSize,
+		logRetention:   cfg.OperationLogs.Retention,
+		stateRetention: cfg.Backend.History.Retention,
+		logger:         logger,
+	}
+	if p.logTailSize <= 0 {
+		p.logTailSize = defaultLogTailSize
+	}
+	if p.logRetention <= 0 {
+		p.logRetention = defaultLogRetention
+	}
+
+	// limit the terraform operations that run concurrently on the node
+	maxProcesses := cfg.Concurrency.MaxProcesses
+	if maxProcesses <= 0 {
+		maxProcesses = defaultMaxProcesses
+	}
+	maxQueued := cfg.Concurrency.MaxQueued
+	if maxQueued <= 0 {
+		maxQueued = defaultMaxQueued
+	}
+	p.queue = newOperationQueue(maxProcesses, maxQueued)
+
+	// install the default, legacy and additionally configured versions up
+	// front - versions that modules are pinned to are otherwise installed
+	// on first use
+	versions := []*version.Version{vrs, legacyVrs}
+	for _, v := range cfg.TerraformVersions {
+		extra, err := version.NewVersion(v)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create terraform directory: %v", err)
+			return nil, fmt.Errorf("failed to parse terraform version %q: %v", v, err)
 		}
-
-		// perform terraform install via Hashicorp hc-install
-		err = installTf(context.Background(), vrs, cfg.TerraformDir)
+		versions = append(versions, extra)
+	}
+	for _, v := range versions {
+		_, err = p.install(context.Background(), v)
 		if err != nil {
-			return nil, fmt.Errorf("failed to install terraform: %v", err)
+			return nil, err
 		}
 	}
 
+	// prepare the shared provider caches
+	pluginCacheDir, initCacheDir, env, err := setupPluginCache(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup plugin cache: %v", err)
+	}
+	logger.Debugf("using terraform plugin cache %q and init cache %q", pluginCacheDir, initCacheDir)
+
 	// create provisioner Backend
 	var provisionerBackend backend.ProvisionerBackend
 	switch cfg.Backend.Type {
 	case models.ProvisionerBackendFS:
-		provisionerBackend, err = backend.NewProvisionerBackendFS(cfg.Backend.FS)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create fs provisioner backend: %v", err)
+		// the terraform local backend cannot encrypt the statefiles so
+		// they are served by the built in state server when encrypted
+		if cfg.Backend.Encryption.Enabled {
+			keyring, err := encryption.NewKeyring(cfg.Backend.Encryption)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create encryption keyring: %v", err)
+			}
+			if cfg.Backend.Encryption.Rewrap {
+				count, err := rewrapFSStatefiles(cfg.Backend.FS.Root, keyring)
+				if err != nil {
+					return nil, fmt.Errorf("failed to rewrap statefiles: %v", err)
+				}
+				logger.Infof("rewrapped %d statefiles", count)
+			}
+			provisionerBackend, err = backend.NewProvisionerBackendEncryptedFS(cfg.Backend.FS, cfg.Backend.HTTP, keyring)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create encrypted fs provisioner backend: %v", err)
+			}
+		} else {
+			provisionerBackend, err = backend.NewProvisionerBackendFS(cfg.Backend.FS)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create fs provisioner backend: %v", err)
+			}
 		}
 	case models.ProvisionerBackendS3:
-		provisionerBackend, err = backend.NewProvisionerBackendS3(cfg.Backend.S3, cfg.Backend.InsecureS3)
+		provisionerBackend, err = backend.NewProvisionerBackendS3(cfg.Backend.S3, cfg.Backend.InsecureS3, cfg.Backend.S3Lockfile)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create s3 provisioner backend: %v", err)
 		}
+	case models.ProvisionerBackendKubernetes:
+		provisionerBackend, err = backend.NewProvisionerBackendKubernetes(cfg.Backend.Kubernetes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kubernetes provisioner backend: %v", err)
+		}
+	case models.ProvisionerBackendPG:
+		provisionerBackend, err = backend.NewProvisionerBackendPG(cfg.Backend.PG)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create pg provisioner backend: %v", err)
+		}
+	case models.ProvisionerBackendHTTP:
+		provisionerBackend, err = backend.NewProvisionerBackendHTTP(cfg.Backend.HTTP, storageEngine)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create http provisioner backend: %v", err)
+		}
 	default:
 		return nil, fmt.Errorf("unknown provisioner Backend type: %d", provisionerBackend)
 	}
 
-	return &Provisioner{
-		terraformPath:    binaryPath,
-		terraformVersion: vrs,
-		logger:           logger,
-		Backend:          provisionerBackend,
-	}, nil
+	p.initCacheDir = initCacheDir
+	p.env = env
+	p.Backend = provisionerBackend
+
+	return p, nil
+}
+
+// environment
+//
+//	Returns the environment of the process followed by the environment
+//	of the module with the provisioner's terraform configuration appended
+//	so that it takes precedence. The process environment is important
+//	for k8s deployments because the k8s api server config is in the
+//	container's environment and is not stored with the module.
+func (p *Provisioner) environment(module *models.TerraformModule) []string {
+	processEnv := os.Environ()
+	env := make([]string, 0, len(processEnv)+len(module.Environment)+len(p.env))
+	env = append(env, processEnv...)
+	env = append(env, module.Environment...)
+	return append(env, p.env...)
+}
+
+// mergeEnvironment
+//
+//	Appends the passed environment variables to the environment
+//	removing any existing entries with the same keys
+func mergeEnvironment(env []string, envs []string) []string {
+	keys := make(map[string]struct{}, len(envs))
+	for _, e := range envs {
+		key, _, _ := strings.Cut(e, "=")
+		keys[key] = struct{}{}
+	}
+
+	merged := make([]string, 0, len(env)+len(envs))
+	for _, e := range env {
+		key, _, _ := strings.Cut(e, "=")
+		if _, ok := keys[key]; ok {
+			continue
+		}
+		merged = append(merged, e)
+	}
+	return append(merged, envs...)
+}
+
+// prepOptions
+//
+//	Side effects on the backend of a module prep
+type prepOptions struct {
+	// snapshot keeps the current statefile in the statefile history
+	snapshot bool
+	// createWorkspace creates the terraform workspace of the statefile
+	// on backends that store each statefile in its own workspace
+	createWorkspace bool
 }
 
 // prepModule
@@ -142,10 +284,38 @@ func NewProvisioner(cfg config.ProvisionerConfig, logger logging.Logger) (*Provi
 //	WARNING: This function will modify the <BACKEND_PROVIDER> template
 //	the first time it is run on the module. THIS WILL MODIFY THE PASSED MODULE
 func (p *Provisioner) prepModule(ctx context.Context, module *models.TerraformModule) error {
+	return p.prepModuleWithOptions(ctx, module, prepOptions{snapshot: true, createWorkspace: true})
+}
+
+// prepModuleReadOnly
+//
+//	Preps a module for terraform commands that only read the state.
+//	The statefile is not snapshotted and no terraform workspace is
+//	created so the read has no side effects on the backend.
+func (p *Provisioner) prepModuleReadOnly(ctx context.Context, module *models.TerraformModule) error {
+	return p.prepModuleWithOptions(ctx, module, prepOptions{})
+}
+
+// prepModuleWithOptions
+//
+//	Preps a module for terraform operations performing only the
+//	backend side effects selected by the passed options
+func (p *Provisioner) prepModuleWithOptions(ctx context.Context, module *models.TerraformModule, opts prepOptions) error {
 	p.logger.Debugf("prepping module: %d", module.ModuleID)
 
+	// ensure the version that the module is pinned to is installed
+	vrs, err := version.NewVersion(p.ModuleVersion(module))
+	if err != nil {
+		return fmt.Errorf("failed to parse module terraform version: %v", err)
+	}
+	_, err = p.install(ctx, vrs)
+	if err != nil {
+		return err
+	}
+
 	// format module for write
-	mod, envs := p.Backend.ToTerraform(fmt.Sprintf("states/%d", module.ModuleID))
+	bucketPath := fmt.Sprintf("states/%d", module.ModuleID)
+	mod, envs := p.Backend.ToTerraform(bucketPath)
 
 	// override Backend template slot with Backend provider
 	module.MainTF = bytes.ReplaceAll(
@@ -154,31 +324,88 @@ func (p *Provisioner) prepModule(ctx context.Context, module *models.TerraformMo
 		[]byte(mod),
 	)
 
-	// update module environment variables
-	module.Environment = append(module.Environment, envs...)
+	// replace the backend configuration rendered into a stored module by a previous backend
+	if migratingBackend, ok := p.Backend.(backend.MigratingProvisionerBackend); ok {
+		module.MainTF = bytes.ReplaceAll(
+			module.MainTF,
+			[]byte(migratingBackend.PreviousTerraform(bucketPath)),
+			[]byte(mod),
+		)
+	}
+
+	// update module environment variables replacing the values of a previous prep
+	module.Environment = mergeEnvironment(module.Environment, envs)
 
 	// mark sure that module is written to local fs
 	// this operation is idempotent so we execute every time
-	err := module.WriteTemporaryCopy()
+	err = module.WriteTemporaryCopy()
 	if err != nil {
 		return fmt.Errorf("failed to write module: %v", err)
 	}
 
+	// keep the state that terraform is about to operate on so that it can be
+	// restored if the operation corrupts it - failing to do so is not fatal
+	if opts.snapshot {
+		err = p.snapshotStatefile(bucketPath)
+		if err != nil {
+			p.logger.Warnf("failed to snapshot statefile of module %d: %v", module.ModuleID, err)
+		}
+	}
+
+	// skip init if a previous operation already initialized the module
+	// directory - WriteTemporaryCopy guarantees the main.tf is unchanged
+	initialized, err := utils.PathExists(filepath.Join(module.LocalPath, initMarkerFile))
+	if err != nil {
+		return fmt.Errorf("failed to check module init state: %v", err)
+	}
+	if initialized {
+		return nil
+	}
+
+	// reuse the providers and lock file of a previous init with the same
+	// required providers so that init does not resolve or download them
+	cached, err := p.restoreInitCache(module)
+	if err != nil {
+		p.logger.Warnf("failed to restore init cache for module %d: %v", module.ModuleID, err)
+	}
+	if !cached {
+		p.initMu.Lock()
+		defer p.initMu.Unlock()
+	}
+
+	// terraform will not initialize a module for a workspace that does not exist
+	if workspaceBackend, ok := p.Backend.(backend.WorkspaceProvisionerBackend); ok && opts.createWorkspace {
+		err = workspaceBackend.CreateWorkspace(bucketPath)
+		if err != nil {
+			return fmt.Errorf("failed to create terraform workspace: %v", err)
+		}
+	}
+
 	// initialize terraform module
-	// terraform implements init as an idempotent operation, so
-	// we should execute this every time - the time cost is worth
-	// the code cleanliness of not checking
-	res, err := utils2.ExecuteCommand(
-		ctx, module.Environment, "",
-		"sh", "-c",
-		fmt.Sprintf("%s -chdir=%s init", p.terraformPath, module.LocalPath),
+	res, oplog, err := p.runCommand(
+		ctx, module, "init",
+		fmt.Sprintf("%s -chdir=%s init -input=false", p.binaryPath(module), module.LocalPath),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to initialize terraform module: %v", err)
 	}
+	metrics.ObserveTerraform("init", res.ExitCode, res.Cost)
 
 	if res.ExitCode != 0 {
-		return fmt.Errorf("failed to initialize terraform module: %s", res.Stderr)
+		return newTerraformError("init", res, oplog, nil)
+	}
+
+	// save the providers for the next module with the same required providers
+	if !cached {
+		err = p.saveInitCache(module)
+		if err != nil {
+			p.logger.Warnf("failed to save init cache for module %d: %v", module.ModuleID, err)
+		}
+	}
+
+	err = os.WriteFile(filepath.Join(module.LocalPath, initMarkerFile), nil, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to mark module as initialized: %v", err)
 	}
 
 	return nil
@@ -191,17 +418,23 @@ func (p *Provisioner) prepModule(ctx context.Context, module *models.TerraformMo
 func (p *Provisioner) Validate(ctx context.Context, module *models.TerraformModule) (*tfjson.ValidateOutput, error) {
 	p.logger.Debugf("validating module: %d", module.ModuleID)
 
+	// wait for a slot to run the operation
+	release, err := p.acquireOperation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	// prep module
-	err := p.prepModule(ctx, module)
+	err = p.prepModule(ctx, module)
 	if err != nil {
-		return nil, fmt.Errorf("failed to prepare module: %v", err)
+		return nil, fmt.Errorf("failed to prepare module: %w", err)
 	}
 
 	// run terraform validate
-	res, err := utils2.ExecuteCommand(
-		ctx, module.Environment, "",
-		"sh", "-c",
-		fmt.Sprintf("%s -chdir=%s validate -json", p.terraformPath, module.LocalPath),
+	res, oplog, err := p.runCommand(
+		ctx, module, "validate",
+		fmt.Sprintf("%s -chdir=%s validate -json", p.binaryPath(module), module.LocalPath),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to validate module: %v", err)
@@ -216,11 +449,30 @@ func (p *Provisioner) Validate(ctx context.Context, module *models.TerraformModu
 
 	// return error for invalid template
 	if !validationResponse.Valid {
-		return &validationResponse, fmt.Errorf("invalid terraform module")
+		tfErr := newTerraformError("validate", res, oplog, nil)
+		for _, d := range validationResponse.Diagnostics {
+			if d.Severity != tfjson.DiagnosticSeverityError {
+				continue
+			}
+			diag := tfevent.Diagnostic{
+				Severity: string(d.Severity),
+				Summary:  d.Summary,
+				Detail:   d.Detail,
+			}
+			if d.Range != nil {
+				diag.Range = &tfevent.Range{
+					Filename: d.Range.Filename,
+					Start:    tfevent.Pos{Line: d.Range.Start.Line, Column: d.Range.Start.Column, Byte: d.Range.Start.Byte},
+					End:      tfevent.Pos{Line: d.Range.End.Line, Column: d.Range.End.Column, Byte: d.Range.End.Byte},
+				}
+			}
+			tfErr.Diagnostics = append(tfErr.Diagnostics, diag)
+		}
+		return &validationResponse, tfErr
 	}
 
 	if res.ExitCode != 0 {
-		return nil, fmt.Errorf("failed to validate module: %s", res.Stderr)
+		return nil, newTerraformError("validate", res, oplog, nil)
 	}
 
 	// mark module as having been validated
@@ -229,77 +481,313 @@ func (p *Provisioner) Validate(ctx context.Context, module *models.TerraformModu
 	return nil, nil
 }
 
+// Plan
+//
+//	Plans the passed terraform module and returns the changes
+//	that an apply would make without modifying any resources
+func (p *Provisioner) Plan(ctx context.Context, module *models.TerraformModule) (*tfjson.Plan, error) {
+	p.logger.Debugf("planning module: %d", module.ModuleID)
+	buf, err := p.plan(ctx, module, "")
+	if err != nil {
+		return nil, err
+	}
+	return parsePlan(buf)
+}
+
+// PlanCreate
+//
+//	Plans the creation of the passed terraform module. The statefile
+//	is not snapshotted and a terraform workspace that is created to
+//	initialize the module is removed once the plan completes so that
+//	planning does not leave behind a workspace that does not exist.
+func (p *Provisioner) PlanCreate(ctx context.Context, module *models.TerraformModule) (*tfjson.Plan, error) {
+	p.logger.Debugf("planning create of module: %d", module.ModuleID)
+
+	// only backends with terraform workspaces write a statefile before the apply
+	bucketPath := fmt.Sprintf("states/%d", module.ModuleID)
+	if _, ok := p.Backend.(backend.WorkspaceProvisionerBackend); ok {
+		statefile, err := p.Backend.GetStatefile(bucketPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve statefile: %v", err)
+		}
+		if statefile != nil {
+			_ = statefile.Close()
+		} else {
+			defer func() {
+				err := p.Backend.RemoveStatefile(bucketPath)
+				if err != nil {
+					p.logger.Warnf("failed to remove workspace of create plan %d: %v", module.ModuleID, err)
+				}
+			}()
+		}
+	}
+
+	buf, err := p.planWithOptions(ctx, module, "", prepOptions{createWorkspace: true})
+	if err != nil {
+		return nil, err
+	}
+	return parsePlan(buf)
+}
+
+// PlanDestroy
+//
+//	Plans the destruction of the passed terraform module and returns
+//	the changes that a destroy would make without modifying any resources
+func (p *Provisioner) PlanDestroy(ctx context.Context, module *models.TerraformModule) (*tfjson.Plan, error) {
+	p.logger.Debugf("planning destroy of module: %d", module.ModuleID)
+	buf, err := p.plan(ctx, module, " -destroy")
+	if err != nil {
+		return nil, err
+	}
+	return parsePlan(buf)
+}
+
+// DetectDrift
+//
+//	Performs a refresh-only plan of the passed terraform module and
+//	returns the resources that were changed or deleted outside of
+//	terraform since the statefile was last written
+func (p *Provisioner) DetectDrift(ctx context.Context, module *models.TerraformModule) ([]models.ResourceDrift, error) {
+	p.logger.Debugf("detecting drift of module: %d", module.ModuleID)
+	buf, err := p.plan(ctx, module, " -refresh-only")
+	if err != nil {
+		return nil, err
+	}
+	return parseDrift(buf)
+}
+
+// plan
+//
+//	Helper function to write a terraform plan for the module with the
+//	passed plan flags and render the saved plan as json
+func (p *Provisioner) plan(ctx context.Context, module *models.TerraformModule, flags string) ([]byte, error) {
+	return p.planWithOptions(ctx, module, flags, prepOptions{snapshot: true, createWorkspace: true})
+}
+
+// planWithOptions
+//
+//	Writes and renders a terraform plan for the module
+//	prepping the module with the passed options
+func (p *Provisioner) planWithOptions(ctx context.Context, module *models.TerraformModule, flags string, opts prepOptions) ([]byte, error) {
+	// wait for a slot to run the operation
+	release, err := p.acquireOperation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// prep module
+	err = p.prepModuleWithOptions(ctx, module, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare module: %w", err)
+	}
+
+	// the plan file is written relative to the module directory
+	// and contains the module state so we remove it once parsed
+	planFile := "tfplan"
+	defer os.Remove(filepath.Join(module.LocalPath, planFile))
+
+	// run terraform plan
+	res, oplog, err := p.runCommand(
+		ctx, module, "plan",
+		fmt.Sprintf(
+			"%s -chdir=%s plan%s -json -no-color -input=false -out=%s",
+			p.binaryPath(module), module.LocalPath, flags, planFile,
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan terraform module: %v", err)
+	}
+	metrics.ObserveTerraform("plan", res.ExitCode, res.Cost)
+
+	// return error for invalid terraform module
+	if res.ExitCode != 0 {
+		return nil, newTerraformError("plan", res, oplog, tfevent.ParseAll(res.Stdout))
+	}
+
+	// render the saved plan as json
+	res, err = utils2.ExecuteCommand(
+		ctx, p.environment(module), "",
+		"sh", "-c",
+		fmt.Sprintf("%s -chdir=%s show -json %s", p.binaryPath(module), module.LocalPath, planFile),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to show terraform plan: %v", err)
+	}
+
+	if res.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to show terraform plan: %s", res.Stderr)
+	}
+
+	return []byte(res.Stdout), nil
+}
+
+// parsePlan
+//
+//	Parses the json representation of a saved plan
+func parsePlan(buf []byte) (*tfjson.Plan, error) {
+	var plan tfjson.Plan
+	err := json.Unmarshal(buf, &plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %v", err)
+	}
+
+	err = plan.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan: %v", err)
+	}
+
+	return &plan, nil
+}
+
 // Apply
 //
 //	Applies the passed terraform module
 func (p *Provisioner) Apply(ctx context.Context, module *models.TerraformModule) (*ApplyLogs, error) {
 	p.logger.Debugf("applying module: %d", module.ModuleID)
 
+	// wait for a slot to run the operation
+	release, err := p.acquireOperation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	// prep module
-	err := p.prepModule(ctx, module)
+	err = p.prepModule(ctx, module)
 	if err != nil {
-		return nil, fmt.Errorf("failed to prepare module: %v", err)
+		return nil, fmt.Errorf("failed to prepare module: %w", err)
 	}
 
 	// run terraform apply
-	res, err := utils2.ExecuteCommand(
-		ctx, module.Environment, "",
-		"sh", "-c",
+	res, oplog, err := p.runCommand(
+		ctx, module, "apply",
 		fmt.Sprintf(
-			"TF_LOG=DEBUG %s -chdir=%s apply -json -auto-approve -no-color -input=false",
-			p.terraformPath, module.LocalPath,
+			"%s -chdir=%s apply -json -auto-approve -no-color -input=false",
+			p.binaryPath(module), module.LocalPath,
 		),
 	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply terraform module: %v", err)
+	}
+	metrics.ObserveTerraform("apply", res.ExitCode, res.Cost)
 
-	// return error for invalid terraform module
+	// parse stdout jsonl from apply responses - lines that are not
+	// terraform events are skipped since the apply has already
+	// modified resources and we must not orphan them by failing here
+	applyResult := &ApplyLogs{
+		Events: tfevent.ParseAll(res.Stdout),
+	}
+
+	// return error for failed terraform apply
 	if res.ExitCode != 0 {
-		return nil, fmt.Errorf("failed to apply terraform module:\n    code: %d\n    out:\n%s\n    err:\n%s", res.ExitCode, res.Stdout, res.Stderr)
+		return nil, newTerraformError("apply", res, oplog, applyResult.Events)
+	}
+
+	return applyResult, nil
+}
+
+// ApplyStream
+//
+//	Applies the passed terraform module and sends each terraform
+//	machine-readable event to the passed channel as it is emitted.
+//	The events channel is never closed by this function; the caller
+//	should close it once ApplyStream returns.
+func (p *Provisioner) ApplyStream(ctx context.Context, module *models.TerraformModule, events chan<- tfevent.Event) (*ApplyLogs, error) {
+	p.logger.Debugf("applying module with stream: %d", module.ModuleID)
+
+	// wait for a slot to run the operation
+	release, err := p.acquireOperation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// prep module
+	err = p.prepModule(ctx, module)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare module: %w", err)
 	}
 
 	// create apply result
-	applyResult := &ApplyLogs{}
+	applyResult := &ApplyLogs{
+		Events: make([]tfevent.Event, 0),
+	}
 
-	// parse stdout jsonl from apply responses
-	for _, l := range strings.Split(res.Stdout, "\n") {
-		var m map[string]interface{}
-		err = json.Unmarshal([]byte(l), &m)
-		if err != nil {
-			// make sure to wrap this error so that the caller
-			// can check the type to determine if they should
-			// proceed. this is important since apply executes
-			// a resource state change and we don't want to
-			// orphan resource cause we error here
-			return nil, NewResultParseError(fmt.Sprintf("failed to parse apply response: %v", err))
+	// create channels to receive the command output line-by-line
+	stdOut := make(chan string)
+	stdErr := make(chan string)
+
+	// stream stderr into the operation log so that we can summarize a failure
+	oplog := p.startOperationLog(module, "apply")
+
+	// launch go func to consume the command output
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		// Done when both channels have been closed
+		// https://dave.cheney.net/2013/04/30/curious-channels
+		for stdOut != nil || stdErr != nil {
+			select {
+			case line, ok := <-stdOut:
+				if !ok {
+					stdOut = nil
+					continue
+				}
+
+				// we don't fail on a bad line here since the apply is
+				// already in progress - we log it and move on
+				e, ok := tfevent.Parse(line)
+				if !ok {
+					if strings.TrimSpace(line) != "" {
+						p.logger.Debugf("skipping apply stream line %d: %s", module.ModuleID, line)
+					}
+					continue
+				}
+				applyResult.Events = append(applyResult.Events, e)
+
+				// forward the event to the caller
+				events <- e
+			case line, ok := <-stdErr:
+				if !ok {
+					stdErr = nil
+					continue
+				}
+				oplog.writeLine(line)
+			}
 		}
-		applyResult.StdOut = append(applyResult.StdOut, m)
-	}
-
-	b, _ := json.Marshal(applyResult.StdOut)
-	p.logger.Debugf("apply op internal logs %d:\n---\n%s\n---\n", module.ModuleID, string(b))
-
-	p.logger.Debugf("debug stderr %d\n---\n%s\n---", module.ModuleID, res.Stderr)
-
-	// // parse stderr jsonl from apply responses
-	// for _, l := range strings.Split(res.Stderr, "\n") {
-	// 	var m map[string]interface{}
-	// 	err = json.Unmarshal([]byte(l), &m)
-	// 	if err != nil {
-	// 		// make sure to wrap this error so that the caller
-	// 		// can check the type to determine if they should
-	// 		// proceed. this is important since apply executes
-	// 		// a resource state change and we don't want to
-	// 		// orphan resource cause we error here
-	// 		return nil, NewResultParseError(fmt.Sprintf("failed to parse apply response: %v", err))
-	// 	}
-	// 	applyResult.StdErr = append(applyResult.StdErr, m)
-	// }
-	//
-	// if len(applyResult.StdErr) > 0 {
-	// 	b, _ = json.Marshal(applyResult.StdErr)
-	// } else {
-	// 	b = []byte("empty logs")
-	// }
-	// p.logger.Debugf("apply op internal err logs %d:\n---\n%s\n---\n", module.ModuleID, string(b))
+	}()
+
+	// run terraform apply
+	res, err := utils2.ExecuteCommandStream(
+		ctx, p.operationEnvironment(module, oplog), stdOut, stdErr,
+		"sh", "-c",
+		fmt.Sprintf(
+			"%s -chdir=%s apply -json -auto-approve -no-color -input=false",
+			p.binaryPath(module), module.LocalPath,
+		),
+	)
+
+	// the command has exited and all output has been sent so
+	// we can close the output channels and wait for the consumer
+	close(stdOut)
+	close(stdErr)
+	<-done
+
+	ref := p.finishOperationLog(oplog)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply terraform module: %v", err)
+	}
+	metrics.ObserveTerraform("apply", res.ExitCode, res.Cost)
+
+	// return error for failed terraform apply
+	if res.ExitCode != 0 {
+		res.Stdout = ""
+		res.Stderr = oplog.tail.String()
+		return nil, newTerraformError("apply", res, ref, applyResult.Events)
+	}
 
 	return applyResult, nil
 }
@@ -310,59 +798,43 @@ func (p *Provisioner) Apply(ctx context.Context, module *models.TerraformModule)
 func (p *Provisioner) Destroy(ctx context.Context, module *models.TerraformModule) (*DestroyLogs, error) {
 	p.logger.Debugf("destroying module: %d", module.ModuleID)
 
+	// wait for a slot to run the operation
+	release, err := p.acquireOperation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	// prep module
-	err := p.prepModule(ctx, module)
+	err = p.prepModule(ctx, module)
 	if err != nil {
-		return nil, fmt.Errorf("failed to prepare module: %v", err)
+		return nil, fmt.Errorf("failed to prepare module: %w", err)
 	}
 
 	// run terraform apply
-	res, err := utils2.ExecuteCommand(
-		ctx, module.Environment, "",
-		"sh", "-c",
+	res, oplog, err := p.runCommand(
+		ctx, module, "destroy",
 		fmt.Sprintf(
 			"%s -chdir=%s destroy -json -auto-approve -no-color",
-			p.terraformPath, module.LocalPath,
+			p.binaryPath(module), module.LocalPath,
 		),
 	)
-
-	// return error for invalid terraform module
-	if res.ExitCode != 0 {
-		return nil, fmt.Errorf("failed to apply terraform module:\n    code: %d\n    out:\n%s\n    err:\n%s", res.ExitCode, res.Stdout, res.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to destroy terraform module: %v", err)
 	}
+	metrics.ObserveTerraform("destroy", res.ExitCode, res.Cost)
 
-	// create destroy result
-	destroyResult := &DestroyLogs{}
+	// parse stdout jsonl from destroy responses - lines that are not
+	// terraform events are skipped since the destroy has already
+	// modified resources and we must not orphan them by failing here
+	destroyResult := &DestroyLogs{
+		Events: tfevent.ParseAll(res.Stdout),
+	}
 
-	// parse stdout jsonl from apply responses
-	for _, l := range strings.Split(res.Stdout, "\n") {
-		var m map[string]interface{}
-		err = json.Unmarshal([]byte(l), &m)
-		if err != nil {
-			// make sure to wrap this error so that the caller
-			// can check the type to determine if they should
-			// proceed. this is important since destroy executes
-			// a resource state change and we don't want to
-			// orphan resource cause we error here
-			return nil, NewResultParseError(fmt.Sprintf("failed to parse destroy response: %v", err))
-		}
-		destroyResult.StdOut = append(destroyResult.StdOut, m)
-	}
-
-	// // parse stderr jsonl from apply responses
-	// for _, l := range strings.Split(res.Stderr, "\n") {
-	// 	var m map[string]interface{}
-	// 	err = json.Unmarshal([]byte(l), &m)
-	// 	if err != nil {
-	// 		// make sure to wrap this error so that the caller
-	// 		// can check the type to determine if they should
-	// 		// proceed. this is important since destroy executes
-	// 		// a resource state change and we don't want to
-	// 		// orphan resource cause we error here
-	// 		return nil, NewResultParseError(fmt.Sprintf("failed to parse apply response: %v", err))
-	// 	}
-	// 	destroyResult.StdErr = append(destroyResult.StdErr, m)
-	// }
+	// return error for failed terraform destroy
+	if res.ExitCode != 0 {
+		return nil, newTerraformError("destroy", res, oplog, destroyResult.Events)
+	}
 
 	return destroyResult, nil
 }
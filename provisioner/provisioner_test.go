@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -63,7 +64,7 @@ func TestNewProvisioner(t *testing.T) {
 				Root: "/tmp/gigo-ws-new-Backend",
 			},
 		},
-	}, logger)
+	}, nil, logger)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -89,7 +90,7 @@ func TestNewProvisioner_prepModule(t *testing.T) {
 				Root: "/tmp/gigo-ws-provisioner-Backend",
 			},
 		},
-	}, logger)
+	}, nil, logger)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -125,6 +126,17 @@ func TestNewProvisioner_prepModule(t *testing.T) {
 	}
 }
 
+func TestMergeEnvironment(t *testing.T) {
+	env := mergeEnvironment(
+		[]string{"GIGO_WORKSPACE_ID=420", "PG_CONN_STR=old", "TF_HTTP_PASSWORD=old"},
+		[]string{"PG_CONN_STR=new", "TF_HTTP_PASSWORD=new"},
+	)
+	expected := []string{"GIGO_WORKSPACE_ID=420", "PG_CONN_STR=new", "TF_HTTP_PASSWORD=new"}
+	if !reflect.DeepEqual(env, expected) {
+		t.Fatalf("expected %v, got %v", expected, env)
+	}
+}
+
 func TestNewProvisioner_Validate(t *testing.T) {
 	logger, err := logging.CreateBasicLogger(logging.NewDefaultBasicLoggerOptions("/tmp/gigo-ws-prep-provisioner-test.log"))
 	if err != nil {
@@ -141,7 +153,7 @@ func TestNewProvisioner_Validate(t *testing.T) {
 				Root: "/tmp/gigo-ws-provisioner-Backend",
 			},
 		},
-	}, logger)
+	}, nil, logger)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -204,7 +216,7 @@ func TestNewProvisioner_Apply(t *testing.T) {
 				Root: "/tmp/gigo-ws-provisioner-Backend",
 			},
 		},
-	}, logger)
+	}, nil, logger)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -220,7 +232,7 @@ func TestNewProvisioner_Apply(t *testing.T) {
 			"sh", "-c",
 			fmt.Sprintf(
 				"cd %s && %s destroy -json -auto-approve -no-color",
-				module.LocalPath, p.terraformPath,
+				module.LocalPath, p.binaryPath(module),
 			),
 		)
 		_ = os.RemoveAll(module.LocalPath)
@@ -235,13 +247,10 @@ func TestNewProvisioner_Apply(t *testing.T) {
 		t.Fatalf("apply result should not be nil")
 	}
 
-	if len(applyLogs.StdOut) != 20 {
-		t.Fatalf("stdout length should be 20, got %d", len(applyLogs.StdOut))
+	if len(applyLogs.Events) != 20 {
+		t.Fatalf("event count should be 20, got %d", len(applyLogs.Events))
 	}
 
-	if len(applyLogs.StdErr) != 0 {
-		t.Fatalf("stderr length should be 0, got %d", len(applyLogs.StdErr))
-	}
 }
 
 func TestNewProvisioner_Destroy(t *testing.T) {
@@ -260,7 +269,7 @@ func TestNewProvisioner_Destroy(t *testing.T) {
 				Root: "/tmp/gigo-ws-provisioner-Backend",
 			},
 		},
-	}, logger)
+	}, nil, logger)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -280,7 +289,7 @@ func TestNewProvisioner_Destroy(t *testing.T) {
 			"sh", "-c",
 			fmt.Sprintf(
 				"cd %s && %s destroy -json -auto-approve -no-color",
-				module1.LocalPath, p.terraformPath,
+				module1.LocalPath, p.binaryPath(module1),
 			),
 		)
 		_, _ = utils2.ExecuteCommand(
@@ -288,7 +297,7 @@ func TestNewProvisioner_Destroy(t *testing.T) {
 			"sh", "-c",
 			fmt.Sprintf(
 				"cd %s && %s destroy -json -auto-approve -no-color",
-				module2.LocalPath, p.terraformPath,
+				module2.LocalPath, p.binaryPath(module2),
 			),
 		)
 		_ = os.RemoveAll(module1.LocalPath)
@@ -311,11 +320,8 @@ func TestNewProvisioner_Destroy(t *testing.T) {
 		t.Fatalf("apply result should not be nil")
 	}
 
-	if len(destroyLogs.StdOut) != 26 {
-		t.Fatalf("stdout length should be 26, got %d", len(destroyLogs.StdOut))
+	if len(destroyLogs.Events) != 26 {
+		t.Fatalf("event count should be 26, got %d", len(destroyLogs.Events))
 	}
 
-	if len(destroyLogs.StdErr) != 0 {
-		t.Fatalf("stderr length should be 0, got %d", len(destroyLogs.StdErr))
-	}
 }
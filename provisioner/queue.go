@@ -0,0 +1,227 @@
+package provisioner
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"gigo-ws/metrics"
+)
+
+const (
+	// defaultMaxProcesses is the number of terraform operations that run
+	// concurrently on a node when no limit is configured
+	defaultMaxProcesses = 8
+
+	// defaultMaxQueued is the number of terraform operations that wait for
+	// a slot on a node when no queue size is configured
+	defaultMaxQueued = 64
+)
+
+// ErrQueueFull is returned when a terraform operation cannot be queued
+// because the node is already saturated with queued operations
+var ErrQueueFull = errors.New("terraform operation queue is full")
+
+type Priority int
+
+const (
+	// PriorityBackground background work like volume pool and drift
+	// operations that only runs when no user operation is waiting
+	PriorityBackground Priority = iota
+	// PriorityUser operations requested by a user - this is the
+	// priority of operations that are not explicitly prioritized
+	PriorityUser
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityBackground:
+		return "background"
+	case PriorityUser:
+		return "user"
+	default:
+		return "unknown"
+	}
+}
+
+type priorityKey struct{}
+
+// WithPriority
+//
+//	Returns a context that queues the terraform operations
+//	executed with it at the passed priority
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+type guaranteedKey struct{}
+
+// WithGuaranteedSlot
+//
+//	Returns a context whose terraform operations are never rejected by a
+//	full queue. The operations still wait for a free slot. Used for the
+//	cleanup of failed operations which would otherwise orphan resources.
+func WithGuaranteedSlot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, guaranteedKey{}, true)
+}
+
+// priorityFromContext
+//
+//	Returns the priority of the context defaulting to PriorityUser
+func priorityFromContext(ctx context.Context) Priority {
+	priority, ok := ctx.Value(priorityKey{}).(Priority)
+	if !ok || priority < PriorityBackground || priority > PriorityUser {
+		return PriorityUser
+	}
+	return priority
+}
+
+// operationQueue
+//
+//	Limits the number of terraform operations that run concurrently
+//	on a node. Operations that exceed the limit wait in a queue per
+//	priority and slots are granted to the highest priority first so
+//	that user operations are never stuck behind background work.
+type operationQueue struct {
+	mu        sync.Mutex
+	running   int
+	limit     int
+	maxQueued int
+	queued    int
+	// waiters FIFO queue of chan struct{} per priority
+	waiters [PriorityUser + 1]*list.List
+}
+
+// newOperationQueue
+//
+//	Creates an operationQueue that runs at most limit operations
+//	concurrently and holds at most maxQueued waiting operations
+func newOperationQueue(limit int, maxQueued int) *operationQueue {
+	q := &operationQueue{
+		limit:     limit,
+		maxQueued: maxQueued,
+	}
+	for i := range q.waiters {
+		q.waiters[i] = list.New()
+	}
+	q.observe()
+	return q
+}
+
+// acquire
+//
+//	Waits for a slot to run a terraform operation at the passed priority.
+//	ErrQueueFull is returned immediately if the queue is full unless the
+//	context has a guaranteed slot and the context error is returned if the
+//	context is done before a slot is granted. The returned function must
+//	be called to release the slot.
+func (q *operationQueue) acquire(ctx context.Context, priority Priority) (func(), error) {
+	q.mu.Lock()
+
+	// run immediately if there is a free slot and no operation of the
+	// same or higher priority is waiting for it
+	if q.running < q.limit && !q.waitingAtOrAbove(priority) {
+		q.running++
+		q.observe()
+		q.mu.Unlock()
+		return q.release, nil
+	}
+
+	guaranteed, _ := ctx.Value(guaranteedKey{}).(bool)
+	if q.queued >= q.maxQueued && !guaranteed {
+		queued := q.queued
+		q.mu.Unlock()
+		metrics.ObserveTerraformQueueRejection(priority.String())
+		return nil, fmt.Errorf("%w: %d operations queued behind %d running", ErrQueueFull, queued, q.limit)
+	}
+
+	ready := make(chan struct{})
+	elem := q.waiters[priority].PushBack(ready)
+	q.queued++
+	q.observe()
+	q.mu.Unlock()
+
+	select {
+	case <-ready:
+		return q.release, nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		// the slot may have been granted while we were acquiring the lock
+		select {
+		case <-ready:
+			q.running--
+			q.grant()
+		default:
+			q.waiters[priority].Remove(elem)
+			q.queued--
+		}
+		q.observe()
+		return nil, ctx.Err()
+	}
+}
+
+// release
+//
+//	Releases a slot and grants it to the next waiting operation
+func (q *operationQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.running--
+	q.grant()
+	q.observe()
+}
+
+// grant
+//
+//	Grants free slots to the waiting operations with the highest
+//	priority first. The caller must hold the lock.
+func (q *operationQueue) grant() {
+	for priority := len(q.waiters) - 1; priority >= 0 && q.running < q.limit; priority-- {
+		for q.waiters[priority].Len() > 0 && q.running < q.limit {
+			ready := q.waiters[priority].Remove(q.waiters[priority].Front()).(chan struct{})
+			q.queued--
+			q.running++
+			close(ready)
+		}
+	}
+}
+
+// waitingAtOrAbove
+//
+//	Determines if an operation of the passed priority or higher is
+//	waiting for a slot. The caller must hold the lock.
+func (q *operationQueue) waitingAtOrAbove(priority Priority) bool {
+	for p := int(priority); p < len(q.waiters); p++ {
+		if q.waiters[p].Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// observe
+//
+//	Records the state of the queue. The caller must hold the lock.
+func (q *operationQueue) observe() {
+	depth := make(map[string]int, len(q.waiters))
+	for priority, waiters := range q.waiters {
+		depth[Priority(priority).String()] = waiters.Len()
+	}
+	metrics.ObserveTerraformQueue(q.running, depth)
+}
+
+// acquireOperation
+//
+//	Waits for a slot to run a terraform operation with the priority of
+//	the context. Operations are not limited if no queue is configured.
+func (p *Provisioner) acquireOperation(ctx context.Context) (func(), error) {
+	if p.queue == nil {
+		return func() {}, nil
+	}
+	return p.queue.acquire(ctx, priorityFromContext(ctx))
+}
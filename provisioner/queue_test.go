@@ -0,0 +1,134 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOperationQueue(t *testing.T) {
+	q := newOperationQueue(1, 2)
+
+	// the first operation runs immediately
+	release, err := q.acquire(context.Background(), PriorityUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// queue a background operation followed by a user operation
+	order := make(chan Priority, 2)
+	wait := func(priority Priority) {
+		ctx := WithPriority(context.Background(), priority)
+		rel, err := q.acquire(ctx, priorityFromContext(ctx))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		order <- priority
+		rel()
+	}
+	go wait(PriorityBackground)
+	waitQueued(t, q, 1)
+	go wait(PriorityUser)
+	waitQueued(t, q, 2)
+
+	// the queue is full so further operations are rejected
+	_, err = q.acquire(context.Background(), PriorityUser)
+	if !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	// the user operation is granted the slot before the background operation
+	release()
+	for _, expected := range []Priority{PriorityUser, PriorityBackground} {
+		select {
+		case priority := <-order:
+			if priority != expected {
+				t.Fatalf("expected %s operation to run, got %s", expected, priority)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s operation", expected)
+		}
+	}
+
+	// waiting operations are removed from the queue when their context is done
+	release, err = q.acquire(context.Background(), PriorityUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	_, err = q.acquire(ctx, PriorityBackground)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	waitQueued(t, q, 0)
+	release()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.running != 0 {
+		t.Fatalf("expected no running operations, got %d", q.running)
+	}
+}
+
+func waitQueued(t *testing.T, q *operationQueue, queued int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		q.mu.Lock()
+		n := q.queued
+		q.mu.Unlock()
+		if n == queued {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d queued operations", queued)
+}
+
+func TestOperationQueue_GuaranteedSlot(t *testing.T) {
+	q := newOperationQueue(1, 1)
+
+	release, err := q.acquire(context.Background(), PriorityUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// fill the queue with a waiting operation
+	queuedDone := make(chan struct{})
+	go func() {
+		defer close(queuedDone)
+		rel, err := q.acquire(context.Background(), PriorityUser)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		rel()
+	}()
+	waitQueued(t, q, 1)
+
+	// the queue is full but a guaranteed operation still waits for a slot
+	guaranteedDone := make(chan error, 1)
+	go func() {
+		rel, err := q.acquire(WithGuaranteedSlot(context.Background()), PriorityBackground)
+		if err == nil {
+			rel()
+		}
+		guaranteedDone <- err
+	}()
+	waitQueued(t, q, 2)
+
+	release()
+	select {
+	case err := <-guaranteedDone:
+		if err != nil {
+			t.Fatalf("expected guaranteed operation to run, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for guaranteed operation")
+	}
+	<-queuedDone
+	waitQueued(t, q, 0)
+}
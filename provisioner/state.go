@@ -1,6 +1,7 @@
 package provisioner
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -14,13 +15,17 @@ import (
 // ParseStatefileForAgent
 //
 //	 Parses a terraform state file and returns the gigo_agent's
-//		id and token
+//		id and token. The agent outputs are used if the module declares
+//		them and the agent resource is parsed for older modules.
 func ParseStatefileForAgent(provisionerBackend backend.ProvisionerBackend, workspaceId int64) (*models.Agent, error) {
 	// retrieve state file from storage engine
 	buf, err := provisionerBackend.GetStatefile(fmt.Sprintf("states/%d", workspaceId))
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve statefile: %v", err)
 	}
+	if buf == nil {
+		return nil, fmt.Errorf("statefile does not exist")
+	}
 	defer buf.Close()
 
 	// read state file
@@ -32,8 +37,19 @@ func ParseStatefileForAgent(provisionerBackend backend.ProvisionerBackend, works
 	// close buffer
 	_ = buf.Close()
 
-	// create agent variable to hold parsed agent data
-	var agent *models.Agent
+	// prefer the outputs declared by the template since they do not
+	// depend on the addresses of the resources or the state format
+	outputs, err := parseStatefileOutputs(stateBuf)
+	if err != nil {
+		return nil, err
+	}
+	agent, err := agentFromOutputs(outputs)
+	if err == nil {
+		return agent, nil
+	}
+	if !errors.Is(err, errOutputNotFound) {
+		return nil, err
+	}
 
 	// retrieve resources
 	resourcesBuf, resourcesType, _, err := jsonparser.Get(stateBuf, "resources")
@@ -101,7 +117,9 @@ func ParseStatefileForAgent(provisionerBackend backend.ProvisionerBackend, works
 
 // ParseStatefileForWorkspaceState
 //
-//	Parses a terraform state file and returns the workspace state
+//	Parses a terraform state file and returns the workspace state.
+//	The start_count output is used if the module declares it and the
+//	gigo_workspace data source is parsed for older modules.
 func ParseStatefileForWorkspaceState(provisionerBackend backend.ProvisionerBackend, workspaceId int64) (models.WorkspaceState, error) {
 	// retrieve state file from storage engine
 	buf, err := provisionerBackend.GetStatefile(fmt.Sprintf("states/%d", workspaceId))
@@ -125,8 +143,19 @@ func ParseStatefileForWorkspaceState(provisionerBackend backend.ProvisionerBacke
 	// close buffer
 	_ = buf.Close()
 
-	// create state variable to hold parsed state
-	state := models.WorkspaceState(-1)
+	// prefer the outputs declared by the template since they do not
+	// depend on the addresses of the resources or the state format
+	outputs, err := parseStatefileOutputs(stateBuf)
+	if err != nil {
+		return -1, err
+	}
+	state, err := workspaceStateFromOutputs(outputs)
+	if err == nil {
+		return state, nil
+	}
+	if !errors.Is(err, errOutputNotFound) {
+		return -1, err
+	}
 
 	// retrieve resources
 	resourcesBuf, resourcesType, _, err := jsonparser.Get(stateBuf, "resources")
@@ -180,3 +209,100 @@ func ParseStatefileForWorkspaceState(provisionerBackend backend.ProvisionerBacke
 
 	return state, nil
 }
+
+// ParseStatefileForKubernetesResources
+//
+//	Parses a terraform state file and returns the names of the
+//	workspace's persistent volume claim and pod. Empty strings are
+//	returned for resources that do not exist in the statefile. If the
+//	persistent volume claim is not managed by the workspace module the
+//	claim name is resolved from the volumes mounted by the pod.
+func ParseStatefileForKubernetesResources(provisionerBackend backend.ProvisionerBackend, workspaceId int64) (string, string, error) {
+	// retrieve state file from storage engine
+	buf, err := provisionerBackend.GetStatefile(fmt.Sprintf("states/%d", workspaceId))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to retrieve statefile: %v", err)
+	}
+
+	// return empty names if statefile is not found
+	if buf == nil {
+		return "", "", nil
+	}
+
+	defer buf.Close()
+
+	// read state file
+	stateBuf, err := io.ReadAll(buf)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read statefile: %v", err)
+	}
+
+	// close buffer
+	_ = buf.Close()
+
+	// create variables to hold the parsed resource names
+	pvcName := ""
+	podName := ""
+	podClaimName := ""
+
+	// retrieve resources
+	resourcesBuf, resourcesType, _, err := jsonparser.Get(stateBuf, "resources")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to retrieve resources: %v", err)
+	}
+
+	// ensure that resources is an array
+	if resourcesType != jsonparser.Array {
+		return "", "", fmt.Errorf("resources is not an array")
+	}
+
+	// parse the state file for the kubernetes resources
+	jsonparser.ArrayEach(resourcesBuf, func(value []byte, dataType jsonparser.ValueType, offset int, err error) {
+		// skip non object - this should never occur
+		if dataType != jsonparser.Object {
+			return
+		}
+
+		// parse type from resource
+		resourceType, err := jsonparser.GetString(value, "type")
+		if err != nil {
+			return
+		}
+
+		switch resourceType {
+		case "kubernetes_persistent_volume_claim":
+			name, err := jsonparser.GetString(value, "instances", "[0]", "attributes", "metadata", "[0]", "name")
+			if err != nil {
+				return
+			}
+			pvcName = name
+		case "kubernetes_pod", "k8s_core_v1_pod":
+			name, err := jsonparser.GetString(value, "instances", "[0]", "attributes", "metadata", "[0]", "name")
+			if err != nil {
+				return
+			}
+			podName = name
+
+			// retrieve the claim mounted by the pod in case the pvc is not
+			// managed by the workspace module
+			volumesBuf, _, _, err := jsonparser.Get(value, "instances", "[0]", "attributes", "spec", "[0]", "volume")
+			if err != nil {
+				return
+			}
+			jsonparser.ArrayEach(volumesBuf, func(volume []byte, _ jsonparser.ValueType, _ int, _ error) {
+				claimName, err := jsonparser.GetString(volume, "persistent_volume_claim", "[0]", "claim_name")
+				if err != nil || claimName == "" {
+					return
+				}
+				podClaimName = claimName
+			})
+		}
+	})
+
+	// fallback on the claim mounted by the pod
+	if pvcName == "" {
+		pvcName = podClaimName
+	}
+
+	return pvcName, podName, nil
+}
@@ -37,6 +37,23 @@ func TestParseStatefileForAgent(t *testing.T) {
 	if agent.Token != "8d6cadd6-3469-4ff8-9543-a92a6b160c64" {
 		t.Fatal("agent token is not 8d6cadd6-3469-4ff8-9543-a92a6b160c64")
 	}
+
+	// modules that declare the agent outputs are parsed from the outputs
+	pb, err = backend.NewProvisionerBackendFS(config2.StorageFSConfig{
+		Root: basepath + "/test_data/outputs",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agent, err = ParseStatefileForAgent(pb, 420)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if agent.ID != 1620436895550922753 || agent.Token != "0f4b9c8e-7d3a-4c1e-9b2f-6a5d4e3c2b1a" {
+		t.Fatalf("unexpected agent from outputs: %+v", agent)
+	}
 }
 
 func TestParseStatefileForWorkspaceState(t *testing.T) {
@@ -75,4 +92,54 @@ func TestParseStatefileForWorkspaceState(t *testing.T) {
 	if state != models.WorkspaceStateDestroyed {
 		t.Fatal("state is invalid: ", state)
 	}
+
+	// modules that declare the start_count output are parsed from the outputs
+	pb, err = backend.NewProvisionerBackendFS(config2.StorageFSConfig{
+		Root: basepath + "/test_data/outputs",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = ParseStatefileForWorkspaceState(pb, 420)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state != models.WorkspaceStateStopped {
+		t.Fatal("state is invalid: ", state)
+	}
+}
+
+func TestParseStatefileForKubernetesResources(t *testing.T) {
+	_, b, _, _ := runtime.Caller(0)
+	basepath := strings.Replace(filepath.Dir(b), "/provisioner", "", -1)
+	pb, err := backend.NewProvisionerBackendFS(config2.StorageFSConfig{
+		Root: basepath + "/test_data/statefiles",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pvcName, podName, err := ParseStatefileForKubernetesResources(pb, 420)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pvcName != "gigo-ws-69-420-home" {
+		t.Fatal("pvc name is not gigo-ws-69-420-home: ", pvcName)
+	}
+
+	if podName != "gigo-ws-69-420" {
+		t.Fatal("pod name is not gigo-ws-69-420: ", podName)
+	}
+
+	pvcName, podName, err = ParseStatefileForKubernetesResources(pb, 422)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pvcName != "" || podName != "" {
+		t.Fatalf("expected empty names, got %q %q", pvcName, podName)
+	}
 }
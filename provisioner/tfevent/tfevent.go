@@ -0,0 +1,192 @@
+package tfevent
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Type
+//
+//	Type of a terraform machine-readable ui event
+//	https://developer.hashicorp.com/terraform/internals/machine-readable-ui
+type Type string
+
+const (
+	TypeVersion       Type = "version"
+	TypeLog           Type = "log"
+	TypePlannedChange Type = "planned_change"
+	TypeChangeSummary Type = "change_summary"
+	TypeApplyStart    Type = "apply_start"
+	TypeApplyProgress Type = "apply_progress"
+	TypeApplyComplete Type = "apply_complete"
+	TypeApplyErrored  Type = "apply_errored"
+	TypeDiagnostic    Type = "diagnostic"
+	TypeOutputs       Type = "outputs"
+)
+
+// Event
+//
+//	Machine-readable ui event emitted by terraform when executed with
+//	the -json flag. Only the fields of the event type are populated.
+type Event struct {
+	Level     string `json:"@level"`
+	Message   string `json:"@message"`
+	Module    string `json:"@module"`
+	Timestamp string `json:"@timestamp"`
+	Type      Type   `json:"type"`
+
+	// Terraform version of terraform for version events
+	Terraform string `json:"terraform,omitempty"`
+	// UI version of the ui protocol for version events
+	UI string `json:"ui,omitempty"`
+
+	// Change planned change of a resource for planned_change events
+	Change *ResourceChange `json:"change,omitempty"`
+	// Hook resource operation for apply_* events
+	Hook *Hook `json:"hook,omitempty"`
+	// Diagnostic warning or error for diagnostic events
+	Diagnostic *Diagnostic `json:"diagnostic,omitempty"`
+	// Changes summary of the changes for change_summary events
+	Changes *ChangeSummary `json:"changes,omitempty"`
+	// Outputs root module outputs for outputs events
+	Outputs map[string]Output `json:"outputs,omitempty"`
+
+	// Raw json line that the event was parsed from
+	Raw json.RawMessage `json:"-"`
+}
+
+type Resource struct {
+	Addr            string      `json:"addr"`
+	Module          string      `json:"module"`
+	Resource        string      `json:"resource"`
+	ImpliedProvider string      `json:"implied_provider"`
+	ResourceType    string      `json:"resource_type"`
+	ResourceName    string      `json:"resource_name"`
+	ResourceKey     interface{} `json:"resource_key"`
+}
+
+type ResourceChange struct {
+	Resource         Resource  `json:"resource"`
+	PreviousResource *Resource `json:"previous_resource,omitempty"`
+	Action           string    `json:"action"`
+	Reason           string    `json:"reason,omitempty"`
+}
+
+type Hook struct {
+	Resource       Resource `json:"resource"`
+	Action         string   `json:"action"`
+	IDKey          string   `json:"id_key,omitempty"`
+	IDValue        string   `json:"id_value,omitempty"`
+	ElapsedSeconds float64  `json:"elapsed_seconds,omitempty"`
+}
+
+type Pos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+type Range struct {
+	Filename string `json:"filename"`
+	Start    Pos    `json:"start"`
+	End      Pos    `json:"end"`
+}
+
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail"`
+	Address  string `json:"address,omitempty"`
+	// Range location of the diagnostic in the configuration - only
+	// set for diagnostics that originate from the configuration
+	Range *Range `json:"range,omitempty"`
+}
+
+type ChangeSummary struct {
+	Add       int    `json:"add"`
+	Change    int    `json:"change"`
+	Remove    int    `json:"remove"`
+	Operation string `json:"operation"`
+}
+
+type Output struct {
+	Sensitive bool            `json:"sensitive"`
+	Type      json.RawMessage `json:"type,omitempty"`
+	Value     json.RawMessage `json:"value,omitempty"`
+	Action    string          `json:"action,omitempty"`
+}
+
+// Known
+//
+//	Returns true if the type is one of the event types
+//	that is modelled by this package
+func (t Type) Known() bool {
+	switch t {
+	case TypeVersion, TypeLog, TypePlannedChange, TypeChangeSummary, TypeApplyStart,
+		TypeApplyProgress, TypeApplyComplete, TypeApplyErrored, TypeDiagnostic, TypeOutputs:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsError
+//
+//	Returns true if the event is an error diagnostic
+func (e *Event) IsError() bool {
+	return e.Type == TypeDiagnostic && e.Diagnostic != nil && e.Diagnostic.Severity == "error"
+}
+
+// Parse
+//
+//	Parses a single line of terraform output into an event. False is
+//	returned for empty lines and lines that are not terraform events
+//	so that callers can skip garbage in the output. Events of unknown
+//	types are returned with only the common fields populated.
+func Parse(line string) (Event, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || line[0] != '{' {
+		return Event{}, false
+	}
+
+	var e Event
+	err := json.Unmarshal([]byte(line), &e)
+	if err != nil || e.Type == "" {
+		return Event{}, false
+	}
+	e.Raw = json.RawMessage(line)
+
+	return e, true
+}
+
+// ParseAll
+//
+//	Parses every line of the terraform output skipping the
+//	lines that are not terraform events
+func ParseAll(output string) []Event {
+	events := make([]Event, 0)
+	for _, line := range strings.Split(output, "\n") {
+		if e, ok := Parse(line); ok {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// Diagnostics
+//
+//	Returns the diagnostics of the passed severity contained in the
+//	events. All diagnostics are returned if the severity is empty.
+func Diagnostics(events []Event, severity string) []Diagnostic {
+	diags := make([]Diagnostic, 0)
+	for _, e := range events {
+		if e.Type != TypeDiagnostic || e.Diagnostic == nil {
+			continue
+		}
+		if severity != "" && e.Diagnostic.Severity != severity {
+			continue
+		}
+		diags = append(diags, *e.Diagnostic)
+	}
+	return diags
+}
@@ -0,0 +1,110 @@
+package tfevent
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		ok   bool
+		typ  Type
+	}{
+		{
+			name: "empty",
+			line: "   ",
+		},
+		{
+			name: "plain text",
+			line: "Terraform has been successfully initialized!",
+		},
+		{
+			name: "truncated json",
+			line: `{"@level":"info","@message":"Terraform 1.3.7","type":"vers`,
+		},
+		{
+			name: "json without type",
+			line: `{"@level":"info","@message":"hello"}`,
+		},
+		{
+			name: "version",
+			line: `{"@level":"info","@message":"Terraform 1.3.7","@module":"terraform.ui","@timestamp":"2023-01-31T12:00:00.000000-06:00","terraform":"1.3.7","type":"version","ui":"1.0"}`,
+			ok:   true,
+			typ:  TypeVersion,
+		},
+		{
+			name: "unknown type",
+			line: `{"@level":"info","@message":"something new","@module":"terraform.ui","@timestamp":"2023-01-31T12:00:00.000000-06:00","type":"resource_drift"}`,
+			ok:   true,
+			typ:  "resource_drift",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, ok := Parse(test.line)
+			if ok != test.ok {
+				t.Fatalf("expected ok %v, got %v", test.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if e.Type != test.typ {
+				t.Errorf("expected type %s, got %s", test.typ, e.Type)
+			}
+			if string(e.Raw) != test.line {
+				t.Errorf("expected raw %s, got %s", test.line, e.Raw)
+			}
+		})
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	output := `{"@level":"info","@message":"Terraform 1.3.7","@module":"terraform.ui","@timestamp":"2023-01-31T12:00:00.000000-06:00","terraform":"1.3.7","type":"version","ui":"1.0"}
+panic: something went wrong
+{"@level":"info","@message":"kubernetes_pod.main[0]: Creating...","@module":"terraform.ui","@timestamp":"2023-01-31T12:00:01.000000-06:00","hook":{"resource":{"addr":"kubernetes_pod.main[0]","module":"","resource":"kubernetes_pod.main[0]","implied_provider":"kubernetes","resource_type":"kubernetes_pod","resource_name":"main","resource_key":0},"action":"create"},"type":"apply_start"}
+{"@level":"error","@message":"Error: failed to create pod","@module":"terraform.ui","@timestamp":"2023-01-31T12:00:20.000000-06:00","diagnostic":{"severity":"error","summary":"failed to create pod","detail":"","address":"kubernetes_pod.main[0]"},"type":"diagnostic"}
+{"@level":"info","@message":"Apply complete! Resources: 0 added, 0 changed, 0 destroyed.","@module":"terraform.ui","@timestamp":"2023-01-31T12:00:21.000000-06:00","changes":{"add":0,"change":0,"remove":0,"operation":"apply"},"type":"change_summary"}
+`
+
+	events := ParseAll(output)
+	if len(events) != 4 {
+		t.Fatalf("expected 4 events, got %d", len(events))
+	}
+
+	start := events[1]
+	if start.Type != TypeApplyStart || start.Hook == nil {
+		t.Fatalf("expected apply_start with hook, got %+v", start)
+	}
+	if start.Hook.Resource.Addr != "kubernetes_pod.main[0]" {
+		t.Errorf("expected resource kubernetes_pod.main[0], got %s", start.Hook.Resource.Addr)
+	}
+
+	if !events[2].IsError() {
+		t.Errorf("expected error diagnostic, got %+v", events[2])
+	}
+
+	summary := events[3]
+	if summary.Changes == nil || summary.Changes.Operation != "apply" {
+		t.Errorf("expected apply change summary, got %+v", summary.Changes)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	events := []Event{
+		{Type: TypeVersion},
+		{Type: TypeDiagnostic, Diagnostic: &Diagnostic{Severity: "warning", Summary: "deprecated"}},
+		{Type: TypeDiagnostic, Diagnostic: &Diagnostic{Severity: "error", Summary: "failed"}},
+		{Type: TypeDiagnostic},
+	}
+
+	if diags := Diagnostics(events, ""); len(diags) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %d", len(diags))
+	}
+
+	diags := Diagnostics(events, "error")
+	if len(diags) != 1 || diags[0].Summary != "failed" {
+		t.Fatalf("expected the error diagnostic, got %+v", diags)
+	}
+}
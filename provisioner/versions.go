@@ -0,0 +1,251 @@
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gigo-ws/metrics"
+	"gigo-ws/models"
+	"gigo-ws/provisioner/tfevent"
+
+	"github.com/gage-technologies/gigo-lib/utils"
+	"github.com/hashicorp/go-version"
+)
+
+var (
+	// ErrVersionDowngrade is returned when a module is upgraded to a version
+	// older than its pinned version since terraform refuses to read a
+	// statefile written by a newer version
+	ErrVersionDowngrade = fmt.Errorf("terraform version downgrade")
+)
+
+// DefaultVersion
+//
+//	Returns the terraform version that new modules are pinned to
+func (p *Provisioner) DefaultVersion() string {
+	return p.defaultVersion.String()
+}
+
+// ModuleVersion
+//
+//	Returns the terraform version that the module is pinned to. Modules
+//	stored before versions were pinned use the legacy version.
+func (p *Provisioner) ModuleVersion(module *models.TerraformModule) string {
+	if module.TerraformVersion == "" {
+		return p.legacyVersion.String()
+	}
+	return module.TerraformVersion
+}
+
+// binaryPath
+//
+//	Returns the path of the engine binary for the module's pinned version.
+//	The binary is only guaranteed to exist once the module is prepped.
+func (p *Provisioner) binaryPath(module *models.TerraformModule) string {
+	return filepath.Join(p.terraformDir, p.ModuleVersion(module), p.engine.Binary())
+}
+
+// install
+//
+//	Ensures that the passed version of the engine is installed in its
+//	version directory and returns the path of the binary. Versions are
+//	installed side by side so that modules pinned to different versions
+//	can be operated concurrently.
+func (p *Provisioner) install(ctx context.Context, vrs *version.Version) (string, error) {
+	p.installMu.Lock()
+	defer p.installMu.Unlock()
+
+	dir := filepath.Join(p.terraformDir, vrs.String())
+	binaryPath := filepath.Join(dir, p.engine.Binary())
+
+	// skip versions that were already verified by this process
+	if p.installed[vrs.String()] {
+		return binaryPath, nil
+	}
+
+	// check for existing binary
+	exists, err := utils.PathExists(binaryPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to check %s path: %v", p.engine.Binary(), err)
+	}
+
+	// validate existing install
+	if exists {
+		// ensure the existing binary is the correct version
+		existingVersion, err := p.engine.Version(binaryPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to get %s version: %v", p.engine.Binary(), err)
+		}
+
+		// handle a corrupted or replaced install
+		if !vrs.Equal(existingVersion) {
+			// exit with error if we don't have overwrite privileges
+			if !p.overwrite {
+				return "", fmt.Errorf(
+					"installed %s version %s in %s does not match %s and overwrite is disabled",
+					p.engine.Binary(), existingVersion, dir, vrs,
+				)
+			}
+
+			// remove existing install and overwrite `exists` to trigger new install
+			err = os.Remove(binaryPath)
+			if err != nil {
+				return "", fmt.Errorf("failed to remove existing %s binary: %v", p.engine.Binary(), err)
+			}
+			exists = false
+		}
+	}
+
+	if !exists {
+		p.logger.Infof("installing %s %s into %s", p.engine.Binary(), vrs, dir)
+
+		// ensure version directory exists
+		err = os.MkdirAll(dir, 0700)
+		if err != nil {
+			return "", fmt.Errorf("failed to create %s version directory: %v", p.engine.Binary(), err)
+		}
+
+		// perform install via the engine's installer
+		err = p.engine.Install(ctx, vrs, dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to install %s %s: %v", p.engine.Binary(), vrs, err)
+		}
+
+		// ensure the installed binary is the expected version since
+		// a local archive may contain a different release
+		installedVersion, err := p.engine.Version(binaryPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to get installed %s version: %v", p.engine.Binary(), err)
+		}
+		if !vrs.Equal(installedVersion) {
+			return "", fmt.Errorf("installed %s version %s does not match %s", p.engine.Binary(), installedVersion, vrs)
+		}
+	}
+
+	p.installed[vrs.String()] = true
+
+	return binaryPath, nil
+}
+
+// migrateLegacyInstall
+//
+//	Moves a binary installed at the root of the terraform directory by
+//	previous releases into the directory of its version so that it is
+//	reused by the modules pinned to that version
+func migrateLegacyInstall(dir string, engine Engine) error {
+	legacyPath := filepath.Join(dir, engine.Binary())
+
+	info, err := os.Stat(legacyPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to check legacy install: %v", err)
+	}
+	if info.IsDir() {
+		return nil
+	}
+
+	vrs, err := engine.Version(legacyPath)
+	if err != nil {
+		return fmt.Errorf("failed to get legacy install version: %v", err)
+	}
+
+	versionDir := filepath.Join(dir, vrs.String())
+	err = os.MkdirAll(versionDir, 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create version directory: %v", err)
+	}
+
+	// drop the legacy binary if the version was already installed
+	exists, err := utils.PathExists(filepath.Join(versionDir, engine.Binary()))
+	if err != nil {
+		return fmt.Errorf("failed to check version directory: %v", err)
+	}
+	if exists {
+		return os.Remove(legacyPath)
+	}
+
+	return os.Rename(legacyPath, filepath.Join(versionDir, engine.Binary()))
+}
+
+// UpgradeModule
+//
+//	Upgrades the terraform version that the module is pinned to. A
+//	refresh-only apply is performed with the new version so that the
+//	statefile is rewritten by it and any incompatibility surfaces here
+//	instead of during a later operation. The module is left pinned to
+//	its previous version if the upgrade fails. The caller is responsible
+//	for storing the module once the upgrade succeeds.
+func (p *Provisioner) UpgradeModule(ctx context.Context, module *models.TerraformModule, vrs *version.Version) (*ApplyLogs, error) {
+	previous := p.ModuleVersion(module)
+	p.logger.Debugf("upgrading module %d from %s to %s", module.ModuleID, previous, vrs)
+
+	current, err := version.NewVersion(previous)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse module version: %v", err)
+	}
+
+	if vrs.LessThan(current) {
+		return nil, fmt.Errorf("%w: module %d is pinned to %s", ErrVersionDowngrade, module.ModuleID, current)
+	}
+
+	// wait for a slot to run the upgrade
+	release, err := p.acquireOperation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// the module directory must be initialized again by the new version
+	if module.LocalPath != "" {
+		err = os.Remove(filepath.Join(module.LocalPath, initMarkerFile))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to reset module init state: %v", err)
+		}
+	}
+
+	module.TerraformVersion = vrs.String()
+	success := false
+	defer func() {
+		if !success {
+			module.TerraformVersion = previous
+			if module.LocalPath != "" {
+				_ = os.Remove(filepath.Join(module.LocalPath, initMarkerFile))
+			}
+		}
+	}()
+
+	// prep module
+	err = p.prepModule(ctx, module)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare module: %w", err)
+	}
+
+	// rewrite the statefile with the new version
+	res, oplog, err := p.runCommand(
+		ctx, module, "upgrade",
+		fmt.Sprintf(
+			"%s -chdir=%s apply -refresh-only -json -auto-approve -no-color -input=false",
+			p.binaryPath(module), module.LocalPath,
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply terraform module: %v", err)
+	}
+	metrics.ObserveTerraform("apply", res.ExitCode, res.Cost)
+
+	applyResult := &ApplyLogs{
+		Events: tfevent.ParseAll(res.Stdout),
+	}
+
+	if res.ExitCode != 0 {
+		return nil, newTerraformError("apply", res, oplog, applyResult.Events)
+	}
+
+	success = true
+
+	return applyResult, nil
+}
@@ -0,0 +1,146 @@
+package provisioner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gigo-ws/models"
+
+	"github.com/gage-technologies/gigo-lib/logging"
+	"github.com/hashicorp/go-version"
+)
+
+// testVersionEngine
+//
+//	Engine that writes its version into the installed binary
+//	so that installs can be verified without a download
+type testVersionEngine struct {
+	installs int
+}
+
+func (e *testVersionEngine) Type() models.IaCEngineType {
+	return models.IaCEngineTerraform
+}
+
+func (e *testVersionEngine) Binary() string {
+	return "terraform"
+}
+
+func (e *testVersionEngine) Install(_ context.Context, vrs *version.Version, dir string) error {
+	e.installs++
+	return os.WriteFile(filepath.Join(dir, e.Binary()), []byte(vrs.String()), 0700)
+}
+
+func (e *testVersionEngine) Version(binary string) (*version.Version, error) {
+	buf, err := os.ReadFile(binary)
+	if err != nil {
+		return nil, err
+	}
+	return version.NewVersion(strings.TrimSpace(string(buf)))
+}
+
+func TestInstallVersions(t *testing.T) {
+	dir, err := os.MkdirTemp("", "gigo-ws-versions-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	logger, err := logging.CreateBasicLogger(logging.NewDefaultBasicLoggerOptions(filepath.Join(dir, "test.log")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engine := &testVersionEngine{}
+
+	// a binary installed by a previous release is moved into its version directory
+	err = os.WriteFile(filepath.Join(dir, "terraform"), []byte("1.3.7"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = migrateLegacyInstall(dir, engine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "1.3.7", "terraform")); err != nil {
+		t.Fatalf("expected legacy binary to be migrated: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "terraform")); !os.IsNotExist(err) {
+		t.Fatalf("expected legacy binary to be removed, got %v", err)
+	}
+
+	v137, _ := version.NewVersion("1.3.7")
+	v150, _ := version.NewVersion("1.5.0")
+
+	p := &Provisioner{
+		engine:         engine,
+		terraformDir:   dir,
+		defaultVersion: v150,
+		legacyVersion:  v137,
+		installed:      make(map[string]bool),
+		logger:         logger,
+	}
+
+	// the migrated binary is reused
+	binary, err := p.install(context.Background(), v137)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binary != filepath.Join(dir, "1.3.7", "terraform") || engine.installs != 0 {
+		t.Fatalf("expected migrated binary to be reused, got %s after %d installs", binary, engine.installs)
+	}
+
+	// versions are installed side by side exactly once
+	for i := 0; i < 2; i++ {
+		_, err = p.install(context.Background(), v150)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if engine.installs != 1 {
+		t.Fatalf("expected 1 install, got %d", engine.installs)
+	}
+
+	// unpinned modules use the legacy version and pinned modules their own
+	legacy := &models.TerraformModule{ModuleID: 1}
+	pinned := &models.TerraformModule{ModuleID: 2, TerraformVersion: p.DefaultVersion()}
+	if p.binaryPath(legacy) != filepath.Join(dir, "1.3.7", "terraform") {
+		t.Fatalf("unexpected legacy binary: %s", p.binaryPath(legacy))
+	}
+	if p.binaryPath(pinned) != filepath.Join(dir, "1.5.0", "terraform") {
+		t.Fatalf("unexpected pinned binary: %s", p.binaryPath(pinned))
+	}
+
+	// a mismatched binary is only replaced with overwrite privileges
+	v160, _ := version.NewVersion("1.6.0")
+	err = os.MkdirAll(filepath.Join(dir, "1.6.0"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "1.6.0", "terraform"), []byte("1.5.0"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = p.install(context.Background(), v160)
+	if err == nil {
+		t.Fatal("expected error for mismatched binary without overwrite")
+	}
+	p.overwrite = true
+	_, err = p.install(context.Background(), v160)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// modules cannot be downgraded below their pinned version
+	_, err = p.UpgradeModule(context.Background(), pinned, v137)
+	if !errors.Is(err, ErrVersionDowngrade) {
+		t.Fatalf("expected downgrade error, got %v", err)
+	}
+	if pinned.TerraformVersion != "1.5.0" {
+		t.Fatalf("expected module to remain pinned to 1.5.0, got %s", pinned.TerraformVersion)
+	}
+}
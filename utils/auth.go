@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	// AuthMetadataAuthorization metadata key carrying a bearer token
+	AuthMetadataAuthorization = "authorization"
+	// AuthMetadataClientID metadata key carrying the id of a client signing with hmac
+	AuthMetadataClientID = "x-gigo-client-id"
+	// AuthMetadataTimestamp metadata key carrying the unix timestamp included in the hmac signature
+	AuthMetadataTimestamp = "x-gigo-timestamp"
+	// AuthMetadataNonce metadata key carrying the unique nonce included in the hmac signature
+	AuthMetadataNonce = "x-gigo-nonce"
+	// AuthMetadataSignature metadata key carrying the hex encoded hmac signature
+	AuthMetadataSignature = "x-gigo-signature"
+)
+
+// SignRPC
+//
+//	Computes the hex encoded HMAC-SHA256 signature of an rpc call. The
+//	signature covers the fully qualified rpc name, the timestamp, the
+//	nonce and the SHA256 digest of the encoded request message separated
+//	by newlines so that a signature cannot be used with another request.
+func SignRPC(secret string, rpc string, timestamp string, nonce string, body []byte) string {
+	digest := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(rpc + "\n" + timestamp + "\n" + nonce + "\n" + hex.EncodeToString(digest[:])))
+	return hex.EncodeToString(mac.Sum(nil))
+}
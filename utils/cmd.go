@@ -5,9 +5,17 @@ import (
 	"fmt"
 	"github.com/go-cmd/cmd"
 	"strings"
+	"syscall"
 	"time"
 )
 
+// InterruptGracePeriod is the duration that a command is given to exit
+// after being interrupted before it is forcefully stopped
+var InterruptGracePeriod = time.Minute * 2
+
+// stderrLineBufferSize is the maximum length of a line streamed by ExecuteCommandStderr
+const stderrLineBufferSize = 1024 * 256
+
 type CommandResult struct {
 	Command  string
 	Stdout   string
@@ -38,8 +46,8 @@ func ExecuteCommand(ctx context.Context, env []string, dir string, binary string
 	// wait for command or context
 	select {
 	case <-ctx.Done():
-		// stop command since we are exiting early
-		err := c.Stop()
+		// interrupt command since we are exiting early
+		err := interruptCommand(c, statusChan)
 		return nil, fmt.Errorf("context closed - %v", err)
 	case status := <-statusChan:
 		// load data from status by retrieving the last
@@ -122,8 +130,8 @@ func ExecuteCommandStream(ctx context.Context, env []string, stdOut chan string,
 	// wait for command or context
 	select {
 	case <-ctx.Done():
-		// stop command since we are exiting early
-		err := c.Stop()
+		// interrupt command since we are exiting early
+		err := interruptCommand(c, statusChan)
 		// wait for streams to close
 		<-done
 		return nil, fmt.Errorf("context closed - %v", err)
@@ -145,3 +153,116 @@ func ExecuteCommandStream(ctx context.Context, env []string, stdOut chan string,
 		}, nil
 	}
 }
+
+// ExecuteCommandStderr
+//
+//	Helper function to execute commands safely via the
+//	github.com/go-cmd/cmd library buffering stdout while passing
+//	each line of stderr to the stderr callback as it is written.
+//	The Stderr field of the returned result is left empty so that
+//	the caller decides how much of the error output is retained.
+func ExecuteCommandStderr(ctx context.Context, env []string, dir string, stderr func(line string), binary string,
+	args ...string) (*CommandResult, error) {
+	// create a new command using streaming API
+	c := cmd.NewCmdOptions(cmd.Options{
+		Buffered:  false,
+		Streaming: true,
+		// terraform emits each diagnostic as a single json line
+		// which can exceed the default line buffer of go-cmd
+		LineBufferSize: stderrLineBufferSize,
+	}, binary, args...)
+	c.Env = env
+
+	// conditionally set the working directory
+	if len(dir) > 0 {
+		c.Dir = dir
+	}
+
+	// collect stdout lines while streaming
+	stdOut := make([]string, 0)
+
+	// create channel to track done
+	done := make(chan struct{})
+
+	// launch go func to handle streaming
+	go func() {
+		// defer closure to mark completion of streaming
+		defer close(done)
+
+		// Done when both channels have been closed
+		// https://dave.cheney.net/2013/04/30/curious-channels
+		for c.Stdout != nil || c.Stderr != nil {
+			select {
+			case line, ok := <-c.Stdout:
+				// set stdout channel nil when we close
+				if !ok {
+					c.Stdout = nil
+					continue
+				}
+				stdOut = append(stdOut, line)
+			case line, ok := <-c.Stderr:
+				// set stderr channel nil when we close
+				if !ok {
+					c.Stderr = nil
+					continue
+				}
+				stderr(line)
+			}
+		}
+	}()
+
+	// start command
+	statusChan := c.Start()
+
+	// wait for command or context
+	select {
+	case <-ctx.Done():
+		// interrupt command since we are exiting early
+		err := interruptCommand(c, statusChan)
+		// wait for streams to close
+		<-done
+		return nil, fmt.Errorf("context closed - %v", err)
+	case status := <-statusChan:
+		// wait for streams to close
+		<-done
+
+		// format the start and end time from the timestamps
+		start := time.Unix(0, status.StartTs)
+		end := time.Unix(0, status.StopTs)
+
+		return &CommandResult{
+			Command:  strings.Join(append([]string{binary}, args...), " "),
+			Stdout:   strings.Join(stdOut, "\n"),
+			ExitCode: status.Exit,
+			Start:    start,
+			End:      end,
+			Cost:     end.Sub(start),
+		}, nil
+	}
+}
+
+// interruptCommand
+//
+//	Sends SIGINT to the process group of the command so that it can
+//	exit gracefully - terraform uses this to finish in-flight resource
+//	operations and persist its state. The command is forcefully stopped
+//	if it has not exited within the InterruptGracePeriod.
+func interruptCommand(c *cmd.Cmd, statusChan <-chan cmd.Status) error {
+	// signal the process group (-pid) so that the children of the
+	// shell that wraps the command receive the interrupt
+	pid := c.Status().PID
+	if pid <= 0 {
+		return c.Stop()
+	}
+	err := syscall.Kill(-pid, syscall.SIGINT)
+	if err != nil {
+		return c.Stop()
+	}
+
+	select {
+	case status := <-statusChan:
+		return fmt.Errorf("command interrupted with exit code %d", status.Exit)
+	case <-time.After(InterruptGracePeriod):
+		return c.Stop()
+	}
+}
@@ -180,3 +180,59 @@ func TestExecuteCommandStream(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteCommandInterrupt(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond*200)
+	defer cancel()
+
+	// the command traps the interrupt and exits gracefully with a distinct code
+	start := time.Now()
+	_, err := ExecuteCommand(ctx, nil, "", "bash", "-c", "trap 'exit 42' INT; while true; do sleep 0.05; done")
+	if err == nil {
+		t.Fatal("expected error from interrupted command")
+	}
+
+	if !strings.Contains(err.Error(), "exit code 42") {
+		t.Fatalf("expected graceful exit code in error, got: %v", err)
+	}
+
+	if time.Since(start) > InterruptGracePeriod {
+		t.Fatal("command was not interrupted before the grace period")
+	}
+}
+
+func TestExecuteCommandStderr(t *testing.T) {
+	t.Parallel()
+
+	buf := NewLineRingBuffer(8)
+	out, err := ExecuteCommandStderr(
+		context.TODO(), nil, "", buf.WriteLine,
+		"bash", "-c", "echo foo; echo bar; echo one 1>&2; echo two 1>&2; echo three 1>&2; exit 3",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.ExitCode != 3 {
+		t.Errorf("expected 3, got %v", out.ExitCode)
+	}
+
+	if out.Stdout != "foo\nbar" {
+		t.Errorf("expected %q, got %q", "foo\nbar", out.Stdout)
+	}
+
+	if out.Stderr != "" {
+		t.Errorf("expected empty stderr, got %q", out.Stderr)
+	}
+
+	// only the trailing lines that fit within the limit are retained
+	if buf.String() != "two\nthree" {
+		t.Errorf("expected %q, got %q", "two\nthree", buf.String())
+	}
+
+	if buf.Dropped() != 1 {
+		t.Errorf("expected 1 dropped line, got %d", buf.Dropped())
+	}
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"strings"
+	"sync"
+)
+
+// LineRingBuffer
+//
+//	Size-bounded buffer of the most recent lines written to it. Once
+//	the total size of the retained lines exceeds the limit the oldest
+//	lines are dropped so that the memory used to track the output of a
+//	long-running command is constant regardless of how verbose it is.
+type LineRingBuffer struct {
+	mu      sync.Mutex
+	lines   []string
+	start   int
+	size    int
+	limit   int
+	dropped int
+}
+
+// NewLineRingBuffer
+//
+//	Creates a LineRingBuffer that retains at most limit bytes of lines
+func NewLineRingBuffer(limit int) *LineRingBuffer {
+	return &LineRingBuffer{
+		limit: limit,
+	}
+}
+
+// WriteLine
+//
+//	Appends a line to the buffer dropping the oldest lines if the
+//	buffer exceeds its limit. A line larger than the limit is cut
+//	down to its trailing bytes.
+func (b *LineRingBuffer) WriteLine(line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(line) > b.limit {
+		line = line[len(line)-b.limit:]
+	}
+
+	b.lines = append(b.lines, line)
+	b.size += len(line)
+
+	for b.size > b.limit {
+		b.size -= len(b.lines[b.start])
+		b.lines[b.start] = ""
+		b.start++
+		b.dropped++
+	}
+
+	// compact the backing slice once half of it holds dropped lines
+	if b.start > len(b.lines)/2 {
+		b.lines = append(b.lines[:0], b.lines[b.start:]...)
+		b.start = 0
+	}
+}
+
+// Lines
+//
+//	Returns a copy of the lines retained by the buffer
+func (b *LineRingBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	lines := make([]string, len(b.lines)-b.start)
+	copy(lines, b.lines[b.start:])
+	return lines
+}
+
+// Dropped
+//
+//	Returns the number of lines that were dropped to stay within the limit
+func (b *LineRingBuffer) Dropped() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
+
+func (b *LineRingBuffer) String() string {
+	return strings.Join(b.Lines(), "\n")
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLineRingBuffer(t *testing.T) {
+	buf := NewLineRingBuffer(10)
+
+	for _, line := range []string{"aaaa", "bbbb", "cccc", "dd"} {
+		buf.WriteLine(line)
+	}
+
+	if buf.String() != "bbbb\ncccc\ndd" {
+		t.Fatalf("unexpected buffer contents: %q", buf.String())
+	}
+	if buf.Dropped() != 1 {
+		t.Fatalf("expected 1 dropped line, got %d", buf.Dropped())
+	}
+
+	// lines larger than the limit keep their trailing bytes
+	buf.WriteLine(strings.Repeat("x", 5) + "0123456789")
+	if buf.String() != "0123456789" {
+		t.Fatalf("unexpected buffer contents: %q", buf.String())
+	}
+
+	// the buffer stays bounded over many writes
+	for i := 0; i < 1000; i++ {
+		buf.WriteLine("z")
+	}
+	if len(buf.Lines()) != 10 || len(buf.lines) > 21 {
+		t.Fatalf("expected 10 retained lines within a compacted slice, got %d of %d", len(buf.Lines()), len(buf.lines))
+	}
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"embed"
 	"fmt"
-	"os"
+	"regexp"
 	"strings"
 
 	"gigo-ws/config"
+	"gigo-ws/metrics"
 	models2 "gigo-ws/models"
 	"gigo-ws/provisioner"
 
@@ -22,6 +23,9 @@ import (
 //go:embed resources
 var embedFS embed.FS
 
+// volumeSizeRegex matches the storage request of the volume template
+var volumeSizeRegex = regexp.MustCompile(`storage\s*=\s*"[^"]*"`)
+
 type VolumePoolParams struct {
 	// DB Database used to store the volume pool state
 	DB *ti.Database
@@ -166,6 +170,83 @@ func (p *VolumePool) DestroyWorkspaceVolumes(workspaceId int64) error {
 	return nil
 }
 
+// ResizeWorkspaceVolume
+//
+//	Expands the volumes associated with the passed workspace id to the
+//	passed size in gigabytes. Volumes that are already at least as large
+//	are left untouched. The storage class of the volume must allow volume
+//	expansion. Volumes can never shrink since they contain user data.
+func (p *VolumePool) ResizeWorkspaceVolume(ctx context.Context, workspaceId int64, size int) error {
+	// query for the volumes associated with the workspace
+	res, err := p.DB.DB.Query("select * from volpool_volume where workspace_id = ?", workspaceId)
+	if err != nil {
+		return fmt.Errorf("error querying for workspace volumes: %v", err)
+	}
+	defer res.Close()
+
+	// create slice to hold the volumes
+	vols := make([]*models.VolpoolVolume, 0)
+
+	// iterate over the results
+	for res.Next() {
+		// load the volume
+		vol, err := models.VolpoolVolumeFromSqlNative(res)
+		if err != nil {
+			return fmt.Errorf("error loading volume: %v", err)
+		}
+
+		// append the volume to the slice
+		vols = append(vols, vol)
+	}
+
+	_ = res.Close()
+
+	if len(vols) == 0 {
+		return fmt.Errorf("no volumes found for workspace %d", workspaceId)
+	}
+
+	for _, vol := range vols {
+		if vol.Size >= size {
+			continue
+		}
+
+		// load the module of the volume
+		module, err := models2.LoadModule(p.StorageEngine, vol.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load module: %v", err)
+		}
+		if module == nil {
+			return fmt.Errorf("module for volume %d not found", vol.ID)
+		}
+
+		// rewrite the storage request of the volume
+		if !volumeSizeRegex.Match(module.MainTF) {
+			return fmt.Errorf("module for volume %d has no storage request", vol.ID)
+		}
+		module.MainTF = volumeSizeRegex.ReplaceAll(module.MainTF, []byte(fmt.Sprintf(`storage = "%dGi"`, size)))
+
+		// perform apply operation to expand the claim
+		_, err = p.Provisioner.Apply(ctx, module)
+		if err != nil {
+			return fmt.Errorf("failed to apply configuration: %v", err)
+		}
+
+		// preserve module for later operations
+		err = module.StoreModule(p.StorageEngine)
+		if err != nil {
+			return fmt.Errorf("failed to store module: %v", err)
+		}
+
+		// update the size of the volume in the database
+		_, err = p.DB.DB.Exec("update volpool_volume set size = ? where _id = ?", size, vol.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update volume: %v", err)
+		}
+	}
+
+	return nil
+}
+
 // ResolveStateDeltas
 //
 //	Public method to resolve state deltas which can be called from outside the pool.
@@ -178,6 +259,38 @@ func (p *VolumePool) ResolveStateDeltas() {
 	})
 }
 
+// VolumeCounts
+//
+//	Returns the count of available and in use volumes for each
+//	configured subpool
+func (p *VolumePool) VolumeCounts() ([]metrics.VolumePoolCount, error) {
+	counts := make([]metrics.VolumePoolCount, 0, len(p.Config.SubPools))
+
+	for _, subpool := range p.Config.SubPools {
+		count := metrics.VolumePoolCount{Size: subpool.VolumeSize}
+
+		err := p.DB.DB.QueryRow(
+			"select count(*) from volpool_volume where size = ? and state = ?",
+			subpool.VolumeSize, models.VolumeStateAvailable,
+		).Scan(&count.Available)
+		if err != nil {
+			return nil, fmt.Errorf("error querying for available volumes for subpool %d: %v", subpool.VolumeSize, err)
+		}
+
+		err = p.DB.DB.QueryRow(
+			"select count(*) from volpool_volume where size = ? and state = ?",
+			subpool.VolumeSize, models.VolumeStateInUse,
+		).Scan(&count.InUse)
+		if err != nil {
+			return nil, fmt.Errorf("error querying for in use volumes for subpool %d: %v", subpool.VolumeSize, err)
+		}
+
+		counts = append(counts, count)
+	}
+
+	return counts, nil
+}
+
 // resolveStateDeltas
 //
 //	Compares the configuration against the current state of the volume pool
@@ -298,18 +411,13 @@ func (p *VolumePool) provisionVolume(vol *models.VolpoolVolume) error {
 	}
 	template = strings.ReplaceAll(template, "<STORAGE_CLASS>", sclass)
 
-	// initialize environment with our current environment
-	// this is really important for k8s deployment because
-	// the k8s api server config is in the container's environment
-	// and we won't be able to provision unless we have access to
-	// that configuration within the provisioner commands
-	env := os.Environ()
-
-	// format module with terraform template
+	// format module with terraform template - the provisioner
+	// supplies the process environment when it runs the module
 	module := &models2.TerraformModule{
-		MainTF:      []byte(template),
-		ModuleID:    vol.ID,
-		Environment: env,
+		MainTF:   []byte(template),
+		ModuleID: vol.ID,
+		// pin the volume to the current terraform version for its destroy
+		TerraformVersion: p.Provisioner.DefaultVersion(),
 	}
 
 	// create boolean to track failure
@@ -319,7 +427,8 @@ func (p *VolumePool) provisionVolume(vol *models.VolpoolVolume) error {
 	// defer cleanup function to destroy resource on failure
 	defer func() {
 		if failed {
-			_, err := p.Provisioner.Destroy(context.TODO(), module)
+			ctx := provisioner.WithGuaranteedSlot(provisioner.WithPriority(context.TODO(), provisioner.PriorityBackground))
+			_, err := p.Provisioner.Destroy(ctx, module)
 			if err != nil {
 				p.Logger.Error(fmt.Errorf("failed to destroy volume on create cleanup: %v", err))
 			}
@@ -327,8 +436,8 @@ func (p *VolumePool) provisionVolume(vol *models.VolpoolVolume) error {
 		return
 	}()
 
-	// perform apply operation
-	_, err = p.Provisioner.Apply(context.TODO(), module)
+	// perform apply operation - pool upkeep yields to user operations
+	_, err = p.Provisioner.Apply(provisioner.WithPriority(context.TODO(), provisioner.PriorityBackground), module)
 	if err != nil {
 		return fmt.Errorf("failed to apply configuration: %v", err)
 	}
@@ -378,7 +487,7 @@ func (p *VolumePool) destroyVolume(volId int64) error {
 	}
 
 	// perform destroy operation
-	_, err = p.Provisioner.Destroy(context.Background(), module)
+	_, err = p.Provisioner.Destroy(provisioner.WithPriority(context.Background(), provisioner.PriorityBackground), module)
 	if err != nil {
 		return fmt.Errorf("failed to destroy configuration: %v", err)
 	}